@@ -44,6 +44,7 @@ func main() {
 	app.Commands = append(app.Commands, pkg.KeysCmd(prompter))
 	app.Commands = append(app.Commands, pkg.EigenPodCmd(prompter))
 	app.Commands = append(app.Commands, pkg.UserCmd(prompter))
+	app.Commands = append(app.Commands, pkg.OperatorSetsCmd(prompter))
 
 	if err := app.Run(os.Args); err != nil {
 		_, err := fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,476 @@
+package batchreader
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/multicall"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	strategy "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IStrategy"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Reader reads the allocation state of an operator through Multicall3. All reads are made at the block
+// the reader was created at, so the allocations, magnitudes and shares it returns are consistent with
+// each other.
+type Reader struct {
+	ethClient                *ethclient.Client
+	multicall                *multicall.Client
+	allocationManagerAddress gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+	allocationManagerAbi     *abi.ABI
+	delegationManagerAbi     *abi.ABI
+	strategyAbi              *abi.ABI
+}
+
+// NewReader creates a reader which reads at the current block. A batchSize or maxConcurrency of 0 uses the
+// Multicall3 defaults.
+func NewReader(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	delegationManagerAddress gethcommon.Address,
+	batchSize int,
+	maxConcurrency int,
+	logger logging.Logger,
+) (*Reader, error) {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress: delegationManagerAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+	allocationManagerAbi, err := allocationmanager.ContractAllocationManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	delegationManagerAbi, err := delegationmanager.ContractDelegationManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	strategyAbi, err := strategy.ContractIStrategyMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	currentBlock, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get current block number", err)
+	}
+	blockNumber := new(big.Int).SetUint64(currentBlock)
+
+	opts := []multicall.Option{
+		multicall.WithBatchSize(batchSize),
+		multicall.WithMaxConcurrency(maxConcurrency),
+	}
+	deployed, err := multicall.IsDeployed(ctx, ethClient, blockNumber)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to check Multicall3 deployment", err)
+	}
+	if !deployed {
+		logger.Warnf("Multicall3 is not deployed at %s, reading without batching", multicall.Multicall3Address)
+		opts = append(opts, multicall.WithoutAggregation())
+	}
+	logger.Debugf("Reading allocations at block %d", currentBlock)
+
+	return &Reader{
+		ethClient:                ethClient,
+		multicall:                multicall.NewClient(ethClient, blockNumber, opts...),
+		allocationManagerAddress: contractBindings.AllocationManagerAddr,
+		delegationManagerAddress: contractBindings.DelegationManagerAddr,
+		allocationManagerAbi:     allocationManagerAbi,
+		delegationManagerAbi:     delegationManagerAbi,
+		strategyAbi:              strategyAbi,
+	}, nil
+}
+
+// BlockNumber returns the block all reads are made at
+func (r *Reader) BlockNumber() uint64 {
+	return r.multicall.BlockNumber().Uint64()
+}
+
+// DelegationManagerAddress returns the address of the DelegationManager the reader reads from
+func (r *Reader) DelegationManagerAddress() gethcommon.Address {
+	return r.delegationManagerAddress
+}
+
+// call makes the method call once for each set of args, batched through Multicall3, and returns the
+// outputs of each call in the order of args
+func (r *Reader) call(
+	ctx context.Context,
+	target gethcommon.Address,
+	contractAbi *abi.ABI,
+	method string,
+	args [][]interface{},
+) ([][]interface{}, error) {
+	calls := make([]multicall.Call, len(args))
+	for i := range args {
+		call, err := multicall.NewCall(target, contractAbi, method, args[i]...)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = call
+	}
+	results, err := r.multicall.Aggregate(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	outputs := make([][]interface{}, len(results))
+	for i, result := range results {
+		outputs[i], err = result.Unpack(contractAbi, method)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+func (r *Reader) callAllocationManager(
+	ctx context.Context,
+	method string,
+	args ...interface{},
+) ([]interface{}, error) {
+	outputs, err := r.call(ctx, r.allocationManagerAddress, r.allocationManagerAbi, method, [][]interface{}{args})
+	if err != nil {
+		return nil, err
+	}
+	return outputs[0], nil
+}
+
+func (r *Reader) GetMaxMagnitudes(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]uint64, error) {
+	out, err := r.callAllocationManager(ctx, "getMaxMagnitudes0", operatorAddress, strategyAddresses)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]uint64)).(*[]uint64), nil
+}
+
+func (r *Reader) GetAllocatableMagnitude(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) (uint64, error) {
+	magnitudes, err := r.GetAllocatableMagnitudes(ctx, operatorAddress, []gethcommon.Address{strategyAddress})
+	if err != nil {
+		return 0, err
+	}
+	return magnitudes[strategyAddress], nil
+}
+
+// GetAllocatableMagnitudes returns the allocatable magnitude of each strategy
+func (r *Reader) GetAllocatableMagnitudes(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) (map[gethcommon.Address]uint64, error) {
+	args := make([][]interface{}, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		args[i] = []interface{}{operatorAddress, strategyAddress}
+	}
+	outputs, err := r.call(ctx, r.allocationManagerAddress, r.allocationManagerAbi, "getAllocatableMagnitude", args)
+	if err != nil {
+		return nil, err
+	}
+	magnitudes := make(map[gethcommon.Address]uint64, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		magnitudes[strategyAddress] = *abi.ConvertType(outputs[i][0], new(uint64)).(*uint64)
+	}
+	return magnitudes, nil
+}
+
+func (r *Reader) GetEncumberedMagnitude(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) (uint64, error) {
+	out, err := r.callAllocationManager(ctx, "getEncumberedMagnitude", operatorAddress, strategyAddress)
+	if err != nil {
+		return 0, err
+	}
+	return *abi.ConvertType(out[0], new(uint64)).(*uint64), nil
+}
+
+func (r *Reader) GetAllocationInfo(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) ([]elcontracts.AllocationInfo, error) {
+	allocations, err := r.GetAllocationInfos(ctx, operatorAddress, []gethcommon.Address{strategyAddress})
+	if err != nil {
+		return nil, err
+	}
+	return allocations[strategyAddress], nil
+}
+
+// GetAllocationInfos returns the allocations of each strategy
+func (r *Reader) GetAllocationInfos(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) (map[gethcommon.Address][]elcontracts.AllocationInfo, error) {
+	args := make([][]interface{}, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		args[i] = []interface{}{operatorAddress, strategyAddress}
+	}
+	outputs, err := r.call(ctx, r.allocationManagerAddress, r.allocationManagerAbi, "getStrategyAllocations", args)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make(map[gethcommon.Address][]elcontracts.AllocationInfo, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		opSets := *abi.ConvertType(outputs[i][0], new([]allocationmanager.OperatorSet)).(*[]allocationmanager.OperatorSet)
+		allocationInfo := *abi.ConvertType(
+			outputs[i][1],
+			new([]allocationmanager.IAllocationManagerTypesAllocation),
+		).(*[]allocationmanager.IAllocationManagerTypesAllocation)
+
+		allocationsInfo := make([]elcontracts.AllocationInfo, len(opSets))
+		for j, opSet := range opSets {
+			allocationsInfo[j] = elcontracts.AllocationInfo{
+				OperatorSetId:    opSet.Id,
+				AvsAddress:       opSet.Avs,
+				CurrentMagnitude: new(big.Int).SetUint64(allocationInfo[j].CurrentMagnitude),
+				PendingDiff:      allocationInfo[j].PendingDiff,
+				EffectBlock:      allocationInfo[j].EffectBlock,
+			}
+		}
+		allocations[strategyAddress] = allocationsInfo
+	}
+	return allocations, nil
+}
+
+func (r *Reader) GetSlashableShares(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategies []gethcommon.Address,
+) (map[gethcommon.Address]*big.Int, error) {
+	slashableShares, err := r.GetSlashableSharesForSets(
+		ctx,
+		operatorAddress,
+		[]allocationmanager.OperatorSet{operatorSet},
+		strategies,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return slashableShares[0], nil
+}
+
+// GetSlashableSharesForSets returns the slashable shares of the operator in each strategy for each of the
+// operator sets, in the order of the operator sets
+func (r *Reader) GetSlashableSharesForSets(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSets []allocationmanager.OperatorSet,
+	strategies []gethcommon.Address,
+) ([]map[gethcommon.Address]*big.Int, error) {
+	blockNumber := uint32(r.BlockNumber())
+	args := make([][]interface{}, len(operatorSets))
+	for i, operatorSet := range operatorSets {
+		args[i] = []interface{}{operatorSet, []gethcommon.Address{operatorAddress}, strategies, blockNumber}
+	}
+	outputs, err := r.call(ctx, r.allocationManagerAddress, r.allocationManagerAbi, "getMinimumSlashableStake", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[gethcommon.Address]*big.Int, len(operatorSets))
+	for i := range operatorSets {
+		slashableShares := *abi.ConvertType(outputs[i][0], new([][]*big.Int)).(*[][]*big.Int)
+		if len(slashableShares) == 0 {
+			return nil, errors.New("no slashable shares found for operator")
+		}
+		result[i] = make(map[gethcommon.Address]*big.Int, len(strategies))
+		for j, strategy := range strategies {
+			// We only query a single operator, so its shares are the first row
+			result[i][strategy] = slashableShares[0][j]
+		}
+	}
+	return result, nil
+}
+
+func (r *Reader) IsOperatorSlashable(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	out, err := r.callAllocationManager(ctx, "isOperatorSlashable", operatorAddress, operatorSet)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+func (r *Reader) GetOperatorShares(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]*big.Int, error) {
+	outputs, err := r.call(
+		ctx,
+		r.delegationManagerAddress,
+		r.delegationManagerAbi,
+		"getOperatorShares",
+		[][]interface{}{{operatorAddress, strategyAddresses}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(outputs[0][0], new([]*big.Int)).(*[]*big.Int), nil
+}
+
+func (r *Reader) GetAllocationDelay(ctx context.Context, operatorAddress gethcommon.Address) (uint32, error) {
+	out, err := r.callAllocationManager(ctx, "getAllocationDelay", operatorAddress)
+	if err != nil {
+		return 0, err
+	}
+	isSet := *abi.ConvertType(out[0], new(bool)).(*bool)
+	if !isSet {
+		return 0, errors.New("allocation delay not set")
+	}
+	return *abi.ConvertType(out[1], new(uint32)).(*uint32), nil
+}
+
+func (r *Reader) GetDeallocationDelay(ctx context.Context) (uint32, error) {
+	out, err := r.callAllocationManager(ctx, "DEALLOCATION_DELAY")
+	if err != nil {
+		return 0, err
+	}
+	return *abi.ConvertType(out[0], new(uint32)).(*uint32), nil
+}
+
+func (r *Reader) GetRegisteredSets(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	out, err := r.callAllocationManager(ctx, "getRegisteredSets", operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]allocationmanager.OperatorSet)).(*[]allocationmanager.OperatorSet), nil
+}
+
+func (r *Reader) GetAllocatedSets(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	out, err := r.callAllocationManager(ctx, "getAllocatedSets", operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]allocationmanager.OperatorSet)).(*[]allocationmanager.OperatorSet), nil
+}
+
+func (r *Reader) GetAllocatedStrategies(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	out, err := r.callAllocationManager(ctx, "getAllocatedStrategies", operatorAddress, operatorSet)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]gethcommon.Address)).(*[]gethcommon.Address), nil
+}
+
+func (r *Reader) GetStrategiesInOperatorSet(
+	ctx context.Context,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	out, err := r.callAllocationManager(ctx, "getStrategiesInOperatorSet", operatorSet)
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(out[0], new([]gethcommon.Address)).(*[]gethcommon.Address), nil
+}
+
+// GetAllocations returns the allocation of the operator to the operator set in each strategy, in the order
+// of the strategies
+func (r *Reader) GetAllocations(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategyAddresses []gethcommon.Address,
+) ([]allocationmanager.IAllocationManagerTypesAllocation, error) {
+	args := make([][]interface{}, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		args[i] = []interface{}{operatorAddress, operatorSet, strategyAddress}
+	}
+	outputs, err := r.call(ctx, r.allocationManagerAddress, r.allocationManagerAbi, "getAllocation", args)
+	if err != nil {
+		return nil, err
+	}
+	allocations := make([]allocationmanager.IAllocationManagerTypesAllocation, len(strategyAddresses))
+	for i := range strategyAddresses {
+		allocations[i] = *abi.ConvertType(
+			outputs[i][0],
+			new(allocationmanager.IAllocationManagerTypesAllocation),
+		).(*allocationmanager.IAllocationManagerTypesAllocation)
+	}
+	return allocations, nil
+}
+
+// GetSlashableSharesInQueue returns the shares of each strategy in the queued withdrawals of the stakers of
+// the operator which can still be slashed, in the order of the strategies
+func (r *Reader) GetSlashableSharesInQueue(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]*big.Int, error) {
+	args := make([][]interface{}, len(strategyAddresses))
+	for i, strategyAddress := range strategyAddresses {
+		args[i] = []interface{}{operatorAddress, strategyAddress}
+	}
+	outputs, err := r.call(ctx, r.delegationManagerAddress, r.delegationManagerAbi, "getSlashableSharesInQueue", args)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]*big.Int, len(strategyAddresses))
+	for i := range strategyAddresses {
+		shares[i] = *abi.ConvertType(outputs[i][0], new(*big.Int)).(**big.Int)
+	}
+	return shares, nil
+}
+
+// GetStrategyAndUnderlyingToken returns the bindings of the strategy and the address of its underlying token
+func (r *Reader) GetStrategyAndUnderlyingToken(
+	ctx context.Context,
+	strategyAddress gethcommon.Address,
+) (*strategy.ContractIStrategy, gethcommon.Address, error) {
+	contractStrategy, err := strategy.NewContractIStrategy(strategyAddress, r.ethClient)
+	if err != nil {
+		return nil, gethcommon.Address{}, eigenSdkUtils.WrapError("failed to fetch strategy contract", err)
+	}
+	outputs, err := r.call(ctx, strategyAddress, r.strategyAbi, "underlyingToken", [][]interface{}{{}})
+	if err != nil {
+		return nil, gethcommon.Address{}, eigenSdkUtils.WrapError("failed to fetch token contract", err)
+	}
+	return contractStrategy, *abi.ConvertType(outputs[0][0], new(gethcommon.Address)).(*gethcommon.Address), nil
+}
+
+// SharesToUnderlying returns the amount of the underlying token the shares of the strategy are worth
+func (r *Reader) SharesToUnderlying(
+	ctx context.Context,
+	strategyAddress gethcommon.Address,
+	shares *big.Int,
+) (*big.Int, error) {
+	outputs, err := r.call(ctx, strategyAddress, r.strategyAbi, "sharesToUnderlyingView", [][]interface{}{{shares}})
+	if err != nil {
+		return nil, err
+	}
+	return *abi.ConvertType(outputs[0][0], new(*big.Int)).(**big.Int), nil
+}
@@ -27,23 +27,12 @@ func GetELWriter(
 	chainId *big.Int,
 	logger eigensdkLogger.Logger,
 ) (*elcontracts.ChainWriter, error) {
-	if signerConfig == nil {
-		return nil, errors.New("signer is required for broadcasting")
-	}
-	logger.Debug("Getting Writer from config")
-	keyWallet, sender, err := getWallet(
-		*signerConfig,
-		signerAddress.String(),
-		ethClient,
-		prompter,
-		*chainId,
-		logger,
-	)
+	txMgr, err := GetTxManager(signerAddress, signerConfig, ethClient, prompter, chainId, logger)
 	if err != nil {
-		return nil, eigenSdkUtils.WrapError("failed to get wallet", err)
+		return nil, err
 	}
 
-	txMgr := txmgr.NewSimpleTxManager(keyWallet, ethClient, logger, sender)
+	logger.Debug("Getting Writer from config")
 	noopMetrics := eigenMetrics.NewNoopMetrics()
 	eLWriter, err := elcontracts.NewWriterFromConfig(
 		contractConfig,
@@ -59,6 +48,34 @@ func GetELWriter(
 	return eLWriter, nil
 }
 
+// GetTxManager returns a transaction manager which signs and sends arbitrary transactions
+// with the configured signer. Use GetELWriter for transactions supported by the ChainWriter.
+func GetTxManager(
+	signerAddress gethcommon.Address,
+	signerConfig *types.SignerConfig,
+	ethClient *ethclient.Client,
+	prompter utils.Prompter,
+	chainId *big.Int,
+	logger eigensdkLogger.Logger,
+) (txmgr.TxManager, error) {
+	if signerConfig == nil {
+		return nil, errors.New("signer is required for broadcasting")
+	}
+	keyWallet, sender, err := getWallet(
+		*signerConfig,
+		signerAddress.String(),
+		ethClient,
+		prompter,
+		*chainId,
+		logger,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get wallet", err)
+	}
+
+	return txmgr.NewSimpleTxManager(keyWallet, ethClient, logger, sender), nil
+}
+
 func IsSmartContractAddress(address gethcommon.Address, ethClient *ethclient.Client) bool {
 	code, err := ethClient.CodeAt(context.Background(), address, nil)
 	if err != nil {
@@ -58,6 +58,28 @@ func ConvertStringSliceToGethAddressSlice(addresses []string) []common.Address {
 	return gethAddresses
 }
 
+// MatchesAddressFilter returns whether address is in filter. An empty filter matches every address.
+func MatchesAddressFilter(filter []common.Address, address common.Address) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, a := range filter {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSharesForMagnitude returns the shares a magnitude is worth: shares * magnitude / maxMagnitude
+func GetSharesForMagnitude(shares *big.Int, magnitude uint64, maxMagnitude uint64) *big.Int {
+	if shares == nil || maxMagnitude == 0 {
+		return big.NewInt(0)
+	}
+	result := new(big.Int).Mul(shares, new(big.Int).SetUint64(magnitude))
+	return result.Div(result, new(big.Int).SetUint64(maxMagnitude))
+}
+
 func ShortEthAddress(address common.Address) string {
 	return fmt.Sprintf("%s...%s", address.Hex()[:6], address.Hex()[len(address.Hex())-4:])
 }
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesAddressFilter(t *testing.T) {
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	assert.True(t, MatchesAddressFilter(nil, a))
+	assert.True(t, MatchesAddressFilter([]common.Address{a}, a))
+	assert.False(t, MatchesAddressFilter([]common.Address{a}, b))
+}
+
+func TestGetSharesForMagnitude(t *testing.T) {
+	assert.Equal(t, big.NewInt(2_500), GetSharesForMagnitude(big.NewInt(10_000), 250, 1_000))
+	assert.Equal(t, big.NewInt(3), GetSharesForMagnitude(big.NewInt(10), 1, 3))
+	assert.Equal(t, big.NewInt(0), GetSharesForMagnitude(nil, 250, 1_000))
+	assert.Equal(t, big.NewInt(0), GetSharesForMagnitude(big.NewInt(10_000), 250, 0))
+}
@@ -83,6 +83,13 @@ var (
 		EnvVars: []string{"SILENT"},
 	}
 
+	YesFlag = cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Skip the confirmation prompt before broadcasting the transaction",
+		EnvVars: []string{"YES"},
+	}
+
 	ExpiryFlag = cli.Int64Flag{
 		Name:    "expiry",
 		Aliases: []string{"exp"},
@@ -144,4 +151,24 @@ var (
 		Usage:   "BLS private key of Operator for Operator Set registration",
 		EnvVars: []string{"BLS_PRIVATE_KEY"},
 	}
+	SafeBatchFileFlag = cli.StringFlag{
+		Name:    "safe-batch-file",
+		Aliases: []string{"sbf"},
+		Usage:   "Write the unsigned transactions as a Safe Transaction Builder batch to this file instead of printing them",
+		EnvVars: []string{"SAFE_BATCH_FILE"},
+	}
+	MulticallBatchSizeFlag = cli.IntFlag{
+		Name:    "multicall-batch-size",
+		Aliases: []string{"mbs"},
+		Usage:   "Maximum number of contract reads aggregated into a single Multicall3 call",
+		Value:   100,
+		EnvVars: []string{"MULTICALL_BATCH_SIZE"},
+	}
+	MaxConcurrencyFlag = cli.IntFlag{
+		Name:    "max-concurrency",
+		Aliases: []string{"mc"},
+		Usage:   "Maximum number of concurrent RPC requests",
+		Value:   4,
+		EnvVars: []string{"MAX_CONCURRENCY"},
+	}
 )
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogsBlockRange is the number of blocks of each logs query, which is within the range limit of most RPC
+// providers. Public RPCs reject queries from genesis to the latest block on mainnet.
+const LogsBlockRange = 10_000
+
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// FilterLogsInRanges calls filter with consecutive ranges of at most LogsBlockRange blocks covering fromBlock to
+// toBlock, oldest first
+func FilterLogsInRanges(
+	ctx context.Context,
+	fromBlock uint64,
+	toBlock uint64,
+	filter func(opts *bind.FilterOpts) error,
+) error {
+	for start := fromBlock; start <= toBlock; start += LogsBlockRange {
+		end := min(start+LogsBlockRange-1, toBlock)
+		if err := filter(&bind.FilterOpts{Start: start, End: &end, Context: ctx}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilterLatestLogsInRanges calls filter with consecutive ranges of at most LogsBlockRange blocks from toBlock back
+// to fromBlock, newest first, until filter reports that it found what it looks for. It is meant for reading the
+// latest event without scanning the whole chain.
+func FilterLatestLogsInRanges(
+	ctx context.Context,
+	fromBlock uint64,
+	toBlock uint64,
+	filter func(opts *bind.FilterOpts) (bool, error),
+) error {
+	if toBlock < fromBlock {
+		return nil
+	}
+	for end := toBlock; ; end -= LogsBlockRange {
+		start := fromBlock
+		if end-fromBlock >= LogsBlockRange {
+			start = end - LogsBlockRange + 1
+		}
+		rangeEnd := end
+		found, err := filter(&bind.FilterOpts{Start: start, End: &rangeEnd, Context: ctx})
+		if err != nil {
+			return err
+		}
+		if found || start == fromBlock {
+			return nil
+		}
+	}
+}
+
+// LookbackStartBlock returns the first block of a lookback of the given number of blocks ending at latestBlock
+func LookbackStartBlock(latestBlock uint64, lookbackBlocks uint64) uint64 {
+	if latestBlock < lookbackBlocks {
+		return 0
+	}
+	return latestBlock - lookbackBlocks + 1
+}
+
+// FindBlockByTimestamp returns the first block with a timestamp at or after the timestamp, or the block after
+// latestBlock if there is none
+func FindBlockByTimestamp(
+	ctx context.Context,
+	reader HeaderReader,
+	latestBlock uint64,
+	timestamp uint64,
+) (uint64, error) {
+	low, high := uint64(0), latestBlock+1
+	for low < high {
+		mid := low + (high-low)/2
+		header, err := reader.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, eigenSdkUtils.WrapError(fmt.Sprintf("failed to get header of block %d", mid), err)
+		}
+		if header.Time < timestamp {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
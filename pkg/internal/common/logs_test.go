@@ -0,0 +1,105 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockRange struct {
+	start uint64
+	end   uint64
+}
+
+func TestFilterLogsInRanges(t *testing.T) {
+	ranges := make([]blockRange, 0)
+	err := FilterLogsInRanges(context.Background(), 5, 25_004, func(opts *bind.FilterOpts) error {
+		ranges = append(ranges, blockRange{opts.Start, *opts.End})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []blockRange{{5, 10_004}, {10_005, 20_004}, {20_005, 25_004}}, ranges)
+
+	ranges = ranges[:0]
+	err = FilterLogsInRanges(context.Background(), 10, 9, func(opts *bind.FilterOpts) error {
+		ranges = append(ranges, blockRange{opts.Start, *opts.End})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	calls := 0
+	err = FilterLogsInRanges(context.Background(), 0, 50_000, func(opts *bind.FilterOpts) error {
+		calls++
+		return errors.New("range too large")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestFilterLatestLogsInRanges(t *testing.T) {
+	ranges := make([]blockRange, 0)
+	err := FilterLatestLogsInRanges(context.Background(), 5, 25_004, func(opts *bind.FilterOpts) (bool, error) {
+		ranges = append(ranges, blockRange{opts.Start, *opts.End})
+		return false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []blockRange{{15_005, 25_004}, {5_005, 15_004}, {5, 5_004}}, ranges)
+
+	ranges = ranges[:0]
+	err = FilterLatestLogsInRanges(context.Background(), 0, 25_000, func(opts *bind.FilterOpts) (bool, error) {
+		ranges = append(ranges, blockRange{opts.Start, *opts.End})
+		return opts.Start <= 10_000, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []blockRange{{15_001, 25_000}, {5_001, 15_000}}, ranges)
+
+	ranges = ranges[:0]
+	err = FilterLatestLogsInRanges(context.Background(), 0, 0, func(opts *bind.FilterOpts) (bool, error) {
+		ranges = append(ranges, blockRange{opts.Start, *opts.End})
+		return false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []blockRange{{0, 0}}, ranges)
+}
+
+func TestLookbackStartBlock(t *testing.T) {
+	assert.Equal(t, uint64(0), LookbackStartBlock(100, 1_000))
+	assert.Equal(t, uint64(901), LookbackStartBlock(1_000, 100))
+	assert.Equal(t, uint64(1), LookbackStartBlock(100, 100))
+}
+
+type fakeHeaderReader struct {
+	timestamps []uint64
+}
+
+func (f *fakeHeaderReader) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Time: f.timestamps[number.Uint64()]}, nil
+}
+
+func TestFindBlockByTimestamp(t *testing.T) {
+	reader := &fakeHeaderReader{timestamps: []uint64{100, 112, 124, 124, 136, 148}}
+	tests := []struct {
+		timestamp     uint64
+		expectedBlock uint64
+	}{
+		{timestamp: 0, expectedBlock: 0},
+		{timestamp: 100, expectedBlock: 0},
+		{timestamp: 101, expectedBlock: 1},
+		{timestamp: 124, expectedBlock: 2},
+		{timestamp: 130, expectedBlock: 4},
+		{timestamp: 148, expectedBlock: 5},
+		{timestamp: 149, expectedBlock: 6},
+	}
+	for _, tt := range tests {
+		block, err := FindBlockByTimestamp(context.Background(), reader, 5, tt.timestamp)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expectedBlock, block, "timestamp %d", tt.timestamp)
+	}
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const safeBatchVersion = "1.0"
+
+// SafeBatch is a batch of transactions in the Safe Transaction Builder JSON format.
+// It can be imported in the Safe web app to propose all transactions as a single multisend.
+type SafeBatch struct {
+	Version      string            `json:"version"`
+	ChainID      string            `json:"chainId"`
+	CreatedAt    int64             `json:"createdAt"`
+	Meta         SafeBatchMeta     `json:"meta"`
+	Transactions []SafeTransaction `json:"transactions"`
+}
+
+type SafeBatchMeta struct {
+	Name                   string `json:"name"`
+	Description            string `json:"description"`
+	CreatedFromSafeAddress string `json:"createdFromSafeAddress"`
+}
+
+type SafeTransaction struct {
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Data  string `json:"data"`
+}
+
+func NewSafeBatch(chainID *big.Int, safeAddress gethcommon.Address, name string, description string) *SafeBatch {
+	return &SafeBatch{
+		Version:   safeBatchVersion,
+		ChainID:   chainID.String(),
+		CreatedAt: time.Now().UnixMilli(),
+		Meta: SafeBatchMeta{
+			Name:                   name,
+			Description:            description,
+			CreatedFromSafeAddress: safeAddress.Hex(),
+		},
+		Transactions: make([]SafeTransaction, 0),
+	}
+}
+
+// AddTransaction appends the target, value and calldata of an unsigned transaction to the batch
+func (b *SafeBatch) AddTransaction(tx *gethtypes.Transaction) {
+	to := ""
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	value := "0"
+	if tx.Value() != nil {
+		value = tx.Value().String()
+	}
+	b.Transactions = append(b.Transactions, SafeTransaction{
+		To:    to,
+		Value: value,
+		Data:  hexutil.Encode(tx.Data()),
+	})
+}
+
+func (b *SafeBatch) WriteToFile(filePath string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteToFile(data, filePath)
+}
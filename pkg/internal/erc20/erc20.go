@@ -1,24 +1,38 @@
 package erc20
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const (
 	UnknownTokenName = "Unknown"
+
+	// DefaultDecimals is used for tokens which don't implement decimals()
+	DefaultDecimals = 18
 )
 
 // ABI is a simplified ABI for the ERC20 token standard
-var ABI = `[{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"}]`
+var ABI = `[
+	{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"type":"function"}
+]`
 
 // ERC20 is the Go binding of the ERC20 contract
 type ERC20 struct {
-	Caller // Read-only binding to the contract
+	Caller     // Read-only binding to the contract
+	Transactor // Write-only binding to the contract
 }
 
 // Caller is an auto generated read-only Go binding around an Ethereum contract.
@@ -36,13 +50,67 @@ func (c *Caller) Name(opts *bind.CallOpts) (string, error) {
 	return out[0].(string), nil
 }
 
+// Symbol is a free data retrieval call binding the contract method 0x95d89b41.
+func (c *Caller) Symbol(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "symbol")
+	if err != nil {
+		return "", err
+	}
+	return out[0].(string), nil
+}
+
+// Decimals is a free data retrieval call binding the contract method 0x313ce567.
+func (c *Caller) Decimals(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	return out[0].(uint8), nil
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+func (c *Caller) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Allowance is a free data retrieval call binding the contract method 0xdd62ed3e.
+func (c *Caller) Allowance(opts *bind.CallOpts, owner common.Address, spender common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := c.contract.Call(opts, &out, "allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Approve is a paid mutator transaction binding the contract method 0x095ea7b3.
+func (t *Transactor) Approve(
+	opts *bind.TransactOpts,
+	spender common.Address,
+	amount *big.Int,
+) (*types.Transaction, error) {
+	return t.contract.Transact(opts, "approve", spender, amount)
+}
+
 // NewERC20 creates a new instance of ERC20, bound to a specific deployed contract.
 func NewERC20(address common.Address, backend bind.ContractBackend) (*ERC20, error) {
 	contract, err := bindERC20(address, backend, backend, backend)
 	if err != nil {
 		return nil, err
 	}
-	return &ERC20{Caller: Caller{contract: contract}}, nil
+	return &ERC20{Caller: Caller{contract: contract}, Transactor: Transactor{contract: contract}}, nil
 }
 
 // bindERC20 binds a generic wrapper to an already deployed contract.
@@ -72,3 +140,41 @@ func GetTokenName(tokenAddress common.Address, client *ethclient.Client) string
 
 	return name
 }
+
+// GetTokenSymbolAndDecimals returns the symbol and decimals of a token. The symbol is UnknownTokenName and
+// the decimals are DefaultDecimals if they can't be read.
+func GetTokenSymbolAndDecimals(tokenAddress common.Address, client bind.ContractBackend) (string, uint8) {
+	erc20Client, err := NewERC20(tokenAddress, client)
+	if err != nil {
+		return UnknownTokenName, DefaultDecimals
+	}
+
+	symbol, err := erc20Client.Symbol(&bind.CallOpts{})
+	if err != nil {
+		symbol = UnknownTokenName
+	}
+	decimals, err := erc20Client.Decimals(&bind.CallOpts{})
+	if err != nil {
+		decimals = DefaultDecimals
+	}
+	return symbol, decimals
+}
+
+// FormatAmount formats an amount in the smallest unit of a token as a decimal number of whole tokens,
+// without trailing zeros. 1500000000000000000 with 18 decimals is formatted as 1.5.
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, fraction := new(big.Int).QuoRem(new(big.Int).Abs(amount), unit, new(big.Int))
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if fraction.Sign() == 0 {
+		return sign + whole.String()
+	}
+	fractionStr := strings.TrimRight(fmt.Sprintf("%0*s", int(decimals), fraction.String()), "0")
+	return sign + whole.String() + "." + fractionStr
+}
@@ -0,0 +1,48 @@
+package erc20
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		expected string
+	}{
+		{amount: "1500000000000000000", decimals: 18, expected: "1.5"},
+		{amount: "1000000000000000000", decimals: 18, expected: "1"},
+		{amount: "1", decimals: 18, expected: "0.000000000000000001"},
+		{amount: "0", decimals: 18, expected: "0"},
+		{amount: "-2500000", decimals: 6, expected: "-2.5"},
+		{amount: "12345", decimals: 0, expected: "12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			amount, ok := new(big.Int).SetString(tt.amount, 10)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, FormatAmount(amount, tt.decimals))
+		})
+	}
+}
+
+func TestABIMethodIDs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(ABI))
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		"balanceOf": "70a08231",
+		"allowance": "dd62ed3e",
+		"approve":   "095ea7b3",
+	}
+	for name, id := range expected {
+		assert.Equal(t, id, hex.EncodeToString(parsed.Methods[name].ID), name)
+	}
+}
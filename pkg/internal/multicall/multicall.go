@@ -0,0 +1,242 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	DefaultBatchSize      = 100
+	DefaultMaxConcurrency = 4
+)
+
+// Multicall3Address is the address of the Multicall3 contract. It is deployed at the same address on
+// all supported networks, see https://github.com/mds1/multicall
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// ABI is a simplified ABI of Multicall3 with only the aggregate3 function
+var ABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+var multicallABI = mustParseABI(ABI)
+
+// Call is a single contract call of a multicall batch
+type Call struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// Result is the return data of a Call. Err is set if the call reverted.
+type Result struct {
+	ReturnData []byte
+	Err        error
+}
+
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// Client aggregates contract calls into Multicall3 calls. All calls are made at the same block, so the
+// results are consistent with each other.
+type Client struct {
+	caller         bind.ContractCaller
+	address        common.Address
+	blockNumber    *big.Int
+	batchSize      int
+	maxConcurrency int
+	aggregate      bool
+}
+
+type Option func(*Client)
+
+// WithBatchSize sets the maximum number of calls aggregated into a single Multicall3 call
+func WithBatchSize(batchSize int) Option {
+	return func(c *Client) {
+		if batchSize > 0 {
+			c.batchSize = batchSize
+		}
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of Multicall3 calls in flight at the same time
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(c *Client) {
+		if maxConcurrency > 0 {
+			c.maxConcurrency = maxConcurrency
+		}
+	}
+}
+
+// WithoutAggregation makes every call separately instead of through Multicall3, for networks where
+// Multicall3 is not deployed. Calls are still made at the same block and with the same concurrency limit.
+func WithoutAggregation() Option {
+	return func(c *Client) {
+		c.aggregate = false
+	}
+}
+
+// WithAddress overrides the Multicall3 address, for example for local deployments
+func WithAddress(address common.Address) Option {
+	return func(c *Client) {
+		c.address = address
+	}
+}
+
+// NewClient returns a client which makes all calls at blockNumber
+func NewClient(caller bind.ContractCaller, blockNumber *big.Int, opts ...Option) *Client {
+	c := &Client{
+		caller:         caller,
+		address:        Multicall3Address,
+		blockNumber:    blockNumber,
+		batchSize:      DefaultBatchSize,
+		maxConcurrency: DefaultMaxConcurrency,
+		aggregate:      true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// IsDeployed returns whether the Multicall3 contract is deployed at the given block
+func IsDeployed(ctx context.Context, caller bind.ContractCaller, blockNumber *big.Int) (bool, error) {
+	code, err := caller.CodeAt(ctx, Multicall3Address, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// BlockNumber returns the block all calls are made at
+func (c *Client) BlockNumber() *big.Int {
+	return c.blockNumber
+}
+
+// Aggregate makes the calls in batches of at most the batch size. The results are returned in the
+// order of the calls. A reverted call doesn't fail the batch, its error is set on its Result instead.
+func (c *Client) Aggregate(ctx context.Context, calls []Call) ([]Result, error) {
+	results := make([]Result, len(calls))
+	if len(calls) == 0 {
+		return results, nil
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.maxConcurrency)
+	errChan := make(chan error, (len(calls)+c.batchSize-1)/c.batchSize)
+	for start := 0; start < len(calls); start += c.batchSize {
+		end := min(start+c.batchSize, len(calls))
+		wg.Add(1)
+		go func(start int, end int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			var batchResults []Result
+			var err error
+			if c.aggregate {
+				batchResults, err = c.aggregate3(ctx, calls[start:end])
+			} else {
+				batchResults, err = c.callEach(ctx, calls[start:end])
+			}
+			if err != nil {
+				errChan <- err
+				return
+			}
+			// Each goroutine writes to its own range of results, so no lock is needed
+			copy(results[start:end], batchResults)
+		}(start, end)
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	if len(errChan) > 0 {
+		return nil, <-errChan // Return the first error encountered
+	}
+	return results, nil
+}
+
+func (c *Client) aggregate3(ctx context.Context, calls []Call) ([]Result, error) {
+	multicallCalls := make([]multicall3Call, len(calls))
+	for i, call := range calls {
+		multicallCalls[i] = multicall3Call{Target: call.Target, AllowFailure: true, CallData: call.CallData}
+	}
+	data, err := multicallABI.Pack("aggregate3", multicallCalls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack multicall: %w", err)
+	}
+
+	output, err := c.caller.CallContract(ctx, ethereum.CallMsg{To: &c.address, Data: data}, c.blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("multicall failed: %w", err)
+	}
+	if len(output) == 0 {
+		return nil, fmt.Errorf("no Multicall3 contract found at %s", c.address.Hex())
+	}
+
+	var multicallResults []multicall3Result
+	if err := multicallABI.UnpackIntoInterface(&multicallResults, "aggregate3", output); err != nil {
+		return nil, fmt.Errorf("failed to unpack multicall result: %w", err)
+	}
+	if len(multicallResults) != len(calls) {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(multicallResults), len(calls))
+	}
+
+	results := make([]Result, len(calls))
+	for i, result := range multicallResults {
+		results[i] = Result{ReturnData: result.ReturnData}
+		if !result.Success {
+			results[i].Err = errors.New("call reverted")
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) callEach(ctx context.Context, calls []Call) ([]Result, error) {
+	results := make([]Result, len(calls))
+	for i, call := range calls {
+		returnData, err := c.caller.CallContract(ctx, ethereum.CallMsg{To: &call.Target, Data: call.CallData}, c.blockNumber)
+		results[i] = Result{ReturnData: returnData, Err: err}
+	}
+	return results, nil
+}
+
+// NewCall packs the call of method on the contract with the given ABI
+func NewCall(target common.Address, contractABI *abi.ABI, method string, args ...interface{}) (Call, error) {
+	callData, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return Call{}, fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+	return Call{Target: target, CallData: callData}, nil
+}
+
+// Unpack unpacks the return data of method into its output values
+func (r Result) Unpack(contractABI *abi.ABI, method string) ([]interface{}, error) {
+	if r.Err != nil {
+		return nil, fmt.Errorf("%s: %w", method, r.Err)
+	}
+	return contractABI.Unpack(method, r.ReturnData)
+}
+
+func mustParseABI(abiJson string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,182 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testABI = `[{"inputs":[{"name":"x","type":"uint256"}],"name":"double","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+var (
+	testContractABI = mustParseABI(testABI)
+	testTarget      = common.HexToAddress("0x1234")
+)
+
+// fakeCaller executes aggregate3 calls of the double function, reverting for inputs in revert
+type fakeCaller struct {
+	mu             sync.Mutex
+	revert         map[int64]bool
+	multicallCalls int
+	directCalls    int
+	blockNumbers   []*big.Int
+}
+
+func (f *fakeCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (f *fakeCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	f.blockNumbers = append(f.blockNumbers, blockNumber)
+	f.mu.Unlock()
+
+	if *call.To == testTarget {
+		f.mu.Lock()
+		f.directCalls++
+		f.mu.Unlock()
+		returnData, ok := f.double(call.Data)
+		if !ok {
+			return nil, errors.New("execution reverted")
+		}
+		return returnData, nil
+	}
+
+	f.mu.Lock()
+	f.multicallCalls++
+	f.mu.Unlock()
+	args, err := multicallABI.Methods["aggregate3"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls := *abi.ConvertType(args[0], new([]multicall3Call)).(*[]multicall3Call)
+	results := make([]multicall3Result, len(calls))
+	for i, c := range calls {
+		returnData, ok := f.double(c.CallData)
+		results[i] = multicall3Result{Success: ok, ReturnData: returnData}
+	}
+	return multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+}
+
+func (f *fakeCaller) double(callData []byte) ([]byte, bool) {
+	args, err := testContractABI.Methods["double"].Inputs.Unpack(callData[4:])
+	if err != nil {
+		return nil, false
+	}
+	x := args[0].(*big.Int)
+	if f.revert[x.Int64()] {
+		return nil, false
+	}
+	returnData, _ := testContractABI.Methods["double"].Outputs.Pack(new(big.Int).Mul(x, big.NewInt(2)))
+	return returnData, true
+}
+
+func newDoubleCalls(t *testing.T, n int) []Call {
+	calls := make([]Call, n)
+	for i := range calls {
+		call, err := NewCall(testTarget, &testContractABI, "double", big.NewInt(int64(i)))
+		assert.NoError(t, err)
+		calls[i] = call
+	}
+	return calls
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name                   string
+		numCalls               int
+		opts                   []Option
+		revert                 map[int64]bool
+		expectedMulticallCalls int
+		expectedDirectCalls    int
+	}{
+		{
+			name:                   "single batch",
+			numCalls:               5,
+			opts:                   []Option{WithBatchSize(10)},
+			expectedMulticallCalls: 1,
+		},
+		{
+			name:                   "multiple batches keep the order of the calls",
+			numCalls:               25,
+			opts:                   []Option{WithBatchSize(10), WithMaxConcurrency(2)},
+			expectedMulticallCalls: 3,
+		},
+		{
+			name:                   "reverted calls don't fail the batch",
+			numCalls:               5,
+			opts:                   []Option{WithBatchSize(2)},
+			revert:                 map[int64]bool{3: true},
+			expectedMulticallCalls: 3,
+		},
+		{
+			name:                "without aggregation",
+			numCalls:            5,
+			opts:                []Option{WithBatchSize(2), WithoutAggregation()},
+			revert:              map[int64]bool{1: true},
+			expectedDirectCalls: 5,
+		},
+		{
+			name:     "no calls",
+			numCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &fakeCaller{revert: tt.revert}
+			blockNumber := big.NewInt(100)
+			client := NewClient(caller, blockNumber, tt.opts...)
+
+			results, err := client.Aggregate(context.Background(), newDoubleCalls(t, tt.numCalls))
+			assert.NoError(t, err)
+			assert.Len(t, results, tt.numCalls)
+			assert.Equal(t, tt.expectedMulticallCalls, caller.multicallCalls)
+			assert.Equal(t, tt.expectedDirectCalls, caller.directCalls)
+			for _, b := range caller.blockNumbers {
+				assert.Equal(t, blockNumber, b)
+			}
+
+			for i, result := range results {
+				out, err := result.Unpack(&testContractABI, "double")
+				if tt.revert[int64(i)] {
+					assert.Error(t, err)
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2*i), out[0].(*big.Int).Int64())
+			}
+		})
+	}
+}
+
+func TestAggregateWithoutMulticall3(t *testing.T) {
+	// A call to an address without code succeeds with empty return data
+	caller := &emptyCaller{}
+	client := NewClient(caller, big.NewInt(1))
+
+	_, err := client.Aggregate(context.Background(), newDoubleCalls(t, 1))
+	assert.ErrorContains(t, err, "no Multicall3 contract found")
+
+	deployed, err := IsDeployed(context.Background(), caller, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.False(t, deployed)
+}
+
+type emptyCaller struct{}
+
+func (e *emptyCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (e *emptyCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
@@ -24,9 +24,13 @@ func OperatorCmd(p utils.Prompter) *cli.Command {
 			operator.SetOperatorPISplitCmd(p),
 			operator.SetOperatorSetSplitCmd(p),
 			operator.GetOperatorSetSplitCmd(p),
+			operator.SplitsCmd(p),
 			operator.AllocationsCmd(p),
 			operator.NewDeregisterOperatorSetsCmd(p),
 			operator.NewRegisterOperatorSetsCmd(p),
+			operator.PlanCmd(p),
+			operator.ApplyCmd(p),
+			operator.SlashingExposureCmd(p),
 		},
 	}
 
@@ -14,6 +14,11 @@ func AllocationsCmd(p utils.Prompter) *cli.Command {
 			allocations.ShowCmd(p),
 			allocations.UpdateCmd(p),
 			allocations.SetDelayCmd(p),
+			allocations.GetDelayCmd(p),
+			allocations.ExportCmd(p),
+			allocations.PendingCmd(p),
+			allocations.ClearQueueCmd(p),
+			allocations.HistoryCmd(p),
 		},
 	}
 
@@ -0,0 +1,278 @@
+package allocations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func ClearQueueCmd(p utils.Prompter) *cli.Command {
+	clearQueueCmd := &cli.Command{
+		Name:      "clear-queue",
+		Usage:     "Clear completed deallocations from the deallocation queue",
+		UsageText: "clear-queue",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to clear completed deallocations from the deallocation queue of the operator
+
+Completed deallocations only release their magnitude from the encumbered magnitude of a strategy once
+they are cleared from the queue. If --strategy-addresses is not set, all strategies of the operator
+whose queue has completed deallocations are cleared.
+`,
+		Flags: getClearQueueFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return clearQueueAction(cCtx, p)
+		},
+	}
+	return clearQueueCmd
+}
+
+func clearQueueAction(cCtx *cli.Context, p utils.Prompter) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateClearQueueConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate clear queue config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	strategyAddresses := config.strategyAddresses
+	if len(strategyAddresses) == 0 {
+		elReader, err := batchreader.NewReader(ctx, ethClient, config.delegationManagerAddress, 0, 0, logger)
+		if err != nil {
+			return err
+		}
+		discovered, err := discoverOperatorStrategies(
+			ctx,
+			elReader,
+			ethClient,
+			config.operatorAddress,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to discover strategies", err)
+		}
+		strategyAddresses, err = getStrategiesToClear(ctx, elReader, config.operatorAddress, discovered)
+		if err != nil {
+			return err
+		}
+		if len(strategyAddresses) == 0 {
+			fmt.Println("Deallocation queue has no completed deallocations to clear")
+			return nil
+		}
+	}
+	// Clearing stops at the first deallocation which is not completed, so this clears all completed ones
+	numsToClear := make([]uint16, len(strategyAddresses))
+	for i := range numsToClear {
+		numsToClear[i] = math.MaxUint16
+	}
+
+	if config.broadcast {
+		if config.signerConfig == nil {
+			return errors.New("signer is required for broadcasting")
+		}
+		logger.Info("Broadcasting clear deallocation queue transaction...")
+		eLWriter, err := common.GetELWriter(
+			config.callerAddress,
+			config.signerConfig,
+			ethClient,
+			elcontracts.Config{
+				DelegationManagerAddress: config.delegationManagerAddress,
+			},
+			p,
+			config.chainID,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get EL writer", err)
+		}
+
+		receipt, err := eLWriter.ClearDeallocationQueue(
+			ctx,
+			config.operatorAddress,
+			strategyAddresses,
+			numsToClear,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+		common.PrintTransactionInfo(receipt.TxHash.String(), config.chainID)
+	} else {
+		noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
+		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+			DelegationManagerAddress: config.delegationManagerAddress,
+		}, ethClient, nil, logger, nil)
+		if err != nil {
+			return err
+		}
+		// If operator is a smart contract, we can't estimate gas using geth
+		// since balance of contract can be 0, as it can be called by an EOA
+		// to claim. So we hardcode the gas limit to 150_000 so that we can
+		// create unsigned tx without gas limit estimation from contract bindings
+		if common.IsSmartContractAddress(config.callerAddress, ethClient) {
+			// address is a smart contract
+			noSendTxOpts.GasLimit = 150_000
+		}
+
+		unsignedTx, err := contractBindings.AllocationManager.ClearDeallocationQueue(
+			noSendTxOpts,
+			config.operatorAddress,
+			strategyAddresses,
+			numsToClear,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to create unsigned tx", err)
+		}
+
+		if config.outputType == utils.CallDataOutputType {
+			calldataHex := gethcommon.Bytes2Hex(unsignedTx.Data())
+			if !common.IsEmptyString(config.output) {
+				err = common.WriteToFile([]byte(calldataHex), config.output)
+				if err != nil {
+					return err
+				}
+				logger.Infof("Call data written to file: %s", config.output)
+			} else {
+				fmt.Println(calldataHex)
+			}
+		} else {
+			if !common.IsEmptyString(config.output) {
+				fmt.Println("output file not supported for pretty output type")
+				fmt.Println()
+			}
+			fmt.Printf("Deallocation queue will be cleared for operator %s and strategies:\n", config.operatorAddress)
+			for _, strategyAddress := range strategyAddresses {
+				fmt.Printf("  %s\n", strategyAddress.Hex())
+			}
+		}
+		txFeeDetails := common.GetTxFeeDetails(unsignedTx)
+		fmt.Println()
+		txFeeDetails.Print()
+		fmt.Println("To broadcast the transaction, use the --broadcast flag")
+	}
+
+	return nil
+}
+
+// getStrategiesToClear returns the strategies whose deallocation queue has completed deallocations
+func getStrategiesToClear(
+	ctx context.Context,
+	reader pendingAllocationReader,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]gethcommon.Address, error) {
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategyAddresses)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+	strategiesToClear := make([]gethcommon.Address, 0)
+	for i, strategyAddress := range strategyAddresses {
+		queue, err := getDeallocationQueueStatus(ctx, reader, operatorAddress, strategyAddress, maxMagnitudes[i])
+		if err != nil {
+			return nil, err
+		}
+		if queue.NeedsClearing {
+			strategiesToClear = append(strategiesToClear, strategyAddress)
+		}
+	}
+	return strategiesToClear, nil
+}
+
+func getClearQueueFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.BroadcastFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.StrategyAddressesFlag,
+		&flags.DelegationManagerAddressFlag,
+		&flags.CallerAddressFlag,
+	}
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
+
+func readAndValidateClearQueueConfig(cCtx *cli.Context, logger logging.Logger) (*clearQueueConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+
+	callerAddress := cCtx.String(flags.CallerAddressFlag.Name)
+	if common.IsEmptyString(callerAddress) {
+		logger.Infof("Caller address not provided. Using operator address (%s) as caller address", operatorAddress)
+		callerAddress = operatorAddress
+	}
+	strategyAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.StrategyAddressesFlag.Name))
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using network %s and environment: %s", network, environment)
+
+	// Get signerConfig
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		// We don't want to throw error since people can still use it to generate the claim
+		// without broadcasting it
+		logger.Debugf("Failed to get signer config: %s", err)
+	}
+
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	if delegationManagerAddress == "" {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &clearQueueConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		environment:              environment,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		broadcast:                broadcast,
+		operatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		callerAddress:            gethcommon.HexToAddress(callerAddress),
+		strategyAddresses:        strategyAddresses,
+		signerConfig:             signerConfig,
+		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+	}, nil
+}
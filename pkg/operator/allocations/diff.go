@@ -0,0 +1,276 @@
+package allocations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	AllocationChangeType   = "allocation"
+	DeallocationChangeType = "deallocation"
+	NoChangeType           = "no change"
+)
+
+type allocationDiffReader interface {
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+	GetOperatorShares(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]*big.Int, error)
+	GetAllocationDelay(ctx context.Context, operatorAddress gethcommon.Address) (uint32, error)
+	GetDeallocationDelay(ctx context.Context) (uint32, error)
+	IsOperatorSlashable(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) (bool, error)
+}
+
+type AllocationDiffs []AllocationDiff
+
+// AllocationDiff is the change of a single (operator set, strategy) allocation
+type AllocationDiff struct {
+	AvsAddress             gethcommon.Address `json:"avs_address"`
+	OperatorSetId          uint32             `json:"operator_set_id"`
+	StrategyAddress        gethcommon.Address `json:"strategy_address"`
+	CurrentMagnitude       uint64             `json:"current_magnitude"`
+	NewMagnitude           uint64             `json:"new_magnitude"`
+	Delta                  *big.Int           `json:"delta"`
+	CurrentSlashableShares *big.Int           `json:"current_slashable_shares"`
+	NewSlashableShares     *big.Int           `json:"new_slashable_shares"`
+	Type                   string             `json:"type"`
+	EffectBlock            uint64             `json:"effect_block"`
+	PendingModification    bool               `json:"pending_modification"`
+}
+
+// computeAllocationDiff compares the requested magnitudes with the current allocations of the operator.
+// The effect block is the earliest block the change can take effect at if the transaction is included
+// in the block after currentBlock.
+func computeAllocationDiff(
+	ctx context.Context,
+	reader allocationDiffReader,
+	operatorAddress gethcommon.Address,
+	allocations []allocationmanager.IAllocationManagerTypesAllocateParams,
+	currentBlock uint64,
+) (AllocationDiffs, error) {
+	strategies := make([]gethcommon.Address, 0)
+	seen := make(map[gethcommon.Address]bool)
+	for _, a := range allocations {
+		for _, strategy := range a.Strategies {
+			if !seen[strategy] {
+				seen[strategy] = true
+				strategies = append(strategies, strategy)
+			}
+		}
+	}
+
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategies)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+	operatorShares, err := reader.GetOperatorShares(ctx, operatorAddress, strategies)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get operator shares", err)
+	}
+	maxMagnitudePerStrategy := make(map[gethcommon.Address]uint64, len(strategies))
+	sharesPerStrategy := make(map[gethcommon.Address]*big.Int, len(strategies))
+	currentAllocations := make(map[gethcommon.Address]map[string]elcontracts.AllocationInfo, len(strategies))
+	for i, strategy := range strategies {
+		maxMagnitudePerStrategy[strategy] = maxMagnitudes[i]
+		sharesPerStrategy[strategy] = operatorShares[i]
+
+		allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, strategy)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+		}
+		currentAllocations[strategy] = make(map[string]elcontracts.AllocationInfo, len(allocationInfo))
+		for _, info := range allocationInfo {
+			currentAllocations[strategy][getUniqueKey(info.AvsAddress, info.OperatorSetId)] = info
+		}
+	}
+
+	deallocationDelay, err := reader.GetDeallocationDelay(ctx)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get deallocation delay", err)
+	}
+	// The allocation delay is only needed for allocations. It is not set for operators who never
+	// allocated, in which case allocations revert anyway.
+	var allocationDelay *uint32
+
+	diffs := make(AllocationDiffs, 0)
+	for _, a := range allocations {
+		slashable, err := reader.IsOperatorSlashable(ctx, operatorAddress, a.OperatorSet)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to check if operator is slashable", err)
+		}
+		for i, strategy := range a.Strategies {
+			diff := AllocationDiff{
+				AvsAddress:      a.OperatorSet.Avs,
+				OperatorSetId:   a.OperatorSet.Id,
+				StrategyAddress: strategy,
+				NewMagnitude:    a.NewMagnitudes[i],
+			}
+			info, ok := currentAllocations[strategy][getUniqueKey(a.OperatorSet.Avs, a.OperatorSet.Id)]
+			if ok {
+				diff.CurrentMagnitude = info.CurrentMagnitude.Uint64()
+				diff.PendingModification = info.PendingDiff != nil && info.PendingDiff.Sign() != 0
+			}
+			diff.Delta = new(big.Int).Sub(
+				new(big.Int).SetUint64(diff.NewMagnitude),
+				new(big.Int).SetUint64(diff.CurrentMagnitude),
+			)
+			diff.CurrentSlashableShares = common.GetSharesForMagnitude(
+				sharesPerStrategy[strategy],
+				diff.CurrentMagnitude,
+				maxMagnitudePerStrategy[strategy],
+			)
+			diff.NewSlashableShares = common.GetSharesForMagnitude(
+				sharesPerStrategy[strategy],
+				diff.NewMagnitude,
+				maxMagnitudePerStrategy[strategy],
+			)
+
+			switch diff.Delta.Sign() {
+			case 1:
+				if allocationDelay == nil {
+					delay, err := reader.GetAllocationDelay(ctx, operatorAddress)
+					if err != nil {
+						return nil, eigenSdkUtils.WrapError("failed to get allocation delay", err)
+					}
+					allocationDelay = &delay
+				}
+				diff.Type = AllocationChangeType
+				diff.EffectBlock = currentBlock + 1 + uint64(*allocationDelay)
+			case -1:
+				diff.Type = DeallocationChangeType
+				diff.EffectBlock = currentBlock + 1
+				// Deallocations only wait for the deallocation delay while the operator is slashable
+				if slashable {
+					diff.EffectBlock += uint64(deallocationDelay) + 1
+				}
+			default:
+				diff.Type = NoChangeType
+				diff.EffectBlock = currentBlock
+			}
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+func (d AllocationDiffs) HasPendingModifications() bool {
+	for _, diff := range d {
+		if diff.PendingModification {
+			return true
+		}
+	}
+	return false
+}
+
+func (d AllocationDiffs) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+func (d AllocationDiffs) PrintPretty() {
+	fmt.Println()
+	fmt.Println("Allocations to be Updated")
+	headers := []string{
+		"Strategy",
+		"AVS",
+		"Operator Set ID",
+		"Current Magnitude",
+		"New Magnitude",
+		"Delta",
+		"Slashable Shares (Wei)",
+		"Type",
+		"Effect Block",
+	}
+	widths := []int{15, 15, 16, 26, 26, 27, 30, 13, 13}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+
+	// Print header
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+
+	// Print separator
+	for _, width := range widths {
+		fmt.Print("|", strings.Repeat("-", width+1))
+	}
+	fmt.Println("|")
+
+	// Print data rows
+	for _, diff := range d {
+		delta := formatSignedDelta(diff.Delta)
+		fmt.Printf(
+			"| %-*s| %-*s| %-*d| %-*s| %-*s| %-*s| %-*s| %-*s| %-*d|\n",
+			widths[0], common.ShortEthAddress(diff.StrategyAddress),
+			widths[1], common.ShortEthAddress(diff.AvsAddress),
+			widths[2], diff.OperatorSetId,
+			widths[3], common.FormatNumberWithUnderscores(common.Uint64ToString(diff.CurrentMagnitude)),
+			widths[4], common.FormatNumberWithUnderscores(common.Uint64ToString(diff.NewMagnitude)),
+			widths[5], delta,
+			widths[6], common.FormatNumberWithUnderscores(diff.NewSlashableShares.String()),
+			widths[7], diff.Type,
+			widths[8], diff.EffectBlock,
+		)
+	}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+
+	for _, diff := range d {
+		if diff.Type == DeallocationChangeType {
+			fmt.Println("Deallocations stay slashable until their effect block because of the deallocation delay")
+			break
+		}
+	}
+	if d.HasPendingModifications() {
+		fmt.Printf(
+			"%s Some allocations already have a pending modification. "+
+				"The transaction will revert until it has taken effect\n",
+			utils.EmojiWarning,
+		)
+	}
+}
+
+// formatSignedDelta formats delta with underscores and a leading + if it's positive
+func formatSignedDelta(delta *big.Int) string {
+	formatted := common.FormatNumberWithUnderscores(delta.String())
+	if delta.Sign() > 0 {
+		formatted = "+" + formatted
+	}
+	return formatted
+}
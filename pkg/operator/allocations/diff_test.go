@@ -0,0 +1,157 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAllocationDiffReader struct {
+	maxMagnitudes   map[gethcommon.Address]uint64
+	shares          map[gethcommon.Address]*big.Int
+	allocationInfo  map[gethcommon.Address][]elcontracts.AllocationInfo
+	slashableSets   map[allocationmanager.OperatorSet]bool
+	allocationDelay uint32
+}
+
+func (f *fakeAllocationDiffReader) GetMaxMagnitudes(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]uint64, error) {
+	magnitudes := make([]uint64, len(strategyAddresses))
+	for i, strategy := range strategyAddresses {
+		magnitudes[i] = f.maxMagnitudes[strategy]
+	}
+	return magnitudes, nil
+}
+
+func (f *fakeAllocationDiffReader) GetAllocationInfo(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) ([]elcontracts.AllocationInfo, error) {
+	return f.allocationInfo[strategyAddress], nil
+}
+
+func (f *fakeAllocationDiffReader) GetOperatorShares(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]*big.Int, error) {
+	shares := make([]*big.Int, len(strategyAddresses))
+	for i, strategy := range strategyAddresses {
+		shares[i] = f.shares[strategy]
+	}
+	return shares, nil
+}
+
+func (f *fakeAllocationDiffReader) GetAllocationDelay(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+) (uint32, error) {
+	return f.allocationDelay, nil
+}
+
+func (f *fakeAllocationDiffReader) GetDeallocationDelay(ctx context.Context) (uint32, error) {
+	return 100, nil
+}
+
+func (f *fakeAllocationDiffReader) IsOperatorSlashable(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	return f.slashableSets[operatorSet], nil
+}
+
+func TestComputeAllocationDiff(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	registeredSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 1}
+	unregisteredSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 2}
+	newSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 3}
+
+	reader := &fakeAllocationDiffReader{
+		maxMagnitudes: map[gethcommon.Address]uint64{strategyAddress: 1000},
+		shares:        map[gethcommon.Address]*big.Int{strategyAddress: big.NewInt(1e6)},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+			strategyAddress: {
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    1,
+					CurrentMagnitude: big.NewInt(500),
+					PendingDiff:      big.NewInt(0),
+				},
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    2,
+					CurrentMagnitude: big.NewInt(200),
+					PendingDiff:      big.NewInt(-100),
+					EffectBlock:      1050,
+				},
+			},
+		},
+		slashableSets:   map[allocationmanager.OperatorSet]bool{registeredSet: true, newSet: true},
+		allocationDelay: 10,
+	}
+
+	allocations := []allocationmanager.IAllocationManagerTypesAllocateParams{
+		{OperatorSet: registeredSet, Strategies: []gethcommon.Address{strategyAddress}, NewMagnitudes: []uint64{300}},
+		{OperatorSet: unregisteredSet, Strategies: []gethcommon.Address{strategyAddress}, NewMagnitudes: []uint64{0}},
+		{OperatorSet: newSet, Strategies: []gethcommon.Address{strategyAddress}, NewMagnitudes: []uint64{100}},
+	}
+
+	diffs, err := computeAllocationDiff(context.Background(), reader, operatorAddress, allocations, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, AllocationDiffs{
+		{
+			AvsAddress:             avsAddress,
+			OperatorSetId:          1,
+			StrategyAddress:        strategyAddress,
+			CurrentMagnitude:       500,
+			NewMagnitude:           300,
+			Delta:                  big.NewInt(-200),
+			CurrentSlashableShares: big.NewInt(500_000),
+			NewSlashableShares:     big.NewInt(300_000),
+			Type:                   DeallocationChangeType,
+			EffectBlock:            1102,
+		},
+		{
+			AvsAddress:             avsAddress,
+			OperatorSetId:          2,
+			StrategyAddress:        strategyAddress,
+			CurrentMagnitude:       200,
+			NewMagnitude:           0,
+			Delta:                  big.NewInt(-200),
+			CurrentSlashableShares: big.NewInt(200_000),
+			NewSlashableShares:     big.NewInt(0),
+			Type:                   DeallocationChangeType,
+			EffectBlock:            1001,
+			PendingModification:    true,
+		},
+		{
+			AvsAddress:             avsAddress,
+			OperatorSetId:          3,
+			StrategyAddress:        strategyAddress,
+			CurrentMagnitude:       0,
+			NewMagnitude:           100,
+			Delta:                  big.NewInt(100),
+			CurrentSlashableShares: big.NewInt(0),
+			NewSlashableShares:     big.NewInt(100_000),
+			Type:                   AllocationChangeType,
+			EffectBlock:            1011,
+		},
+	}, diffs)
+	assert.True(t, diffs.HasPendingModifications())
+}
@@ -0,0 +1,196 @@
+package allocations
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	strategy "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IStrategy"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// beaconChainETHStrategyAddress is the virtual strategy of native restaked ETH. It has no underlying token.
+var beaconChainETHStrategyAddress = gethcommon.HexToAddress("0xbeaC0eeEeeeeEEeEeEEEEeeEEeEeeeEeeEEBEaC0")
+
+const beaconChainETHTokenName = "Beacon Chain ETH"
+
+// delegatedSharesLookbackBlocks is the number of blocks whose OperatorSharesIncreased events are read to find
+// the strategies stakers delegated to the operator in, about 90 days of mainnet blocks
+const delegatedSharesLookbackBlocks = 648_000
+
+type strategyDiscoveryReader interface {
+	GetRegisteredSets(ctx context.Context, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	GetAllocatedSets(ctx context.Context, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	GetAllocatedStrategies(
+		ctx context.Context,
+		operator gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) ([]gethcommon.Address, error)
+	GetStrategiesInOperatorSet(
+		ctx context.Context,
+		operatorSet allocationmanager.OperatorSet,
+	) ([]gethcommon.Address, error)
+}
+
+type strategyTokenReader interface {
+	GetStrategyAndUnderlyingToken(
+		ctx context.Context,
+		strategyAddr gethcommon.Address,
+	) (*strategy.ContractIStrategy, gethcommon.Address, error)
+}
+
+// discoverStrategies returns the strategies of the operator sets the operator is registered for and
+// the strategies the operator has allocations in, including allocations to operator sets the operator
+// has deregistered from.
+func discoverStrategies(
+	ctx context.Context,
+	reader strategyDiscoveryReader,
+	operatorAddress gethcommon.Address,
+) ([]gethcommon.Address, error) {
+	strategies := make(map[gethcommon.Address]bool)
+
+	registeredSets, err := reader.GetRegisteredSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, opSet := range registeredSets {
+		setStrategies, err := reader.GetStrategiesInOperatorSet(ctx, opSet)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range setStrategies {
+			strategies[s] = true
+		}
+	}
+
+	allocatedSets, err := reader.GetAllocatedSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, opSet := range allocatedSets {
+		allocatedStrategies, err := reader.GetAllocatedStrategies(ctx, operatorAddress, opSet)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range allocatedStrategies {
+			strategies[s] = true
+		}
+	}
+
+	return sortedStrategies(strategies), nil
+}
+
+// discoverOperatorStrategies returns the strategies of the registered and allocated operator sets of the
+// operator together with the strategies stakers delegated to the operator in, as of the block of the reader
+func discoverOperatorStrategies(
+	ctx context.Context,
+	reader *batchreader.Reader,
+	ethClient *ethclient.Client,
+	operatorAddress gethcommon.Address,
+	logger logging.Logger,
+) ([]gethcommon.Address, error) {
+	delegationManager, err := delegationmanager.NewContractDelegationManager(
+		reader.DelegationManagerAddress(),
+		ethClient,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies, err := discoverStrategies(ctx, reader, operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	delegatedStrategies, err := getDelegatedStrategies(ctx, delegationManager, operatorAddress, reader.BlockNumber())
+	if err != nil {
+		// Some RPC providers limit the block range of log queries, so we only warn here
+		logger.Warnf("Failed to get delegated strategies from events, using operator set strategies only: %s", err)
+		return strategies, nil
+	}
+	strategies = mergeStrategies(strategies, delegatedStrategies)
+	logger.Debugf("Discovered strategies: %v", strategies)
+	return strategies, nil
+}
+
+// getDelegatedStrategies returns the strategies stakers delegated shares in to the operator, from the
+// OperatorSharesIncreased events of the DelegationManager in the delegatedSharesLookbackBlocks blocks up to toBlock
+func getDelegatedStrategies(
+	ctx context.Context,
+	delegationManager *delegationmanager.ContractDelegationManager,
+	operatorAddress gethcommon.Address,
+	toBlock uint64,
+) ([]gethcommon.Address, error) {
+	strategies := make(map[gethcommon.Address]bool)
+	fromBlock := common.LookbackStartBlock(toBlock, delegatedSharesLookbackBlocks)
+	err := common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		iterator, err := delegationManager.FilterOperatorSharesIncreased(opts, []gethcommon.Address{operatorAddress})
+		if err != nil {
+			return err
+		}
+		defer iterator.Close()
+
+		for iterator.Next() {
+			strategies[iterator.Event.Strategy] = true
+		}
+		return iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedStrategies(strategies), nil
+}
+
+func mergeStrategies(a []gethcommon.Address, b []gethcommon.Address) []gethcommon.Address {
+	strategies := make(map[gethcommon.Address]bool, len(a)+len(b))
+	for _, s := range append(a, b...) {
+		strategies[s] = true
+	}
+	return sortedStrategies(strategies)
+}
+
+func sortedStrategies(strategies map[gethcommon.Address]bool) []gethcommon.Address {
+	sorted := make([]gethcommon.Address, 0, len(strategies))
+	for s := range strategies {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0
+	})
+	return sorted
+}
+
+// getStrategyTokenNames returns the name of the underlying token of each strategy. Strategies whose
+// token can't be read are labeled with erc20.UnknownTokenName.
+func getStrategyTokenNames(
+	ctx context.Context,
+	reader strategyTokenReader,
+	ethClient *ethclient.Client,
+	strategies []gethcommon.Address,
+	logger logging.Logger,
+) map[gethcommon.Address]string {
+	tokenNames := make(map[gethcommon.Address]string, len(strategies))
+	for _, strategyAddress := range strategies {
+		if strategyAddress == beaconChainETHStrategyAddress {
+			tokenNames[strategyAddress] = beaconChainETHTokenName
+			continue
+		}
+		_, tokenAddress, err := reader.GetStrategyAndUnderlyingToken(ctx, strategyAddress)
+		if err != nil {
+			logger.Debugf("Failed to get underlying token of strategy %s: %s", strategyAddress.Hex(), err)
+			tokenNames[strategyAddress] = erc20.UnknownTokenName
+			continue
+		}
+		tokenNames[strategyAddress] = erc20.GetTokenName(tokenAddress, ethClient)
+	}
+	return tokenNames
+}
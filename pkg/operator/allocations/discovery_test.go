@@ -0,0 +1,91 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStrategyDiscoveryReader struct {
+	registeredSets      []allocationmanager.OperatorSet
+	allocatedSets       []allocationmanager.OperatorSet
+	setStrategies       map[allocationmanager.OperatorSet][]gethcommon.Address
+	allocatedStrategies map[allocationmanager.OperatorSet][]gethcommon.Address
+}
+
+func (f *fakeStrategyDiscoveryReader) GetRegisteredSets(
+	ctx context.Context,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func (f *fakeStrategyDiscoveryReader) GetAllocatedSets(
+	ctx context.Context,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.allocatedSets, nil
+}
+
+func (f *fakeStrategyDiscoveryReader) GetAllocatedStrategies(
+	ctx context.Context,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.allocatedStrategies[operatorSet], nil
+}
+
+func (f *fakeStrategyDiscoveryReader) GetStrategiesInOperatorSet(
+	ctx context.Context,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.setStrategies[operatorSet], nil
+}
+
+func TestDiscoverStrategies(t *testing.T) {
+	avsAddress := gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	strategyA := gethcommon.HexToAddress("0x000000000000000000000000000000000000000a")
+	strategyB := gethcommon.HexToAddress("0x000000000000000000000000000000000000000b")
+	strategyC := gethcommon.HexToAddress("0x000000000000000000000000000000000000000c")
+	registeredSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 0}
+	deregisteredSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 1}
+
+	reader := &fakeStrategyDiscoveryReader{
+		registeredSets: []allocationmanager.OperatorSet{registeredSet},
+		allocatedSets:  []allocationmanager.OperatorSet{registeredSet, deregisteredSet},
+		setStrategies: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			registeredSet: {strategyB, strategyA},
+		},
+		allocatedStrategies: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			registeredSet:   {strategyA},
+			deregisteredSet: {strategyC},
+		},
+	}
+
+	strategies, err := discoverStrategies(context.Background(), reader, gethcommon.Address{})
+	assert.NoError(t, err)
+	assert.Equal(t, []gethcommon.Address{strategyA, strategyB, strategyC}, strategies)
+
+	merged := mergeStrategies(strategies, []gethcommon.Address{beaconChainETHStrategyAddress, strategyA})
+	assert.Equal(t, []gethcommon.Address{strategyA, strategyB, strategyC, beaconChainETHStrategyAddress}, merged)
+}
+
+func TestFilterAllocationsByAvs(t *testing.T) {
+	avsA := gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	avsB := gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	allocations := []elcontracts.AllocationInfo{
+		{AvsAddress: avsA, OperatorSetId: 1, CurrentMagnitude: big.NewInt(1)},
+		{AvsAddress: avsB, OperatorSetId: 2, CurrentMagnitude: big.NewInt(2)},
+	}
+
+	assert.Equal(t, allocations, filterAllocationsByAvs(allocations, nil))
+	assert.Equal(t, allocations[1:], filterAllocationsByAvs(allocations, []gethcommon.Address{avsB}))
+	assert.Empty(t, filterAllocationsByAvs(allocations, []gethcommon.Address{{}}))
+}
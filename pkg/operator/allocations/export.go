@@ -0,0 +1,244 @@
+package allocations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gocarina/gocsv"
+	"github.com/urfave/cli/v2"
+)
+
+type allocationExportReader interface {
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+}
+
+func ExportCmd(p utils.Prompter) *cli.Command {
+	exportCmd := &cli.Command{
+		Name:      "export",
+		Usage:     "Export allocations as an update file",
+		UsageText: "export",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to export the allocations of the operator in the format read by 'allocations update --csv-file'
+
+The columns are avs_address, operator_set_id, strategy_address and bips. The bips are computed from the
+magnitude of each allocation and the max magnitude of the strategy, so the file can be edited and applied
+again without converting numbers. Allocations with a pending modification are exported with the magnitude
+they will have once the modification takes effect. Allocations of zero are not exported.
+
+Rows which are left unchanged, and rows of allocations with a pending modification, are skipped by
+'allocations update --csv-file', so only the edited rows are submitted.
+`,
+		Flags: getExportFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return exportAction(cCtx)
+		},
+	}
+	return exportCmd
+}
+
+func exportAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateExportConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate export config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			DelegationManagerAddress: config.delegationManagerAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	allocations, err := getAllocationsToExport(
+		ctx,
+		elReader,
+		config.operatorAddress,
+		config.strategyAddresses,
+		config.avsAddresses,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get allocations", err)
+	}
+	if len(allocations) == 0 {
+		logger.Warn("No allocations found for the given strategies")
+	}
+
+	allocationsCsv, err := gocsv.MarshalString(&allocations)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to marshal allocations to csv", err)
+	}
+	if !common.IsEmptyString(config.output) {
+		err = common.WriteToFile([]byte(allocationsCsv), config.output)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Allocations written to file: %s", config.output)
+	} else {
+		fmt.Print(allocationsCsv)
+	}
+	return nil
+}
+
+// getAllocationsToExport returns the allocations of the operator to the strategies in the update file format.
+// If avsAddresses is not empty, only allocations to operator sets of those AVSs are returned.
+func getAllocationsToExport(
+	ctx context.Context,
+	reader allocationExportReader,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+	avsAddresses []gethcommon.Address,
+) ([]allocation, error) {
+	avsFilter := make(map[gethcommon.Address]bool, len(avsAddresses))
+	for _, avsAddress := range avsAddresses {
+		avsFilter[avsAddress] = true
+	}
+
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategyAddresses)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+
+	allocations := make([]allocation, 0)
+	for i, strategyAddress := range strategyAddresses {
+		allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, strategyAddress)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+		}
+		for _, info := range allocationInfo {
+			if len(avsFilter) > 0 && !avsFilter[info.AvsAddress] {
+				continue
+			}
+			magnitude := new(big.Int).Set(info.CurrentMagnitude)
+			if info.PendingDiff != nil {
+				magnitude.Add(magnitude, info.PendingDiff)
+			}
+			bips := CalculateBipsFromMagnitude(magnitude.Uint64(), maxMagnitudes[i])
+			if bips == 0 {
+				continue
+			}
+			allocations = append(allocations, allocation{
+				AvsAddress:      info.AvsAddress,
+				OperatorSetId:   info.OperatorSetId,
+				StrategyAddress: strategyAddress,
+				Bips:            bips,
+			})
+		}
+	}
+
+	sort.SliceStable(allocations, func(i, j int) bool {
+		if allocations[i].AvsAddress != allocations[j].AvsAddress {
+			return allocations[i].AvsAddress.Hex() < allocations[j].AvsAddress.Hex()
+		}
+		return allocations[i].OperatorSetId < allocations[j].OperatorSetId
+	})
+	return allocations, nil
+}
+
+// CalculateBipsFromMagnitude is the inverse of CalculateMagnitudeToUpdate. The bips are rounded to the
+// nearest value, so magnitudes which were set from bips are converted back to the same bips.
+func CalculateBipsFromMagnitude(magnitude uint64, totalMagnitude uint64) uint64 {
+	if totalMagnitude == 0 {
+		return 0
+	}
+	bigBips := new(big.Int).Mul(new(big.Int).SetUint64(magnitude), big.NewInt(10_000))
+	bigTotalMagnitude := new(big.Int).SetUint64(totalMagnitude)
+	bigBips.Add(bigBips, new(big.Int).Div(bigTotalMagnitude, big.NewInt(2)))
+	return bigBips.Div(bigBips, bigTotalMagnitude).Uint64()
+}
+
+func readAndValidateExportConfig(cCtx *cli.Context, logger logging.Logger) (*exportConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+
+	strategyAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.StrategyAddressesFlag.Name))
+	if len(strategyAddresses) == 0 {
+		logger.Error("--strategy-addresses flag must be set")
+		return nil, errors.New("Empty strategy addresses provided")
+	}
+	avsAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.AVSAddressesFlag.Name))
+
+	chainId := utils.NetworkNameToChainId(network)
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	var err error
+	if delegationManagerAddress == "" {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &exportConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		environment:              environment,
+		chainID:                  chainId,
+		output:                   output,
+		operatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+		avsAddresses:             avsAddresses,
+		strategyAddresses:        strategyAddresses,
+	}, nil
+}
+
+func getExportFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.OperatorAddressFlag,
+		&flags.AVSAddressesFlag,
+		&flags.StrategyAddressesFlag,
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.VerboseFlag,
+		&flags.OutputFileFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
@@ -0,0 +1,95 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/gocarina/gocsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAllocationsToExport(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	otherAvsAddress := gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	maxMagnitude := uint64(initialMagnitude - 12_345)
+
+	reader := &fakeAllocationDiffReader{
+		maxMagnitudes: map[gethcommon.Address]uint64{strategyAddress: maxMagnitude},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+			strategyAddress: {
+				{
+					AvsAddress:       otherAvsAddress,
+					OperatorSetId:    2,
+					CurrentMagnitude: new(big.Int).SetUint64(CalculateMagnitudeToUpdate(maxMagnitude, 2500)),
+					PendingDiff:      big.NewInt(0),
+				},
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    1,
+					CurrentMagnitude: new(big.Int).SetUint64(CalculateMagnitudeToUpdate(maxMagnitude, 1000)),
+					PendingDiff:      new(big.Int).SetUint64(CalculateMagnitudeToUpdate(maxMagnitude, 333)),
+				},
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    3,
+					CurrentMagnitude: new(big.Int).SetUint64(CalculateMagnitudeToUpdate(maxMagnitude, 500)),
+					PendingDiff:      new(big.Int).Neg(new(big.Int).SetUint64(CalculateMagnitudeToUpdate(maxMagnitude, 500))),
+				},
+			},
+		},
+	}
+
+	allocations, err := getAllocationsToExport(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]gethcommon.Address{strategyAddress},
+		nil,
+	)
+	assert.NoError(t, err)
+	expected := []allocation{
+		{AvsAddress: avsAddress, OperatorSetId: 1, StrategyAddress: strategyAddress, Bips: 1333},
+		{AvsAddress: otherAvsAddress, OperatorSetId: 2, StrategyAddress: strategyAddress, Bips: 2500},
+	}
+	assert.Equal(t, expected, allocations)
+
+	filtered, err := getAllocationsToExport(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]gethcommon.Address{strategyAddress},
+		[]gethcommon.Address{otherAvsAddress},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[1:], filtered)
+
+	// The exported file can be read back by allocations update
+	allocationsCsv, err := gocsv.MarshalString(&allocations)
+	assert.NoError(t, err)
+	filePath := filepath.Join(t.TempDir(), "allocations.csv")
+	assert.NoError(t, os.WriteFile(filePath, []byte(allocationsCsv), 0o644))
+	parsed, err := parseAllocationsCSV(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, allocations, parsed)
+}
+
+func TestCalculateBipsFromMagnitude(t *testing.T) {
+	for _, totalMagnitude := range []uint64{initialMagnitude, 123_456_789, 10_000} {
+		for _, bips := range []uint64{0, 1, 333, 5000, 9999, 10_000} {
+			magnitude := CalculateMagnitudeToUpdate(totalMagnitude, bips)
+			assert.Equal(t, bips, CalculateBipsFromMagnitude(magnitude, totalMagnitude))
+		}
+	}
+	assert.Equal(t, uint64(0), CalculateBipsFromMagnitude(100, 0))
+}
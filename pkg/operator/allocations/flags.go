@@ -10,6 +10,41 @@ var (
 		EnvVars: []string{"BIPS_TO_ALLOCATE"},
 	}
 
+	TargetFileFlag = cli.StringFlag{
+		Name:    "target-file",
+		Aliases: []string{"tf"},
+		Usage:   "YAML or CSV file with the target allocation of each strategy across all operator sets",
+		EnvVars: []string{"TARGET_FILE"},
+	}
+
+	PolicyFileFlag = cli.StringFlag{
+		Name:    "policy",
+		Aliases: []string{"pf"},
+		Usage:   "YAML file with the allocation policy the allocations are checked against",
+		EnvVars: []string{"ALLOCATIONS_POLICY"},
+	}
+
+	OverridePolicyFlag = cli.BoolFlag{
+		Name:    "override-policy",
+		Aliases: []string{"op"},
+		Usage:   "Allow allocations which violate the allocation policy, after confirmation when broadcasting",
+		EnvVars: []string{"OVERRIDE_POLICY"},
+	}
+
+	FromBlockFlag = cli.Uint64Flag{
+		Name:    "from-block",
+		Aliases: []string{"fb"},
+		Usage:   "First block of the range to read events from. Defaults to 648,000 blocks before --to-block",
+		EnvVars: []string{"FROM_BLOCK"},
+	}
+
+	ToBlockFlag = cli.Uint64Flag{
+		Name:    "to-block",
+		Aliases: []string{"tb"},
+		Usage:   "Last block of the range to read events from. Defaults to the latest block",
+		EnvVars: []string{"TO_BLOCK"},
+	}
+
 	EnvironmentFlag = cli.StringFlag{
 		Name:    "environment",
 		Aliases: []string{"env"},
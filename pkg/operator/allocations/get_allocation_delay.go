@@ -0,0 +1,301 @@
+package allocations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+type allocationDelaySetEvent = allocationmanager.ContractAllocationManagerAllocationDelaySet
+
+type allocationDelayReader interface {
+	GetAllocationDelay(opts *bind.CallOpts, operator gethcommon.Address) (bool, uint32, error)
+	ALLOCATIONCONFIGURATIONDELAY(opts *bind.CallOpts) (uint32, error)
+}
+
+type AllocationDelayInfo struct {
+	OperatorAddress    gethcommon.Address `json:"operator_address"`
+	CurrentBlock       uint64             `json:"current_block"`
+	IsSet              bool               `json:"is_set"`
+	CurrentDelay       uint32             `json:"current_delay"`
+	PendingDelay       *uint32            `json:"pending_delay,omitempty"`
+	PendingEffectBlock *uint32            `json:"pending_effect_block,omitempty"`
+	ConfigurationDelay uint32             `json:"configuration_delay"`
+}
+
+func GetDelayCmd(p utils.Prompter) *cli.Command {
+	getDelayCmd := &cli.Command{
+		Name:      "get-delay",
+		Usage:     "Get the allocation delay of the operator",
+		UsageText: "get-delay",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to get the current allocation delay of the operator and the pending delay change, if any
+`,
+		Flags: getGetAllocationDelayFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return getDelayAction(cCtx)
+		},
+	}
+	return getDelayCmd
+}
+
+func getDelayAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateGetAllocationDelayConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate get delay config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	info, err := readAllocationDelayInfo(ctx, ethClient, config.delegationManagerAddress, config.operatorAddress, logger)
+	if err != nil {
+		return err
+	}
+
+	if config.outputType == utils.JsonOutputType {
+		infoJson, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(infoJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Allocation delay written to file: %s", config.output)
+		} else {
+			fmt.Println(string(infoJson))
+		}
+		return nil
+	}
+
+	if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+	info.PrintPretty(getAverageBlockTime(ctx, ethClient, info.CurrentBlock, logger), time.Now())
+	return nil
+}
+
+// readAllocationDelayInfo reads the allocation delay of the operator from the AllocationManager. The pending
+// delay is not exposed by the contract, so it is read from the AllocationDelaySet events.
+func readAllocationDelayInfo(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	delegationManagerAddress gethcommon.Address,
+	operatorAddress gethcommon.Address,
+	logger logging.Logger,
+) (*AllocationDelayInfo, error) {
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress: delegationManagerAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	currentBlock, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get current block number", err)
+	}
+
+	configurationDelay, err := contractBindings.AllocationManager.ALLOCATIONCONFIGURATIONDELAY(
+		&bind.CallOpts{Context: ctx},
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get allocation configuration delay", err)
+	}
+	// A delay set more than the configuration delay ago has already taken effect, so only the events of the
+	// last configuration delay can hold a pending delay
+	latestDelaySet, err := getLatestAllocationDelaySet(
+		ctx,
+		contractBindings.AllocationManager,
+		operatorAddress,
+		common.LookbackStartBlock(currentBlock, uint64(configurationDelay)+1),
+		currentBlock,
+	)
+	if err != nil {
+		// Some RPC providers limit log queries, so we only warn here
+		logger.Warnf("Failed to get allocation delay events, pending delay changes are not shown: %s", err)
+	}
+
+	return getAllocationDelayInfo(
+		ctx,
+		contractBindings.AllocationManager,
+		operatorAddress,
+		latestDelaySet,
+		currentBlock,
+	)
+}
+
+func getAllocationDelayInfo(
+	ctx context.Context,
+	reader allocationDelayReader,
+	operatorAddress gethcommon.Address,
+	latestDelaySet *allocationDelaySetEvent,
+	currentBlock uint64,
+) (*AllocationDelayInfo, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	isSet, delay, err := reader.GetAllocationDelay(opts, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get allocation delay", err)
+	}
+	configurationDelay, err := reader.ALLOCATIONCONFIGURATIONDELAY(opts)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get allocation configuration delay", err)
+	}
+
+	info := &AllocationDelayInfo{
+		OperatorAddress:    operatorAddress,
+		CurrentBlock:       currentBlock,
+		IsSet:              isSet,
+		CurrentDelay:       delay,
+		ConfigurationDelay: configurationDelay,
+	}
+	if latestDelaySet != nil && uint64(latestDelaySet.EffectBlock) > currentBlock {
+		info.PendingDelay = &latestDelaySet.Delay
+		info.PendingEffectBlock = &latestDelaySet.EffectBlock
+	}
+	return info, nil
+}
+
+// getLatestAllocationDelaySet returns the last AllocationDelaySet event of the operator from fromBlock to
+// toBlock, or nil if the operator didn't set an allocation delay in those blocks
+func getLatestAllocationDelaySet(
+	ctx context.Context,
+	allocationManager *allocationmanager.ContractAllocationManager,
+	operatorAddress gethcommon.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) (*allocationDelaySetEvent, error) {
+	var latest *allocationDelaySetEvent
+	err := common.FilterLatestLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) (bool, error) {
+		iterator, err := allocationManager.FilterAllocationDelaySet(opts)
+		if err != nil {
+			return false, err
+		}
+		defer iterator.Close()
+		for iterator.Next() {
+			// The event has no indexed fields, so we filter by operator here
+			if iterator.Event.Operator == operatorAddress {
+				event := *iterator.Event
+				latest = &event
+			}
+		}
+		return latest != nil, iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// earliestNewDelayEffectBlock is the earliest block at which a new allocation delay takes effect. The contract
+// sets the effect block to the block of the transaction plus the configuration delay plus one, and the
+// transaction is included in the block after currentBlock at the earliest.
+func (i *AllocationDelayInfo) earliestNewDelayEffectBlock() uint64 {
+	return i.CurrentBlock + 2 + uint64(i.ConfigurationDelay)
+}
+
+func (i *AllocationDelayInfo) PrintPretty(averageBlockTime time.Duration, now time.Time) {
+	if i.IsSet {
+		fmt.Printf("Current allocation delay: %d blocks\n", i.CurrentDelay)
+	} else {
+		fmt.Println("Current allocation delay: not set")
+	}
+	if i.PendingDelay != nil {
+		fmt.Printf(
+			"Pending allocation delay: %d blocks, effective at block %d (%s UTC)\n",
+			*i.PendingDelay,
+			*i.PendingEffectBlock,
+			estimateBlockTime(uint64(*i.PendingEffectBlock), i.CurrentBlock, averageBlockTime, now),
+		)
+	}
+	fmt.Printf("Allocation configuration delay: %d blocks\n", i.ConfigurationDelay)
+}
+
+func estimateBlockTime(block uint64, currentBlock uint64, averageBlockTime time.Duration, now time.Time) string {
+	blocksRemaining := uint64(0)
+	if block > currentBlock {
+		blocksRemaining = block - currentBlock
+	}
+	return now.Add(time.Duration(blocksRemaining) * averageBlockTime).UTC().Format(time.DateTime)
+}
+
+func getGetAllocationDelayFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func readAndValidateGetAllocationDelayConfig(
+	cCtx *cli.Context,
+	logger logging.Logger,
+) (*getAllocationDelayConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	var err error
+	if delegationManagerAddress == "" {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &getAllocationDelayConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		environment:              environment,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		operatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+	}, nil
+}
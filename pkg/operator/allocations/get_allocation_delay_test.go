@@ -0,0 +1,97 @@
+package allocations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAllocationDelayReader struct {
+	isSet bool
+	delay uint32
+}
+
+func (f *fakeAllocationDelayReader) GetAllocationDelay(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+) (bool, uint32, error) {
+	return f.isSet, f.delay, nil
+}
+
+func (f *fakeAllocationDelayReader) ALLOCATIONCONFIGURATIONDELAY(opts *bind.CallOpts) (uint32, error) {
+	return 126_000, nil
+}
+
+func TestGetAllocationDelayInfo(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	pendingDelay := uint32(50)
+	pendingEffectBlock := uint32(1_500)
+
+	tests := []struct {
+		name           string
+		reader         *fakeAllocationDelayReader
+		latestDelaySet *allocationDelaySetEvent
+		expectedInfo   *AllocationDelayInfo
+	}{
+		{
+			name:   "delay not set",
+			reader: &fakeAllocationDelayReader{},
+			expectedInfo: &AllocationDelayInfo{
+				OperatorAddress:    operatorAddress,
+				CurrentBlock:       1_000,
+				ConfigurationDelay: 126_000,
+			},
+		},
+		{
+			name:           "delay change already effective",
+			reader:         &fakeAllocationDelayReader{isSet: true, delay: 10},
+			latestDelaySet: &allocationDelaySetEvent{Operator: operatorAddress, Delay: 10, EffectBlock: 900},
+			expectedInfo: &AllocationDelayInfo{
+				OperatorAddress:    operatorAddress,
+				CurrentBlock:       1_000,
+				IsSet:              true,
+				CurrentDelay:       10,
+				ConfigurationDelay: 126_000,
+			},
+		},
+		{
+			name:   "delay change pending",
+			reader: &fakeAllocationDelayReader{isSet: true, delay: 10},
+			latestDelaySet: &allocationDelaySetEvent{
+				Operator:    operatorAddress,
+				Delay:       pendingDelay,
+				EffectBlock: pendingEffectBlock,
+			},
+			expectedInfo: &AllocationDelayInfo{
+				OperatorAddress:    operatorAddress,
+				CurrentBlock:       1_000,
+				IsSet:              true,
+				CurrentDelay:       10,
+				PendingDelay:       &pendingDelay,
+				PendingEffectBlock: &pendingEffectBlock,
+				ConfigurationDelay: 126_000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := getAllocationDelayInfo(
+				context.Background(),
+				tt.reader,
+				operatorAddress,
+				tt.latestDelaySet,
+				1_000,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedInfo, info)
+			assert.Equal(t, uint64(127_002), info.earliestNewDelayEffectBlock())
+		})
+	}
+}
@@ -0,0 +1,761 @@
+package allocations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	AllocationUpdatedEventType   = "allocation_updated"
+	AllocationDelaySetEventType  = "allocation_delay_set"
+	MaxMagnitudeUpdatedEventType = "max_magnitude_updated"
+	OperatorSlashedEventType     = "operator_slashed"
+)
+
+// historyLookbackBlocks is the number of blocks read when --from-block is not set, about 90 days of mainnet blocks
+const historyLookbackBlocks = 648_000
+
+// wad is the fixed point unit of the slashed proportion, 1e18 is 100%
+var wad = big.NewInt(1e18)
+
+// historicalStateReader reads the state of the AllocationManager and DelegationManager at a past block.
+// It requires an archive node.
+type historicalStateReader interface {
+	GetAllocation(
+		opts *bind.CallOpts,
+		operator gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+		strategy gethcommon.Address,
+	) (allocationmanager.IAllocationManagerTypesAllocation, error)
+	GetMaxMagnitudes(
+		opts *bind.CallOpts,
+		operator gethcommon.Address,
+		strategies []gethcommon.Address,
+	) ([]uint64, error)
+	GetOperatorShares(
+		opts *bind.CallOpts,
+		operator gethcommon.Address,
+		strategies []gethcommon.Address,
+	) ([]*big.Int, error)
+}
+
+type bindingsHistoricalStateReader struct {
+	allocationManager *allocationmanager.ContractAllocationManager
+	delegationManager *delegationmanager.ContractDelegationManager
+}
+
+func (r *bindingsHistoricalStateReader) GetAllocation(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategy gethcommon.Address,
+) (allocationmanager.IAllocationManagerTypesAllocation, error) {
+	return r.allocationManager.GetAllocation(opts, operator, operatorSet, strategy)
+}
+
+func (r *bindingsHistoricalStateReader) GetMaxMagnitudes(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]uint64, error) {
+	return r.allocationManager.GetMaxMagnitudes0(opts, operator, strategies)
+}
+
+func (r *bindingsHistoricalStateReader) GetOperatorShares(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]*big.Int, error) {
+	return r.delegationManager.GetOperatorShares(opts, operator, strategies)
+}
+
+// historyLogs are the AllocationManager events in the block range, for all operators
+type historyLogs struct {
+	allocationUpdates   []*allocationmanager.ContractAllocationManagerAllocationUpdated
+	delaySets           []*allocationmanager.ContractAllocationManagerAllocationDelaySet
+	maxMagnitudeUpdates []*allocationmanager.ContractAllocationManagerMaxMagnitudeUpdated
+	slashes             []*allocationmanager.ContractAllocationManagerOperatorSlashed
+}
+
+type AllocationHistory []AllocationHistoryEvent
+
+// AllocationHistoryEvent is a single entry of the allocation timeline of an operator. Only the fields of
+// its type are set.
+type AllocationHistoryEvent struct {
+	Type                 string              `json:"type"`
+	BlockNumber          uint64              `json:"block_number"`
+	Timestamp            string              `json:"timestamp,omitempty"`
+	TxHash               gethcommon.Hash     `json:"tx_hash"`
+	Sender               *gethcommon.Address `json:"sender,omitempty"`
+	AvsAddress           *gethcommon.Address `json:"avs_address,omitempty"`
+	OperatorSetId        *uint32             `json:"operator_set_id,omitempty"`
+	StrategyAddress      *gethcommon.Address `json:"strategy_address,omitempty"`
+	TokenName            string              `json:"token_name,omitempty"`
+	PreviousMagnitude    *uint64             `json:"previous_magnitude,omitempty"`
+	Magnitude            *uint64             `json:"magnitude,omitempty"`
+	EffectBlock          *uint32             `json:"effect_block,omitempty"`
+	AllocatedShares      *big.Int            `json:"allocated_shares,omitempty"`
+	PreviousMaxMagnitude *uint64             `json:"previous_max_magnitude,omitempty"`
+	MaxMagnitude         *uint64             `json:"max_magnitude,omitempty"`
+	SharesBefore         *big.Int            `json:"shares_before,omitempty"`
+	SharesAfter          *big.Int            `json:"shares_after,omitempty"`
+	Delay                *uint32             `json:"delay,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Slashes              []StrategySlash     `json:"slashes,omitempty"`
+
+	blockHash gethcommon.Hash
+	txIndex   uint
+	logIndex  uint
+}
+
+// StrategySlash is the slashing of a single strategy in an OperatorSlashed event
+type StrategySlash struct {
+	StrategyAddress gethcommon.Address `json:"strategy_address"`
+	TokenName       string             `json:"token_name,omitempty"`
+	WadSlashed      *big.Int           `json:"wad_slashed"`
+	Percentage      string             `json:"percentage"`
+	SharesBefore    *big.Int           `json:"shares_before,omitempty"`
+	SharesAfter     *big.Int           `json:"shares_after,omitempty"`
+}
+
+func HistoryCmd(p utils.Prompter) *cli.Command {
+	historyCmd := &cli.Command{
+		Name:      "history",
+		Usage:     "Show the allocation and slashing history of the operator",
+		UsageText: "history",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to show a timeline of the allocation changes, allocation delay changes, max magnitude changes and
+slashings of the operator in a block range
+
+Each entry shows the transaction and its sender. Magnitude changes show the previous and new magnitude.
+Slashings show the operator set, the description given by the AVS and the proportion slashed of each strategy.
+
+The shares at each entry are read at the block of the entry, which requires an archive node. If the RPC
+is not an archive node, the shares are not shown.
+
+Events are read in ranges of 10,000 blocks since RPC providers limit the range of log queries. If --from-block
+is not set, the history of the last 648,000 blocks (about 90 days on mainnet) is shown. Set --from-block to a
+block before the first allocation of the operator to read the full history.
+`,
+		Flags: getHistoryFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return historyAction(cCtx)
+		},
+	}
+	return historyCmd
+}
+
+func historyAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateHistoryConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate history config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress: config.delegationManagerAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	toBlock := config.toBlock
+	if toBlock == 0 {
+		toBlock, err = ethClient.BlockNumber(ctx)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get current block number", err)
+		}
+	}
+	fromBlock := common.LookbackStartBlock(toBlock, historyLookbackBlocks)
+	if config.fromBlock != nil {
+		fromBlock = *config.fromBlock
+	}
+	if fromBlock > toBlock {
+		return fmt.Errorf("--from-block %d is after --to-block %d", fromBlock, toBlock)
+	}
+	logger.Debugf("Reading allocation events from block %d to %d", fromBlock, toBlock)
+
+	logs, err := getHistoryLogs(ctx, contractBindings.AllocationManager, fromBlock, toBlock)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get allocation events", err)
+	}
+	history := buildAllocationHistory(logs, config.operatorAddress, config.avsAddresses, config.strategyAddresses)
+
+	stateReader := &bindingsHistoricalStateReader{
+		allocationManager: contractBindings.AllocationManager,
+		delegationManager: contractBindings.DelegationManager,
+	}
+	err = addHistoricalState(ctx, stateReader, config.operatorAddress, history)
+	if err != nil {
+		logger.Warnf("Failed to read state at past blocks, shares are not shown. An archive node is required: %s", err)
+	}
+	err = addBlockInfo(ctx, ethClient, history)
+	if err != nil {
+		logger.Warnf("Failed to read block timestamps and transaction senders: %s", err)
+	}
+	tokenNames := getStrategyTokenNames(ctx, elReader, ethClient, history.strategies(), logger)
+	history.setTokenNames(tokenNames)
+
+	if config.outputType == utils.JsonOutputType {
+		historyJson, err := json.MarshalIndent(history, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(historyJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Allocation history written to file: %s", config.output)
+		} else {
+			fmt.Println(string(historyJson))
+		}
+		return nil
+	}
+
+	if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+	fmt.Printf(
+		"------------------ Allocation History for %s (Blocks %d - %d) ---------------------\n",
+		config.operatorAddress.Hex(),
+		fromBlock,
+		toBlock,
+	)
+	history.PrintPretty()
+	return nil
+}
+
+// getHistoryLogs returns the AllocationManager events of all operators in the block range. None of the
+// events have indexed fields, so they can't be filtered by operator on the node. The range is read in
+// chunks, since RPC providers limit the block range of log queries.
+func getHistoryLogs(
+	ctx context.Context,
+	allocationManager *allocationmanager.ContractAllocationManager,
+	fromBlock uint64,
+	toBlock uint64,
+) (*historyLogs, error) {
+	logs := &historyLogs{}
+	err := common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		allocationIterator, err := allocationManager.FilterAllocationUpdated(opts)
+		if err != nil {
+			return err
+		}
+		defer allocationIterator.Close()
+		for allocationIterator.Next() {
+			logs.allocationUpdates = append(logs.allocationUpdates, allocationIterator.Event)
+		}
+		if allocationIterator.Error() != nil {
+			return allocationIterator.Error()
+		}
+
+		delayIterator, err := allocationManager.FilterAllocationDelaySet(opts)
+		if err != nil {
+			return err
+		}
+		defer delayIterator.Close()
+		for delayIterator.Next() {
+			logs.delaySets = append(logs.delaySets, delayIterator.Event)
+		}
+		if delayIterator.Error() != nil {
+			return delayIterator.Error()
+		}
+
+		maxMagnitudeIterator, err := allocationManager.FilterMaxMagnitudeUpdated(opts)
+		if err != nil {
+			return err
+		}
+		defer maxMagnitudeIterator.Close()
+		for maxMagnitudeIterator.Next() {
+			logs.maxMagnitudeUpdates = append(logs.maxMagnitudeUpdates, maxMagnitudeIterator.Event)
+		}
+		if maxMagnitudeIterator.Error() != nil {
+			return maxMagnitudeIterator.Error()
+		}
+
+		slashIterator, err := allocationManager.FilterOperatorSlashed(opts)
+		if err != nil {
+			return err
+		}
+		defer slashIterator.Close()
+		for slashIterator.Next() {
+			logs.slashes = append(logs.slashes, slashIterator.Event)
+		}
+		return slashIterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// buildAllocationHistory returns the events of the operator in chain order. Allocation updates and
+// slashings are filtered by AVS and strategy if any are given.
+func buildAllocationHistory(
+	logs *historyLogs,
+	operatorAddress gethcommon.Address,
+	avsAddresses []gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) AllocationHistory {
+	history := make(AllocationHistory, 0)
+
+	for _, e := range logs.allocationUpdates {
+		if e.Operator != operatorAddress ||
+			!common.MatchesAddressFilter(avsAddresses, e.OperatorSet.Avs) ||
+			!common.MatchesAddressFilter(strategyAddresses, e.Strategy) {
+			continue
+		}
+		event := newHistoryEvent(AllocationUpdatedEventType, e.Raw)
+		event.AvsAddress = &e.OperatorSet.Avs
+		event.OperatorSetId = &e.OperatorSet.Id
+		event.StrategyAddress = &e.Strategy
+		event.Magnitude = &e.Magnitude
+		event.EffectBlock = &e.EffectBlock
+		history = append(history, event)
+	}
+
+	for _, e := range logs.delaySets {
+		if e.Operator != operatorAddress {
+			continue
+		}
+		event := newHistoryEvent(AllocationDelaySetEventType, e.Raw)
+		event.Delay = &e.Delay
+		event.EffectBlock = &e.EffectBlock
+		history = append(history, event)
+	}
+
+	for _, e := range logs.maxMagnitudeUpdates {
+		if e.Operator != operatorAddress || !common.MatchesAddressFilter(strategyAddresses, e.Strategy) {
+			continue
+		}
+		event := newHistoryEvent(MaxMagnitudeUpdatedEventType, e.Raw)
+		event.StrategyAddress = &e.Strategy
+		event.MaxMagnitude = &e.MaxMagnitude
+		history = append(history, event)
+	}
+
+	for _, e := range logs.slashes {
+		if e.Operator != operatorAddress || !common.MatchesAddressFilter(avsAddresses, e.OperatorSet.Avs) {
+			continue
+		}
+		event := newHistoryEvent(OperatorSlashedEventType, e.Raw)
+		event.AvsAddress = &e.OperatorSet.Avs
+		event.OperatorSetId = &e.OperatorSet.Id
+		event.Description = e.Description
+		for i, strategy := range e.Strategies {
+			if !common.MatchesAddressFilter(strategyAddresses, strategy) {
+				continue
+			}
+			event.Slashes = append(event.Slashes, StrategySlash{
+				StrategyAddress: strategy,
+				WadSlashed:      e.WadSlashed[i],
+				Percentage:      wadToPercentage(e.WadSlashed[i]),
+			})
+		}
+		if len(event.Slashes) == 0 {
+			// None of the slashed strategies are shown
+			continue
+		}
+		history = append(history, event)
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].BlockNumber != history[j].BlockNumber {
+			return history[i].BlockNumber < history[j].BlockNumber
+		}
+		return history[i].logIndex < history[j].logIndex
+	})
+
+	// The previous magnitude of an allocation is known from the earlier events in the range
+	magnitudes := make(map[string]uint64)
+	maxMagnitudes := make(map[gethcommon.Address]uint64)
+	for i := range history {
+		event := &history[i]
+		switch event.Type {
+		case AllocationUpdatedEventType:
+			key := getAllocationKey(*event.AvsAddress, *event.OperatorSetId, *event.StrategyAddress)
+			if previous, ok := magnitudes[key]; ok {
+				event.PreviousMagnitude = &previous
+			}
+			magnitudes[key] = *event.Magnitude
+		case MaxMagnitudeUpdatedEventType:
+			if previous, ok := maxMagnitudes[*event.StrategyAddress]; ok {
+				event.PreviousMaxMagnitude = &previous
+			}
+			maxMagnitudes[*event.StrategyAddress] = *event.MaxMagnitude
+		}
+	}
+	return history
+}
+
+func newHistoryEvent(eventType string, raw types.Log) AllocationHistoryEvent {
+	return AllocationHistoryEvent{
+		Type:        eventType,
+		BlockNumber: raw.BlockNumber,
+		TxHash:      raw.TxHash,
+		blockHash:   raw.BlockHash,
+		txIndex:     raw.TxIndex,
+		logIndex:    raw.Index,
+	}
+}
+
+// addHistoricalState adds the previous magnitudes which are not known from earlier events and the shares
+// of the operator at the block of each event. It stops at the first failed read, since all reads fail
+// if the RPC is not an archive node.
+func addHistoricalState(
+	ctx context.Context,
+	reader historicalStateReader,
+	operatorAddress gethcommon.Address,
+	history AllocationHistory,
+) error {
+	for i := range history {
+		event := &history[i]
+		atBlock := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(event.BlockNumber)}
+		beforeBlock := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(event.BlockNumber - 1)}
+
+		switch event.Type {
+		case AllocationUpdatedEventType:
+			strategies := []gethcommon.Address{*event.StrategyAddress}
+			if event.PreviousMagnitude == nil {
+				allocation, err := reader.GetAllocation(
+					beforeBlock,
+					operatorAddress,
+					allocationmanager.OperatorSet{Avs: *event.AvsAddress, Id: *event.OperatorSetId},
+					*event.StrategyAddress,
+				)
+				if err != nil {
+					return err
+				}
+				previous := getEventualMagnitude(allocation)
+				event.PreviousMagnitude = &previous
+			}
+			shares, err := reader.GetOperatorShares(atBlock, operatorAddress, strategies)
+			if err != nil {
+				return err
+			}
+			maxMagnitudes, err := reader.GetMaxMagnitudes(atBlock, operatorAddress, strategies)
+			if err != nil {
+				return err
+			}
+			event.AllocatedShares = common.GetSharesForMagnitude(shares[0], *event.Magnitude, maxMagnitudes[0])
+		case MaxMagnitudeUpdatedEventType:
+			strategies := []gethcommon.Address{*event.StrategyAddress}
+			if event.PreviousMaxMagnitude == nil {
+				maxMagnitudes, err := reader.GetMaxMagnitudes(beforeBlock, operatorAddress, strategies)
+				if err != nil {
+					return err
+				}
+				event.PreviousMaxMagnitude = &maxMagnitudes[0]
+			}
+			sharesBefore, sharesAfter, err := getSharesAround(reader, beforeBlock, atBlock, operatorAddress, strategies)
+			if err != nil {
+				return err
+			}
+			event.SharesBefore = sharesBefore[0]
+			event.SharesAfter = sharesAfter[0]
+		case OperatorSlashedEventType:
+			strategies := make([]gethcommon.Address, len(event.Slashes))
+			for j, slash := range event.Slashes {
+				strategies[j] = slash.StrategyAddress
+			}
+			if len(strategies) == 0 {
+				continue
+			}
+			sharesBefore, sharesAfter, err := getSharesAround(reader, beforeBlock, atBlock, operatorAddress, strategies)
+			if err != nil {
+				return err
+			}
+			for j := range event.Slashes {
+				event.Slashes[j].SharesBefore = sharesBefore[j]
+				event.Slashes[j].SharesAfter = sharesAfter[j]
+			}
+		}
+	}
+	return nil
+}
+
+func getSharesAround(
+	reader historicalStateReader,
+	beforeBlock *bind.CallOpts,
+	atBlock *bind.CallOpts,
+	operatorAddress gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]*big.Int, []*big.Int, error) {
+	sharesBefore, err := reader.GetOperatorShares(beforeBlock, operatorAddress, strategies)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharesAfter, err := reader.GetOperatorShares(atBlock, operatorAddress, strategies)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharesBefore, sharesAfter, nil
+}
+
+// addBlockInfo adds the block timestamp and the transaction sender to each event
+func addBlockInfo(ctx context.Context, ethClient *ethclient.Client, history AllocationHistory) error {
+	timestamps := make(map[uint64]string)
+	senders := make(map[gethcommon.Hash]gethcommon.Address)
+	for i := range history {
+		event := &history[i]
+		if _, ok := timestamps[event.BlockNumber]; !ok {
+			header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(event.BlockNumber))
+			if err != nil {
+				return err
+			}
+			timestamps[event.BlockNumber] = time.Unix(int64(header.Time), 0).UTC().Format(time.DateTime)
+		}
+		event.Timestamp = timestamps[event.BlockNumber]
+
+		if _, ok := senders[event.TxHash]; !ok {
+			tx, _, err := ethClient.TransactionByHash(ctx, event.TxHash)
+			if err != nil {
+				return err
+			}
+			sender, err := ethClient.TransactionSender(ctx, tx, event.blockHash, event.txIndex)
+			if err != nil {
+				return err
+			}
+			senders[event.TxHash] = sender
+		}
+		sender := senders[event.TxHash]
+		event.Sender = &sender
+	}
+	return nil
+}
+
+func getEventualMagnitude(allocation allocationmanager.IAllocationManagerTypesAllocation) uint64 {
+	if allocation.PendingDiff == nil {
+		return allocation.CurrentMagnitude
+	}
+	return new(big.Int).Add(new(big.Int).SetUint64(allocation.CurrentMagnitude), allocation.PendingDiff).Uint64()
+}
+
+// wadToPercentage formats a wad proportion as a percentage with 2 decimals
+func wadToPercentage(wadSlashed *big.Int) string {
+	percentage := new(big.Float).Quo(
+		new(big.Float).SetInt(new(big.Int).Mul(wadSlashed, big.NewInt(100))),
+		new(big.Float).SetInt(wad),
+	)
+	return percentage.Text('f', 2)
+}
+
+func getAllocationKey(avsAddress gethcommon.Address, operatorSetId uint32, strategy gethcommon.Address) string {
+	return fmt.Sprintf("%s-%s", getUniqueKey(avsAddress, operatorSetId), strategy.Hex())
+}
+
+func (h AllocationHistory) strategies() []gethcommon.Address {
+	strategies := make(map[gethcommon.Address]bool)
+	for _, event := range h {
+		if event.StrategyAddress != nil {
+			strategies[*event.StrategyAddress] = true
+		}
+		for _, slash := range event.Slashes {
+			strategies[slash.StrategyAddress] = true
+		}
+	}
+	return sortedStrategies(strategies)
+}
+
+func (h AllocationHistory) setTokenNames(tokenNames map[gethcommon.Address]string) {
+	for i := range h {
+		if h[i].StrategyAddress != nil {
+			h[i].TokenName = tokenNames[*h[i].StrategyAddress]
+		}
+		for j := range h[i].Slashes {
+			h[i].Slashes[j].TokenName = tokenNames[h[i].Slashes[j].StrategyAddress]
+		}
+	}
+}
+
+func (h AllocationHistory) PrintPretty() {
+	if len(h) == 0 {
+		fmt.Println("No allocation events found in the block range")
+		return
+	}
+	for _, event := range h {
+		fmt.Println()
+		fmt.Printf("Block %d", event.BlockNumber)
+		if event.Timestamp != "" {
+			fmt.Printf(" (%s UTC)", event.Timestamp)
+		}
+		fmt.Printf(", tx %s", event.TxHash.Hex())
+		if event.Sender != nil {
+			fmt.Printf(", sent by %s", event.Sender.Hex())
+		}
+		fmt.Println()
+
+		switch event.Type {
+		case AllocationUpdatedEventType:
+			fmt.Printf(
+				"  Allocation updated for AVS %s, operator set %d, strategy %s (%s)\n",
+				event.AvsAddress.Hex(),
+				*event.OperatorSetId,
+				event.StrategyAddress.Hex(),
+				event.TokenName,
+			)
+			fmt.Printf(
+				"  Magnitude: %s -> %s, effective at block %d\n",
+				formatOptionalMagnitude(event.PreviousMagnitude),
+				common.FormatNumberWithUnderscores(common.Uint64ToString(*event.Magnitude)),
+				*event.EffectBlock,
+			)
+			if event.AllocatedShares != nil {
+				fmt.Printf(
+					"  Allocated shares: %s\n",
+					common.FormatNumberWithUnderscores(event.AllocatedShares.String()),
+				)
+			}
+		case AllocationDelaySetEventType:
+			fmt.Printf(
+				"  Allocation delay set to %d blocks, effective at block %d\n",
+				*event.Delay,
+				*event.EffectBlock,
+			)
+		case MaxMagnitudeUpdatedEventType:
+			fmt.Printf(
+				"  Max magnitude of strategy %s (%s): %s -> %s\n",
+				event.StrategyAddress.Hex(),
+				event.TokenName,
+				formatOptionalMagnitude(event.PreviousMaxMagnitude),
+				common.FormatNumberWithUnderscores(common.Uint64ToString(*event.MaxMagnitude)),
+			)
+			printSharesChange("  ", event.SharesBefore, event.SharesAfter)
+		case OperatorSlashedEventType:
+			fmt.Printf(
+				"  SLASHED by AVS %s, operator set %d: %q\n",
+				event.AvsAddress.Hex(),
+				*event.OperatorSetId,
+				event.Description,
+			)
+			for _, slash := range event.Slashes {
+				fmt.Printf(
+					"    Strategy %s (%s): %s%% of allocated magnitude (wad %s)\n",
+					slash.StrategyAddress.Hex(),
+					slash.TokenName,
+					slash.Percentage,
+					slash.WadSlashed.String(),
+				)
+				printSharesChange("    ", slash.SharesBefore, slash.SharesAfter)
+			}
+		}
+	}
+}
+
+func printSharesChange(indent string, sharesBefore *big.Int, sharesAfter *big.Int) {
+	if sharesBefore == nil || sharesAfter == nil {
+		return
+	}
+	fmt.Printf(
+		"%sOperator shares: %s -> %s (%s)\n",
+		indent,
+		common.FormatNumberWithUnderscores(sharesBefore.String()),
+		common.FormatNumberWithUnderscores(sharesAfter.String()),
+		formatSignedDelta(new(big.Int).Sub(sharesAfter, sharesBefore)),
+	)
+}
+
+func formatOptionalMagnitude(magnitude *uint64) string {
+	if magnitude == nil {
+		return "unknown"
+	}
+	return common.FormatNumberWithUnderscores(common.Uint64ToString(*magnitude))
+}
+
+func getHistoryFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.AVSAddressesFlag,
+		&flags.StrategyAddressesFlag,
+		&flags.DelegationManagerAddressFlag,
+		&FromBlockFlag,
+		&ToBlockFlag,
+	}
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func readAndValidateHistoryConfig(cCtx *cli.Context, logger logging.Logger) (*historyConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	var fromBlock *uint64
+	if cCtx.IsSet(FromBlockFlag.Name) {
+		block := cCtx.Uint64(FromBlockFlag.Name)
+		fromBlock = &block
+	}
+	toBlock := cCtx.Uint64(ToBlockFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+	avsAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.AVSAddressesFlag.Name))
+	strategyAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.StrategyAddressesFlag.Name))
+
+	chainID := utils.NetworkNameToChainId(network)
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	var err error
+	if delegationManagerAddress == "" {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &historyConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		environment:              environment,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		operatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+		avsAddresses:             avsAddresses,
+		strategyAddresses:        strategyAddresses,
+		fromBlock:                fromBlock,
+		toBlock:                  toBlock,
+	}, nil
+}
@@ -0,0 +1,260 @@
+package allocations
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHistoricalStateReader returns the state at a block from per-block maps. Blocks missing from the
+// maps fall back to the state at the closest earlier block.
+type fakeHistoricalStateReader struct {
+	allocations   map[uint64]map[string]allocationmanager.IAllocationManagerTypesAllocation
+	maxMagnitudes map[uint64]map[gethcommon.Address]uint64
+	shares        map[uint64]map[gethcommon.Address]*big.Int
+	err           error
+}
+
+func (f *fakeHistoricalStateReader) GetAllocation(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategy gethcommon.Address,
+) (allocationmanager.IAllocationManagerTypesAllocation, error) {
+	if f.err != nil {
+		return allocationmanager.IAllocationManagerTypesAllocation{}, f.err
+	}
+	key := getAllocationKey(operatorSet.Avs, operatorSet.Id, strategy)
+	for block := opts.BlockNumber.Uint64(); ; block-- {
+		if allocation, ok := f.allocations[block][key]; ok {
+			return allocation, nil
+		}
+		if block == 0 {
+			return allocationmanager.IAllocationManagerTypesAllocation{PendingDiff: big.NewInt(0)}, nil
+		}
+	}
+}
+
+func (f *fakeHistoricalStateReader) GetMaxMagnitudes(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]uint64, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make([]uint64, len(strategies))
+	for i, strategy := range strategies {
+		for block := opts.BlockNumber.Uint64(); ; block-- {
+			if magnitude, ok := f.maxMagnitudes[block][strategy]; ok {
+				result[i] = magnitude
+				break
+			}
+			if block == 0 {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeHistoricalStateReader) GetOperatorShares(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]*big.Int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make([]*big.Int, len(strategies))
+	for i, strategy := range strategies {
+		for block := opts.BlockNumber.Uint64(); ; block-- {
+			if shares, ok := f.shares[block][strategy]; ok {
+				result[i] = shares
+				break
+			}
+			if block == 0 {
+				result[i] = big.NewInt(0)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func testLog(block uint64, index uint) types.Log {
+	return types.Log{BlockNumber: block, Index: index, TxHash: gethcommon.BigToHash(big.NewInt(int64(block)))}
+}
+
+func TestBuildAllocationHistory(t *testing.T) {
+	operator := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	otherOperator := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	otherAvs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyA := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyB := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	opSet := allocationmanager.OperatorSet{Avs: avs, Id: 1}
+
+	logs := &historyLogs{
+		allocationUpdates: []*allocationmanager.ContractAllocationManagerAllocationUpdated{
+			{Operator: operator, OperatorSet: opSet, Strategy: strategyA, Magnitude: 500, Raw: testLog(120, 0)},
+			{Operator: operator, OperatorSet: opSet, Strategy: strategyA, Magnitude: 300, Raw: testLog(100, 1)},
+			{Operator: otherOperator, OperatorSet: opSet, Strategy: strategyA, Magnitude: 1, Raw: testLog(101, 0)},
+			{
+				Operator:    operator,
+				OperatorSet: allocationmanager.OperatorSet{Avs: otherAvs, Id: 0},
+				Strategy:    strategyB,
+				Magnitude:   100,
+				Raw:         testLog(110, 0),
+			},
+			{Operator: operator, OperatorSet: opSet, Strategy: strategyA, Magnitude: 450, Raw: testLog(130, 0)},
+		},
+		delaySets: []*allocationmanager.ContractAllocationManagerAllocationDelaySet{
+			{Operator: operator, Delay: 10, EffectBlock: 90, Raw: testLog(50, 0)},
+			{Operator: otherOperator, Delay: 20, EffectBlock: 90, Raw: testLog(51, 0)},
+		},
+		maxMagnitudeUpdates: []*allocationmanager.ContractAllocationManagerMaxMagnitudeUpdated{
+			{Operator: operator, Strategy: strategyA, MaxMagnitude: 900, Raw: testLog(130, 2)},
+		},
+		slashes: []*allocationmanager.ContractAllocationManagerOperatorSlashed{
+			{
+				Operator:    operator,
+				OperatorSet: opSet,
+				Strategies:  []gethcommon.Address{strategyA},
+				WadSlashed:  []*big.Int{big.NewInt(1e17)},
+				Description: "double signing",
+				Raw:         testLog(130, 1),
+			},
+		},
+	}
+
+	t.Run("all events of the operator in chain order", func(t *testing.T) {
+		history := buildAllocationHistory(logs, operator, nil, nil)
+		eventTypes := make([]string, len(history))
+		blocks := make([]uint64, len(history))
+		for i, event := range history {
+			eventTypes[i] = event.Type
+			blocks[i] = event.BlockNumber
+		}
+		assert.Equal(t, []string{
+			AllocationDelaySetEventType,
+			AllocationUpdatedEventType,
+			AllocationUpdatedEventType,
+			AllocationUpdatedEventType,
+			AllocationUpdatedEventType,
+			OperatorSlashedEventType,
+			MaxMagnitudeUpdatedEventType,
+		}, eventTypes)
+		assert.Equal(t, []uint64{50, 100, 110, 120, 130, 130, 130}, blocks)
+
+		// The first allocation of each (operator set, strategy) has no known previous magnitude
+		assert.Nil(t, history[1].PreviousMagnitude)
+		assert.Equal(t, uint64(300), *history[3].PreviousMagnitude)
+		assert.Equal(t, uint64(500), *history[4].PreviousMagnitude)
+		assert.Nil(t, history[6].PreviousMaxMagnitude)
+
+		assert.Equal(t, "double signing", history[5].Description)
+		assert.Equal(t, "10.00", history[5].Slashes[0].Percentage)
+	})
+
+	t.Run("filtered by AVS and strategy", func(t *testing.T) {
+		history := buildAllocationHistory(logs, operator, []gethcommon.Address{otherAvs}, []gethcommon.Address{strategyB})
+		assert.Len(t, history, 2)
+		assert.Equal(t, AllocationDelaySetEventType, history[0].Type)
+		assert.Equal(t, strategyB, *history[1].StrategyAddress)
+	})
+
+	t.Run("slashings of other strategies are dropped", func(t *testing.T) {
+		history := buildAllocationHistory(logs, operator, []gethcommon.Address{avs}, []gethcommon.Address{strategyB})
+		assert.Len(t, history, 1)
+		assert.Equal(t, AllocationDelaySetEventType, history[0].Type)
+	})
+}
+
+func TestAddHistoricalState(t *testing.T) {
+	operator := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategy := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	opSet := allocationmanager.OperatorSet{Avs: avs, Id: 1}
+
+	logs := &historyLogs{
+		allocationUpdates: []*allocationmanager.ContractAllocationManagerAllocationUpdated{
+			{Operator: operator, OperatorSet: opSet, Strategy: strategy, Magnitude: 500, EffectBlock: 150, Raw: testLog(100, 0)},
+		},
+		maxMagnitudeUpdates: []*allocationmanager.ContractAllocationManagerMaxMagnitudeUpdated{
+			{Operator: operator, Strategy: strategy, MaxMagnitude: 950, Raw: testLog(200, 1)},
+		},
+		slashes: []*allocationmanager.ContractAllocationManagerOperatorSlashed{
+			{
+				Operator:    operator,
+				OperatorSet: opSet,
+				Strategies:  []gethcommon.Address{strategy},
+				WadSlashed:  []*big.Int{big.NewInt(1e17)},
+				Raw:         testLog(200, 0),
+			},
+		},
+	}
+	reader := &fakeHistoricalStateReader{
+		allocations: map[uint64]map[string]allocationmanager.IAllocationManagerTypesAllocation{
+			50: {
+				getAllocationKey(avs, 1, strategy): {CurrentMagnitude: 100, PendingDiff: big.NewInt(100), EffectBlock: 60},
+			},
+		},
+		maxMagnitudes: map[uint64]map[gethcommon.Address]uint64{
+			0:   {strategy: 1000},
+			200: {strategy: 950},
+		},
+		shares: map[uint64]map[gethcommon.Address]*big.Int{
+			0:   {strategy: big.NewInt(2000)},
+			200: {strategy: big.NewInt(1900)},
+		},
+	}
+
+	t.Run("reads previous magnitudes and shares at the event blocks", func(t *testing.T) {
+		history := buildAllocationHistory(logs, operator, nil, nil)
+		err := addHistoricalState(context.Background(), reader, operator, history)
+		assert.NoError(t, err)
+
+		allocation := history[0]
+		assert.Equal(t, uint64(200), *allocation.PreviousMagnitude)
+		assert.Equal(t, big.NewInt(1000), allocation.AllocatedShares)
+
+		slash := history[1]
+		assert.Equal(t, big.NewInt(2000), slash.Slashes[0].SharesBefore)
+		assert.Equal(t, big.NewInt(1900), slash.Slashes[0].SharesAfter)
+
+		maxMagnitude := history[2]
+		assert.Equal(t, uint64(1000), *maxMagnitude.PreviousMaxMagnitude)
+		assert.Equal(t, big.NewInt(2000), maxMagnitude.SharesBefore)
+		assert.Equal(t, big.NewInt(1900), maxMagnitude.SharesAfter)
+	})
+
+	t.Run("returns the error of a non archive node", func(t *testing.T) {
+		history := buildAllocationHistory(logs, operator, nil, nil)
+		err := addHistoricalState(
+			context.Background(),
+			&fakeHistoricalStateReader{err: errors.New("missing trie node")},
+			operator,
+			history,
+		)
+		assert.Error(t, err)
+		assert.Nil(t, history[1].Slashes[0].SharesBefore)
+	})
+}
+
+func TestWadToPercentage(t *testing.T) {
+	assert.Equal(t, "100.00", wadToPercentage(big.NewInt(1e18)))
+	assert.Equal(t, "12.35", wadToPercentage(big.NewInt(123_456_789_000_000_000)))
+	assert.Equal(t, "0.00", wadToPercentage(big.NewInt(0)))
+}
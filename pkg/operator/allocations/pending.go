@@ -0,0 +1,428 @@
+package allocations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// blockTimeSampleSize is the number of recent blocks used to estimate the average block time
+	blockTimeSampleSize = 1000
+	defaultBlockTime    = 12 * time.Second
+)
+
+type pendingAllocationReader interface {
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+	GetAllocatableMagnitude(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) (uint64, error)
+	GetEncumberedMagnitude(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) (uint64, error)
+}
+
+type PendingAllocationsReport struct {
+	OperatorAddress    gethcommon.Address        `json:"operator_address"`
+	CurrentBlock       uint64                    `json:"current_block"`
+	AverageBlockTime   float64                   `json:"average_block_time_seconds"`
+	PendingAllocations []PendingAllocation       `json:"pending_allocations"`
+	DeallocationQueues []DeallocationQueueStatus `json:"deallocation_queues"`
+}
+
+type PendingAllocation struct {
+	StrategyAddress  gethcommon.Address `json:"strategy_address"`
+	TokenName        string             `json:"token_name"`
+	AvsAddress       gethcommon.Address `json:"avs_address"`
+	OperatorSetId    uint32             `json:"operator_set_id"`
+	Type             string             `json:"type"`
+	CurrentMagnitude uint64             `json:"current_magnitude"`
+	NewMagnitude     uint64             `json:"new_magnitude"`
+	EffectBlock      uint32             `json:"effect_block"`
+	BlocksRemaining  uint64             `json:"blocks_remaining"`
+	ETA              time.Time          `json:"eta"`
+}
+
+// DeallocationQueueStatus shows whether completed deallocations of a strategy are still in the deallocation
+// queue. The AllocationManager only subtracts completed deallocations from the encumbered magnitude when the
+// queue of the strategy is cleared, either with clearDeallocationQueue or as part of the next ModifyAllocations
+// call for the strategy.
+type DeallocationQueueStatus struct {
+	StrategyAddress      gethcommon.Address `json:"strategy_address"`
+	TokenName            string             `json:"token_name"`
+	MaxMagnitude         uint64             `json:"max_magnitude"`
+	EncumberedMagnitude  uint64             `json:"encumbered_magnitude"`
+	AllocatableMagnitude uint64             `json:"allocatable_magnitude"`
+	ClearableMagnitude   uint64             `json:"clearable_magnitude"`
+	NeedsClearing        bool               `json:"needs_clearing"`
+}
+
+func PendingCmd(p utils.Prompter) *cli.Command {
+	pendingCmd := &cli.Command{
+		Name:      "pending",
+		Usage:     "Show pending allocations and deallocations",
+		UsageText: "pending",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to show the pending allocations and deallocations of the operator
+
+Each pending modification is listed with its effect block and an estimated time, based on the average
+block time of the last 1000 blocks. For each strategy, the deallocation queue status shows whether
+completed deallocations still have to be cleared from the queue with 'allocations clear-queue'.
+
+If --strategy-addresses is not set, the strategies are discovered the same way as in 'allocations show'.
+If --avs-addresses is set, only the pending modifications of operator sets of those AVSs are listed.
+`,
+		Flags: getShowFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return pendingAction(cCtx)
+		},
+	}
+	return pendingCmd
+}
+
+func pendingAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateShowConfig(cCtx, &logger)
+	if err != nil {
+		return err
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elReader, err := batchreader.NewReader(
+		ctx,
+		ethClient,
+		config.delegationManagerAddress,
+		config.batchSize,
+		config.maxConcurrency,
+		logger,
+	)
+	if err != nil {
+		return err
+	}
+
+	strategyAddresses := config.strategyAddresses
+	if len(strategyAddresses) == 0 {
+		strategyAddresses, err = discoverOperatorStrategies(
+			ctx,
+			elReader,
+			ethClient,
+			config.operatorAddress,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to discover strategies", err)
+		}
+	}
+
+	currentBlock := elReader.BlockNumber()
+	averageBlockTime := getAverageBlockTime(ctx, ethClient, currentBlock, logger)
+
+	report, err := getPendingAllocationsReport(
+		ctx,
+		elReader,
+		config.operatorAddress,
+		strategyAddresses,
+		config.avsAddresses,
+		currentBlock,
+		averageBlockTime,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	tokenNames := getStrategyTokenNames(ctx, elReader, ethClient, strategyAddresses, logger)
+	for i := range report.PendingAllocations {
+		report.PendingAllocations[i].TokenName = tokenNames[report.PendingAllocations[i].StrategyAddress]
+	}
+	for i := range report.DeallocationQueues {
+		report.DeallocationQueues[i].TokenName = tokenNames[report.DeallocationQueues[i].StrategyAddress]
+	}
+
+	if config.outputType == utils.JsonOutputType {
+		reportJson, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(reportJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Pending allocations written to file: %s", config.output)
+		} else {
+			fmt.Println(string(reportJson))
+		}
+		return nil
+	}
+
+	if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+	report.PrintPretty()
+	return nil
+}
+
+// getPendingAllocationsReport lists the pending modifications of the operator and the deallocation queue
+// status of each strategy. The ETA of a modification assumes every remaining block takes averageBlockTime.
+// If avsAddresses is not empty, only modifications of operator sets of those AVSs are listed.
+func getPendingAllocationsReport(
+	ctx context.Context,
+	reader pendingAllocationReader,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+	avsAddresses []gethcommon.Address,
+	currentBlock uint64,
+	averageBlockTime time.Duration,
+	now time.Time,
+) (*PendingAllocationsReport, error) {
+	report := &PendingAllocationsReport{
+		OperatorAddress:    operatorAddress,
+		CurrentBlock:       currentBlock,
+		AverageBlockTime:   averageBlockTime.Seconds(),
+		PendingAllocations: make([]PendingAllocation, 0),
+		DeallocationQueues: make([]DeallocationQueueStatus, 0, len(strategyAddresses)),
+	}
+	if len(strategyAddresses) == 0 {
+		return report, nil
+	}
+
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategyAddresses)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+
+	for i, strategyAddress := range strategyAddresses {
+		allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, strategyAddress)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+		}
+		for _, info := range allocationInfo {
+			if info.PendingDiff == nil || info.PendingDiff.Sign() == 0 {
+				continue
+			}
+			if !common.MatchesAddressFilter(avsAddresses, info.AvsAddress) {
+				continue
+			}
+			pending := PendingAllocation{
+				StrategyAddress:  strategyAddress,
+				AvsAddress:       info.AvsAddress,
+				OperatorSetId:    info.OperatorSetId,
+				Type:             AllocationChangeType,
+				CurrentMagnitude: info.CurrentMagnitude.Uint64(),
+				NewMagnitude:     new(big.Int).Add(info.CurrentMagnitude, info.PendingDiff).Uint64(),
+				EffectBlock:      info.EffectBlock,
+			}
+			if info.PendingDiff.Sign() < 0 {
+				pending.Type = DeallocationChangeType
+			}
+			if uint64(info.EffectBlock) > currentBlock {
+				pending.BlocksRemaining = uint64(info.EffectBlock) - currentBlock
+			}
+			pending.ETA = now.Add(time.Duration(pending.BlocksRemaining) * averageBlockTime).UTC()
+			report.PendingAllocations = append(report.PendingAllocations, pending)
+		}
+
+		queue, err := getDeallocationQueueStatus(ctx, reader, operatorAddress, strategyAddress, maxMagnitudes[i])
+		if err != nil {
+			return nil, err
+		}
+		report.DeallocationQueues = append(report.DeallocationQueues, queue)
+	}
+
+	return report, nil
+}
+
+func getDeallocationQueueStatus(
+	ctx context.Context,
+	reader pendingAllocationReader,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+	maxMagnitude uint64,
+) (DeallocationQueueStatus, error) {
+	allocatableMagnitude, err := reader.GetAllocatableMagnitude(ctx, operatorAddress, strategyAddress)
+	if err != nil {
+		return DeallocationQueueStatus{}, eigenSdkUtils.WrapError("failed to get allocatable magnitude", err)
+	}
+	encumberedMagnitude, err := reader.GetEncumberedMagnitude(ctx, operatorAddress, strategyAddress)
+	if err != nil {
+		return DeallocationQueueStatus{}, eigenSdkUtils.WrapError("failed to get encumbered magnitude", err)
+	}
+	queue := DeallocationQueueStatus{
+		StrategyAddress:      strategyAddress,
+		MaxMagnitude:         maxMagnitude,
+		EncumberedMagnitude:  encumberedMagnitude,
+		AllocatableMagnitude: allocatableMagnitude,
+	}
+	// The allocatable magnitude includes completed deallocations which are still in the queue,
+	// the encumbered magnitude only drops once they are cleared
+	if maxMagnitude >= encumberedMagnitude && allocatableMagnitude > maxMagnitude-encumberedMagnitude {
+		queue.ClearableMagnitude = allocatableMagnitude - (maxMagnitude - encumberedMagnitude)
+		queue.NeedsClearing = true
+	}
+	return queue, nil
+}
+
+// getAverageBlockTime estimates the block time from the timestamps of the last blockTimeSampleSize blocks
+func getAverageBlockTime(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	currentBlock uint64,
+	logger logging.Logger,
+) time.Duration {
+	if currentBlock < blockTimeSampleSize {
+		return defaultBlockTime
+	}
+	latest, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(currentBlock))
+	if err != nil {
+		logger.Debugf("Failed to get latest block header, using default block time: %s", err)
+		return defaultBlockTime
+	}
+	earlier, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(currentBlock-blockTimeSampleSize))
+	if err != nil {
+		logger.Debugf("Failed to get block header, using default block time: %s", err)
+		return defaultBlockTime
+	}
+	if latest.Time <= earlier.Time {
+		return defaultBlockTime
+	}
+	return time.Duration(latest.Time-earlier.Time) * time.Second / blockTimeSampleSize
+}
+
+func (r *PendingAllocationsReport) PrintPretty() {
+	fmt.Printf(
+		"------------------ Pending Allocations for %s (Block: %d) ---------------------\n",
+		r.OperatorAddress.String(),
+		r.CurrentBlock,
+	)
+	fmt.Printf("Average block time: %.2f seconds\n", r.AverageBlockTime)
+	fmt.Println()
+
+	if len(r.PendingAllocations) == 0 {
+		fmt.Println("No pending allocations or deallocations")
+	} else {
+		headers := []string{
+			"Strategy",
+			"Token",
+			"AVS",
+			"Operator Set ID",
+			"Type",
+			"Current Magnitude",
+			"New Magnitude",
+			"Effect Block",
+			"ETA (UTC)",
+		}
+		widths := []int{15, 20, 15, 16, 13, 26, 26, 13, 20}
+		printTableBorder(widths)
+		printTableHeader(headers, widths)
+		for _, pending := range r.PendingAllocations {
+			fmt.Printf(
+				"| %-*s| %-*s| %-*s| %-*d| %-*s| %-*s| %-*s| %-*d| %-*s|\n",
+				widths[0], common.ShortEthAddress(pending.StrategyAddress),
+				widths[1], pending.TokenName,
+				widths[2], common.ShortEthAddress(pending.AvsAddress),
+				widths[3], pending.OperatorSetId,
+				widths[4], pending.Type,
+				widths[5], common.FormatNumberWithUnderscores(common.Uint64ToString(pending.CurrentMagnitude)),
+				widths[6], common.FormatNumberWithUnderscores(common.Uint64ToString(pending.NewMagnitude)),
+				widths[7], pending.EffectBlock,
+				widths[8], pending.ETA.Format(time.DateTime),
+			)
+		}
+		printTableBorder(widths)
+	}
+
+	fmt.Println()
+	fmt.Println("Deallocation Queue")
+	headers := []string{
+		"Strategy",
+		"Token",
+		"Max Magnitude",
+		"Encumbered Magnitude",
+		"Allocatable Magnitude",
+		"Clearable Magnitude",
+		"Needs Clearing",
+	}
+	widths := []int{15, 20, 26, 26, 26, 26, 15}
+	printTableBorder(widths)
+	printTableHeader(headers, widths)
+	needsClearing := false
+	for _, queue := range r.DeallocationQueues {
+		fmt.Printf(
+			"| %-*s| %-*s| %-*s| %-*s| %-*s| %-*s| %-*t|\n",
+			widths[0], common.ShortEthAddress(queue.StrategyAddress),
+			widths[1], queue.TokenName,
+			widths[2], common.FormatNumberWithUnderscores(common.Uint64ToString(queue.MaxMagnitude)),
+			widths[3], common.FormatNumberWithUnderscores(common.Uint64ToString(queue.EncumberedMagnitude)),
+			widths[4], common.FormatNumberWithUnderscores(common.Uint64ToString(queue.AllocatableMagnitude)),
+			widths[5], common.FormatNumberWithUnderscores(common.Uint64ToString(queue.ClearableMagnitude)),
+			widths[6], queue.NeedsClearing,
+		)
+		needsClearing = needsClearing || queue.NeedsClearing
+	}
+	printTableBorder(widths)
+
+	if needsClearing {
+		fmt.Println(
+			"Completed deallocations are waiting in the deallocation queue. " +
+				"Run 'eigenlayer operator allocations clear-queue' to release their magnitude",
+		)
+	}
+}
+
+func printTableBorder(widths []int) {
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+}
+
+func printTableHeader(headers []string, widths []int) {
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+
+	for _, width := range widths {
+		fmt.Print("|", strings.Repeat("-", width+1))
+	}
+	fmt.Println("|")
+}
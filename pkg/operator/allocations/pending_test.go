@@ -0,0 +1,158 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePendingAllocationReader struct {
+	fakeAllocationDiffReader
+	allocatableMagnitudes map[gethcommon.Address]uint64
+	encumberedMagnitudes  map[gethcommon.Address]uint64
+}
+
+func (f *fakePendingAllocationReader) GetAllocatableMagnitude(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) (uint64, error) {
+	return f.allocatableMagnitudes[strategyAddress], nil
+}
+
+func (f *fakePendingAllocationReader) GetEncumberedMagnitude(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) (uint64, error) {
+	return f.encumberedMagnitudes[strategyAddress], nil
+}
+
+func TestGetPendingAllocationsReport(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyA := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyB := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	reader := &fakePendingAllocationReader{
+		fakeAllocationDiffReader: fakeAllocationDiffReader{
+			maxMagnitudes: map[gethcommon.Address]uint64{strategyA: 1000, strategyB: 1000},
+			allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+				strategyA: {
+					{
+						AvsAddress:       avsAddress,
+						OperatorSetId:    1,
+						CurrentMagnitude: big.NewInt(100),
+						PendingDiff:      big.NewInt(200),
+						EffectBlock:      110,
+					},
+					{
+						AvsAddress:       avsAddress,
+						OperatorSetId:    2,
+						CurrentMagnitude: big.NewInt(400),
+						PendingDiff:      big.NewInt(0),
+					},
+				},
+				strategyB: {
+					{
+						AvsAddress:       avsAddress,
+						OperatorSetId:    1,
+						CurrentMagnitude: big.NewInt(500),
+						PendingDiff:      big.NewInt(-300),
+						EffectBlock:      150,
+					},
+				},
+			},
+		},
+		// strategyA has 300 magnitude of completed deallocations in its queue
+		allocatableMagnitudes: map[gethcommon.Address]uint64{strategyA: 300, strategyB: 500},
+		encumberedMagnitudes:  map[gethcommon.Address]uint64{strategyA: 1000, strategyB: 500},
+	}
+
+	report, err := getPendingAllocationsReport(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]gethcommon.Address{strategyA, strategyB},
+		nil,
+		100,
+		12*time.Second,
+		now,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []PendingAllocation{
+		{
+			StrategyAddress:  strategyA,
+			AvsAddress:       avsAddress,
+			OperatorSetId:    1,
+			Type:             AllocationChangeType,
+			CurrentMagnitude: 100,
+			NewMagnitude:     300,
+			EffectBlock:      110,
+			BlocksRemaining:  10,
+			ETA:              now.Add(2 * time.Minute),
+		},
+		{
+			StrategyAddress:  strategyB,
+			AvsAddress:       avsAddress,
+			OperatorSetId:    1,
+			Type:             DeallocationChangeType,
+			CurrentMagnitude: 500,
+			NewMagnitude:     200,
+			EffectBlock:      150,
+			BlocksRemaining:  50,
+			ETA:              now.Add(10 * time.Minute),
+		},
+	}, report.PendingAllocations)
+	assert.Equal(t, []DeallocationQueueStatus{
+		{
+			StrategyAddress:      strategyA,
+			MaxMagnitude:         1000,
+			EncumberedMagnitude:  1000,
+			AllocatableMagnitude: 300,
+			ClearableMagnitude:   300,
+			NeedsClearing:        true,
+		},
+		{
+			StrategyAddress:      strategyB,
+			MaxMagnitude:         1000,
+			EncumberedMagnitude:  500,
+			AllocatableMagnitude: 500,
+		},
+	}, report.DeallocationQueues)
+
+	// Pending modifications of other AVSs are filtered out, the deallocation queues are still reported
+	otherAvsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	report, err = getPendingAllocationsReport(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]gethcommon.Address{strategyA, strategyB},
+		[]gethcommon.Address{otherAvsAddress},
+		100,
+		12*time.Second,
+		now,
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, report.PendingAllocations)
+	assert.Len(t, report.DeallocationQueues, 2)
+
+	strategiesToClear, err := getStrategiesToClear(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]gethcommon.Address{strategyA, strategyB},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []gethcommon.Address{strategyA}, strategiesToClear)
+}
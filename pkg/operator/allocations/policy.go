@@ -0,0 +1,247 @@
+package allocations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"gopkg.in/yaml.v2"
+)
+
+type allocationPolicyReader interface {
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+}
+
+// allocationPolicy is the YAML format of an allocation policy file. Limits which are not set are not
+// checked. Bips are relative to the max magnitude of the strategy.
+type allocationPolicy struct {
+	MaxBipsPerAvs           *uint64  `yaml:"max_bips_per_avs"`
+	MaxTotalBipsPerStrategy *uint64  `yaml:"max_total_bips_per_strategy"`
+	MinUnallocatedBips      *uint64  `yaml:"min_unallocated_bips"`
+	AllowedAvsAddresses     []string `yaml:"allowed_avs_addresses"`
+
+	allowedAvs map[gethcommon.Address]bool
+}
+
+func readAllocationPolicy(filePath string) (*allocationPolicy, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var policy allocationPolicy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, err
+	}
+
+	for name, limit := range map[string]*uint64{
+		"max_bips_per_avs":            policy.MaxBipsPerAvs,
+		"max_total_bips_per_strategy": policy.MaxTotalBipsPerStrategy,
+		"min_unallocated_bips":        policy.MinUnallocatedBips,
+	} {
+		if limit != nil && *limit > maxBips {
+			return nil, fmt.Errorf("%s must be at most %d, got %d", name, maxBips, *limit)
+		}
+	}
+	if policy.AllowedAvsAddresses != nil {
+		policy.allowedAvs = make(map[gethcommon.Address]bool, len(policy.AllowedAvsAddresses))
+		for _, avs := range policy.AllowedAvsAddresses {
+			if !gethcommon.IsHexAddress(avs) {
+				return nil, fmt.Errorf("invalid avs address in allowed_avs_addresses: %s", avs)
+			}
+			policy.allowedAvs[gethcommon.HexToAddress(avs)] = true
+		}
+	}
+	return &policy, nil
+}
+
+// checkAllocationPolicy returns the policy violations of the allocations. The resulting magnitude of an
+// operator set is the new magnitude if the allocations update it, and otherwise the larger of its current
+// and pending magnitude since a pending deallocation stays slashable until it takes effect.
+//
+// Only increases are checked: an allocation is a violation if it increases the magnitude of an operator set
+// and the resulting magnitudes break a limit. Allocations which only reduce exposure are always allowed,
+// so an operator which already breaks the policy can still move back within its limits.
+func checkAllocationPolicy(
+	ctx context.Context,
+	reader allocationPolicyReader,
+	operatorAddress gethcommon.Address,
+	policy *allocationPolicy,
+	allocations []allocationmanager.IAllocationManagerTypesAllocateParams,
+) ([]string, error) {
+	newMagnitudes := make(map[gethcommon.Address]map[allocationmanager.OperatorSet]uint64)
+	for _, a := range allocations {
+		for i, strategy := range a.Strategies {
+			if _, ok := newMagnitudes[strategy]; !ok {
+				newMagnitudes[strategy] = make(map[allocationmanager.OperatorSet]uint64)
+			}
+			newMagnitudes[strategy][a.OperatorSet] = a.NewMagnitudes[i]
+		}
+	}
+	strategies := make([]gethcommon.Address, 0, len(newMagnitudes))
+	for strategy := range newMagnitudes {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool {
+		return bytes.Compare(strategies[i].Bytes(), strategies[j].Bytes()) < 0
+	})
+
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategies)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+
+	violations := make([]string, 0)
+	for i, strategy := range strategies {
+		allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, strategy)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+		}
+		currentMagnitudes := make(map[allocationmanager.OperatorSet]uint64)
+		for _, info := range allocationInfo {
+			opSet := allocationmanager.OperatorSet{Avs: info.AvsAddress, Id: info.OperatorSetId}
+			currentMagnitudes[opSet] = getExposedMagnitude(info)
+		}
+
+		resultingMagnitudes := make(map[allocationmanager.OperatorSet]uint64)
+		for opSet, magnitude := range currentMagnitudes {
+			resultingMagnitudes[opSet] = magnitude
+		}
+		increasedAvs := make(map[gethcommon.Address]bool)
+		for opSet, magnitude := range newMagnitudes[strategy] {
+			resultingMagnitudes[opSet] = magnitude
+			if magnitude > currentMagnitudes[opSet] {
+				increasedAvs[opSet.Avs] = true
+			}
+		}
+		if len(increasedAvs) == 0 {
+			continue
+		}
+
+		maxMagnitude := maxMagnitudes[i]
+		avsMagnitudes := make(map[gethcommon.Address]uint64)
+		var totalMagnitude uint64
+		for opSet, magnitude := range resultingMagnitudes {
+			avsMagnitudes[opSet.Avs] += magnitude
+			totalMagnitude += magnitude
+		}
+
+		increased := make([]gethcommon.Address, 0, len(increasedAvs))
+		for avs := range increasedAvs {
+			increased = append(increased, avs)
+		}
+		sort.Slice(increased, func(i, j int) bool {
+			return bytes.Compare(increased[i].Bytes(), increased[j].Bytes()) < 0
+		})
+		for _, avs := range increased {
+			if policy.allowedAvs != nil && !policy.allowedAvs[avs] {
+				violations = append(violations, fmt.Sprintf(
+					"strategy %s: AVS %s is not in allowed_avs_addresses",
+					strategy.Hex(),
+					avs.Hex(),
+				))
+			}
+			if policy.MaxBipsPerAvs != nil &&
+				compareMagnitudeToBips(avsMagnitudes[avs], maxMagnitude, *policy.MaxBipsPerAvs) > 0 {
+				violations = append(violations, fmt.Sprintf(
+					"strategy %s: AVS %s would have %d bips allocated, max_bips_per_avs is %d",
+					strategy.Hex(),
+					avs.Hex(),
+					magnitudeToBipsRoundedUp(avsMagnitudes[avs], maxMagnitude),
+					*policy.MaxBipsPerAvs,
+				))
+			}
+		}
+
+		if policy.MaxTotalBipsPerStrategy != nil &&
+			compareMagnitudeToBips(totalMagnitude, maxMagnitude, *policy.MaxTotalBipsPerStrategy) > 0 {
+			violations = append(violations, fmt.Sprintf(
+				"strategy %s: %d bips would be allocated in total, max_total_bips_per_strategy is %d",
+				strategy.Hex(),
+				magnitudeToBipsRoundedUp(totalMagnitude, maxMagnitude),
+				*policy.MaxTotalBipsPerStrategy,
+			))
+		}
+		if policy.MinUnallocatedBips != nil {
+			unallocatedMagnitude := uint64(0)
+			if totalMagnitude < maxMagnitude {
+				unallocatedMagnitude = maxMagnitude - totalMagnitude
+			}
+			if compareMagnitudeToBips(unallocatedMagnitude, maxMagnitude, *policy.MinUnallocatedBips) < 0 {
+				violations = append(violations, fmt.Sprintf(
+					"strategy %s: %d bips would remain unallocated, min_unallocated_bips is %d",
+					strategy.Hex(),
+					magnitudeToBips(unallocatedMagnitude, maxMagnitude),
+					*policy.MinUnallocatedBips,
+				))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// getExposedMagnitude returns the larger of the current and pending magnitude of an allocation
+func getExposedMagnitude(info elcontracts.AllocationInfo) uint64 {
+	magnitude := new(big.Int).Set(info.CurrentMagnitude)
+	if info.PendingDiff != nil && info.PendingDiff.Sign() > 0 {
+		magnitude.Add(magnitude, info.PendingDiff)
+	}
+	return magnitude.Uint64()
+}
+
+// compareMagnitudeToBips compares magnitude / maxMagnitude to bips / maxBips without rounding. Converting
+// the magnitude to bips first would let a magnitude slightly above a limit round down to the limit.
+func compareMagnitudeToBips(magnitude uint64, maxMagnitude uint64, bips uint64) int {
+	scaledMagnitude := new(big.Int).Mul(new(big.Int).SetUint64(magnitude), big.NewInt(maxBips))
+	scaledBips := new(big.Int).Mul(new(big.Int).SetUint64(bips), new(big.Int).SetUint64(maxMagnitude))
+	return scaledMagnitude.Cmp(scaledBips)
+}
+
+// magnitudeToBips returns magnitude / maxMagnitude in bips, rounded down
+func magnitudeToBips(magnitude uint64, maxMagnitude uint64) uint64 {
+	if maxMagnitude == 0 {
+		return 0
+	}
+	bips := new(big.Int).Mul(new(big.Int).SetUint64(magnitude), big.NewInt(maxBips))
+	return bips.Div(bips, new(big.Int).SetUint64(maxMagnitude)).Uint64()
+}
+
+// magnitudeToBipsRoundedUp returns magnitude / maxMagnitude in bips, rounded up so that a magnitude above a
+// limit is shown above it
+func magnitudeToBipsRoundedUp(magnitude uint64, maxMagnitude uint64) uint64 {
+	if maxMagnitude == 0 {
+		return 0
+	}
+	bips := new(big.Int).Mul(new(big.Int).SetUint64(magnitude), big.NewInt(maxBips))
+	bips.Add(bips, new(big.Int).SetUint64(maxMagnitude-1))
+	return bips.Div(bips, new(big.Int).SetUint64(maxMagnitude)).Uint64()
+}
+
+func formatPolicyViolations(policyFilePath string, violations []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("allocations violate the policy in %s:", policyFilePath))
+	for _, violation := range violations {
+		sb.WriteString("\n  - " + violation)
+	}
+	return sb.String()
+}
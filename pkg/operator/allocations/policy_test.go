@@ -0,0 +1,197 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type allocateParams = allocationmanager.IAllocationManagerTypesAllocateParams
+
+func TestReadAllocationPolicy(t *testing.T) {
+	policy, err := readAllocationPolicy("testdata/allocations_policy.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3000), *policy.MaxBipsPerAvs)
+	assert.Equal(t, uint64(8000), *policy.MaxTotalBipsPerStrategy)
+	assert.Equal(t, uint64(2500), *policy.MinUnallocatedBips)
+	assert.True(t, policy.allowedAvs[gethcommon.HexToAddress("0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f")])
+
+	invalidPolicies := map[string]string{
+		"limit over max bips": "max_bips_per_avs: 10001\n",
+		"unknown field":       "max_bips: 1000\n",
+		"invalid avs address": "allowed_avs_addresses: [\"0x1234\"]\n",
+	}
+	for name, content := range invalidPolicies {
+		t.Run(name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "policy.yaml")
+			assert.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+			_, err := readAllocationPolicy(filePath)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheckAllocationPolicy(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	allowedAvs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	otherAvs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+
+	reader := &fakeAllocationDiffReader{
+		maxMagnitudes: map[gethcommon.Address]uint64{strategyAddress: 10_000},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+			strategyAddress: {
+				// Pending allocation counts with its pending magnitude
+				{AvsAddress: allowedAvs, OperatorSetId: 1, CurrentMagnitude: big.NewInt(1000), PendingDiff: big.NewInt(1000)},
+				{AvsAddress: otherAvs, OperatorSetId: 1, CurrentMagnitude: big.NewInt(4000), PendingDiff: big.NewInt(0)},
+			},
+		},
+	}
+	limit := func(bips uint64) *uint64 { return &bips }
+	allocate := func(avs gethcommon.Address, operatorSetId uint32, magnitude uint64) allocateParams {
+		return allocateParams{
+			OperatorSet:   allocationmanager.OperatorSet{Avs: avs, Id: operatorSetId},
+			Strategies:    []gethcommon.Address{strategyAddress},
+			NewMagnitudes: []uint64{magnitude},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		policy             *allocationPolicy
+		allocations        []allocateParams
+		expectedViolations int
+	}{
+		{
+			name:        "within limits",
+			policy:      &allocationPolicy{MaxBipsPerAvs: limit(3000), MaxTotalBipsPerStrategy: limit(8000)},
+			allocations: []allocateParams{allocate(allowedAvs, 2, 500)},
+		},
+		{
+			name:               "max bips per AVS across operator sets",
+			policy:             &allocationPolicy{MaxBipsPerAvs: limit(3000)},
+			allocations:        []allocateParams{allocate(allowedAvs, 2, 1500)},
+			expectedViolations: 1,
+		},
+		{
+			name:               "max total bips per strategy",
+			policy:             &allocationPolicy{MaxTotalBipsPerStrategy: limit(7000)},
+			allocations:        []allocateParams{allocate(allowedAvs, 2, 1500)},
+			expectedViolations: 1,
+		},
+		{
+			name:               "min unallocated bips",
+			policy:             &allocationPolicy{MinUnallocatedBips: limit(4000)},
+			allocations:        []allocateParams{allocate(allowedAvs, 2, 100)},
+			expectedViolations: 1,
+		},
+		{
+			name: "AVS not allowed",
+			policy: &allocationPolicy{
+				allowedAvs: map[gethcommon.Address]bool{allowedAvs: true},
+			},
+			allocations:        []allocateParams{allocate(otherAvs, 1, 4500)},
+			expectedViolations: 1,
+		},
+		{
+			name: "deallocations are always allowed",
+			policy: &allocationPolicy{
+				MaxBipsPerAvs:      limit(1000),
+				MinUnallocatedBips: limit(9000),
+				allowedAvs:         map[gethcommon.Address]bool{allowedAvs: true},
+			},
+			allocations:        []allocateParams{allocate(otherAvs, 1, 2000)},
+			expectedViolations: 0,
+		},
+		{
+			name:   "multiple violations",
+			policy: &allocationPolicy{MaxBipsPerAvs: limit(3000), MaxTotalBipsPerStrategy: limit(7000)},
+			allocations: []allocateParams{
+				allocate(allowedAvs, 1, 3500),
+				allocate(otherAvs, 1, 4500),
+			},
+			expectedViolations: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := checkAllocationPolicy(
+				context.Background(),
+				reader,
+				operatorAddress,
+				tt.policy,
+				tt.allocations,
+			)
+			assert.NoError(t, err)
+			assert.Len(t, violations, tt.expectedViolations, violations)
+		})
+	}
+}
+
+func TestCheckAllocationPolicyBoundary(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avs := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	reader := &fakeAllocationDiffReader{
+		maxMagnitudes:  map[gethcommon.Address]uint64{strategyAddress: 1e18},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{},
+	}
+	maxBipsPerAvs := uint64(2000)
+	minUnallocatedBips := uint64(8000)
+	policy := &allocationPolicy{
+		MaxBipsPerAvs:           &maxBipsPerAvs,
+		MaxTotalBipsPerStrategy: &maxBipsPerAvs,
+		MinUnallocatedBips:      &minUnallocatedBips,
+	}
+	limitMagnitude := CalculateMagnitudeToUpdate(1e18, maxBipsPerAvs)
+
+	tests := []struct {
+		name               string
+		magnitude          uint64
+		expectedViolations int
+	}{
+		{name: "at the limit", magnitude: limitMagnitude},
+		// Rounds down to the limit in bips, so it must be compared in magnitude
+		{name: "one unit above the limit", magnitude: limitMagnitude + 1, expectedViolations: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := checkAllocationPolicy(
+				context.Background(),
+				reader,
+				operatorAddress,
+				policy,
+				[]allocateParams{{
+					OperatorSet:   allocationmanager.OperatorSet{Avs: avs, Id: 1},
+					Strategies:    []gethcommon.Address{strategyAddress},
+					NewMagnitudes: []uint64{tt.magnitude},
+				}},
+			)
+			assert.NoError(t, err)
+			assert.Len(t, violations, tt.expectedViolations, violations)
+		})
+	}
+}
+
+func TestMagnitudeToBips(t *testing.T) {
+	assert.Equal(t, uint64(2000), magnitudeToBips(CalculateMagnitudeToUpdate(1e18, 2000), 1e18))
+	assert.Equal(t, uint64(3333), magnitudeToBips(1, 3))
+	assert.Equal(t, uint64(0), magnitudeToBips(100, 0))
+	assert.Equal(t, uint64(3334), magnitudeToBipsRoundedUp(1, 3))
+	assert.Equal(t, uint64(2001), magnitudeToBipsRoundedUp(2e17+1, 1e18))
+	assert.Equal(t, 1, compareMagnitudeToBips(2e17+1, 1e18, 2000))
+	assert.Equal(t, 0, compareMagnitudeToBips(2e17, 1e18, 2000))
+	assert.Equal(t, -1, compareMagnitudeToBips(2e17-1, 1e18, 2000))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
@@ -22,12 +23,17 @@ import (
 
 func SetDelayCmd(p utils.Prompter) *cli.Command {
 	setDelayCmd := &cli.Command{
-		Name:        "set-delay",
-		UsageText:   "set-delay [flags] <delay>",
-		Usage:       "Set the allocation delay for operator in blocks",
-		Description: "Set the allocation delay for operator. It will take effect after the delay period",
-		Flags:       getSetAllocationDelayFlags(),
-		After:       telemetry.AfterRunAction(),
+		Name:      "set-delay",
+		UsageText: "set-delay [flags] <delay>",
+		Usage:     "Set the allocation delay for operator in blocks",
+		Description: `
+Set the allocation delay for operator. It will take effect after the allocation configuration delay.
+
+The current allocation delay and any pending change are shown before the transaction is created.
+Use 'allocations get-delay' to only read the allocation delay.
+`,
+		Flags: getSetAllocationDelayFlags(),
+		After: telemetry.AfterRunAction(),
 		Action: func(c *cli.Context) error {
 			return setDelayAction(c, p)
 		},
@@ -50,6 +56,25 @@ func setDelayAction(cCtx *cli.Context, p utils.Prompter) error {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
+	delayInfo, err := readAllocationDelayInfo(
+		ctx,
+		ethClient,
+		config.delegationManagerAddress,
+		config.operatorAddress,
+		logger,
+	)
+	if err != nil {
+		return err
+	}
+	if config.broadcast || config.outputType != utils.CallDataOutputType {
+		printAllocationDelayChange(
+			delayInfo,
+			config.allocationDelay,
+			getAverageBlockTime(ctx, ethClient, delayInfo.CurrentBlock, logger),
+			time.Now(),
+		)
+	}
+
 	if config.broadcast {
 		confirm, err := p.Confirm(
 			"This will set the allocation delay for operator. Do you want to continue?",
@@ -131,6 +156,31 @@ func setDelayAction(cCtx *cli.Context, p utils.Prompter) error {
 	return nil
 }
 
+func printAllocationDelayChange(
+	delayInfo *AllocationDelayInfo,
+	newDelay uint32,
+	averageBlockTime time.Duration,
+	now time.Time,
+) {
+	delayInfo.PrintPretty(averageBlockTime, now)
+	if delayInfo.PendingDelay != nil {
+		fmt.Printf(
+			"%s A change of the allocation delay to %d blocks is already pending. "+
+				"Setting a new delay replaces it and restarts the configuration delay\n",
+			utils.EmojiWarning,
+			*delayInfo.PendingDelay,
+		)
+	}
+	effectBlock := delayInfo.earliestNewDelayEffectBlock()
+	fmt.Printf(
+		"New allocation delay of %d blocks takes effect at block %d (%s UTC) at the earliest\n",
+		newDelay,
+		effectBlock,
+		estimateBlockTime(effectBlock, delayInfo.CurrentBlock, averageBlockTime, now),
+	)
+	fmt.Println()
+}
+
 func getSetAllocationDelayFlags() []cli.Flag {
 	baseFlags := []cli.Flag{
 		&flags.NetworkFlag,
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
@@ -31,6 +32,13 @@ func ShowCmd(p utils.Prompter) *cli.Command {
 		After: telemetry.AfterRunAction(),
 		Description: `
 Command to show allocations
+
+If --strategy-addresses is not set, the strategies are discovered from the operator sets the operator is
+registered for or has allocations in, and from the strategies stakers delegated to the operator in.
+Use --avs-addresses to only show allocations to operator sets of the given AVSs.
+
+All reads are batched through Multicall3 and made at the same block, so the allocation state is consistent.
+Use --multicall-batch-size and --max-concurrency to tune the load on the RPC.
 `,
 		Flags: getShowFlags(),
 		Action: func(cCtx *cli.Context) error {
@@ -55,33 +63,57 @@ func showAction(cCtx *cli.Context, p utils.Prompter) error {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
-	elReader, err := elcontracts.NewReaderFromConfig(
-		elcontracts.Config{
-			DelegationManagerAddress: config.delegationManagerAddress,
-		},
+	// All reads are pinned to the same block so the allocation state is consistent
+	elReader, err := batchreader.NewReader(
+		ctx,
 		ethClient,
+		config.delegationManagerAddress,
+		config.batchSize,
+		config.maxConcurrency,
 		logger,
 	)
 	if err != nil {
-		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+		return err
 	}
 
 	/*
-		1. Get the allocatable magnitude for all strategies
+		0. Discover the strategies of the operator if none are provided
 	*/
-	for _, strategyAddress := range config.strategyAddresses {
-		allocatableMagnitude, err := elReader.GetAllocatableMagnitude(
+	if len(config.strategyAddresses) == 0 {
+		strategyAddresses, err := discoverOperatorStrategies(
 			ctx,
+			elReader,
+			ethClient,
 			config.operatorAddress,
-			strategyAddress,
+			logger,
 		)
 		if err != nil {
-			return eigenSdkUtils.WrapError("failed to get allocatable magnitude", err)
+			return eigenSdkUtils.WrapError("failed to discover strategies", err)
+		}
+		if len(strategyAddresses) == 0 {
+			fmt.Printf("No strategies found for operator %s\n", config.operatorAddress.Hex())
+			return nil
 		}
+		config.strategyAddresses = strategyAddresses
+	}
+	tokenNames := getStrategyTokenNames(ctx, elReader, ethClient, config.strategyAddresses, logger)
+
+	/*
+		1. Get the allocatable magnitude for all strategies
+	*/
+	allocatableMagnitudes, err := elReader.GetAllocatableMagnitudes(
+		ctx,
+		config.operatorAddress,
+		config.strategyAddresses,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get allocatable magnitude", err)
+	}
+	for _, strategyAddress := range config.strategyAddresses {
 		logger.Debugf(
 			"Allocatable magnitude for strategy %v: %s",
 			strategyAddress,
-			common.FormatNumberWithUnderscores(common.Uint64ToString(allocatableMagnitude)),
+			common.FormatNumberWithUnderscores(common.Uint64ToString(allocatableMagnitudes[strategyAddress])),
 		)
 	}
 
@@ -104,17 +136,18 @@ func showAction(cCtx *cli.Context, p utils.Prompter) error {
 	/*
 		3. Get allocation info for the operator
 	*/
+	allocationsPerStrategy, err := elReader.GetAllocationInfos(
+		ctx,
+		config.operatorAddress,
+		config.strategyAddresses,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get allocations", err)
+	}
 	allAllocations := make(map[string][]elcontracts.AllocationInfo, len(config.strategyAddresses))
 	for _, strategyAddress := range config.strategyAddresses {
-		allocations, err := elReader.GetAllocationInfo(
-			ctx,
-			config.operatorAddress,
-			strategyAddress,
-		)
-		if err != nil {
-			return eigenSdkUtils.WrapError("failed to get allocations", err)
-		}
-		allAllocations[strategyAddress.String()] = allocations
+		allocations := allocationsPerStrategy[strategyAddress]
+		allAllocations[strategyAddress.String()] = filterAllocationsByAvs(allocations, config.avsAddresses)
 	}
 
 	/*
@@ -170,15 +203,23 @@ func showAction(cCtx *cli.Context, p utils.Prompter) error {
 	if err != nil {
 		return err
 	}
-
-	for key, val := range operatorDelegatedSharesMap {
-		fmt.Printf("Strategy Address: %s, Shares %s\n", key, common.FormatNumberWithUnderscores(val.String()))
+	for i := range slashableMagnitudeHolders {
+		slashableMagnitudeHolders[i].TokenName = tokenNames[slashableMagnitudeHolders[i].StrategyAddress]
+	}
+	for i := range dergisteredOpsets {
+		dergisteredOpsets[i].TokenName = tokenNames[dergisteredOpsets[i].StrategyAddress]
 	}
 
-	currBlockNumber, err := ethClient.BlockNumber(ctx)
-	if err != nil {
-		return eigenSdkUtils.WrapError("failed to get current block number", err)
+	for _, strategyAddress := range config.strategyAddresses {
+		fmt.Printf(
+			"Strategy Address: %s (%s), Shares %s\n",
+			strategyAddress.String(),
+			tokenNames[strategyAddress],
+			common.FormatNumberWithUnderscores(operatorDelegatedSharesMap[strategyAddress.String()].String()),
+		)
 	}
+
+	currBlockNumber := elReader.BlockNumber()
 	delay, err := elReader.GetAllocationDelay(ctx, config.operatorAddress)
 	if err != nil {
 		return err
@@ -305,6 +346,27 @@ func prepareAllocationsData(
 	return slashableMagnitudeHolders, dergisteredOpsets, nil
 }
 
+// filterAllocationsByAvs returns the allocations to operator sets of the given AVSs. All allocations
+// are returned if no AVS is given.
+func filterAllocationsByAvs(
+	allocations []elcontracts.AllocationInfo,
+	avsAddresses []gethcommon.Address,
+) []elcontracts.AllocationInfo {
+	if len(avsAddresses) == 0 {
+		return allocations
+	}
+	filtered := make([]elcontracts.AllocationInfo, 0, len(allocations))
+	for _, alloc := range allocations {
+		for _, avsAddress := range avsAddresses {
+			if alloc.AvsAddress == avsAddress {
+				filtered = append(filtered, alloc)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 func getSharePercentage(shares *big.Int, totalShares *big.Int) *big.Float {
 	percentageShares := big.NewInt(1)
 	percentageShares = percentageShares.Mul(shares, big.NewInt(100))
@@ -322,11 +384,12 @@ func getSlashableShares(
 	reader elChainReader,
 ) (map[gethcommon.Address]map[string]*big.Int, error) {
 	result := make(map[gethcommon.Address]map[string]*big.Int)
-	for _, opSet := range opSets {
-		slashableSharesMap, err := reader.GetSlashableShares(ctx, operatorAddress, opSet, strategyAddresses)
-		if err != nil {
-			return nil, err
-		}
+	slashableSharesPerSet, err := getSlashableSharesPerSet(ctx, operatorAddress, opSets, strategyAddresses, reader)
+	if err != nil {
+		return nil, err
+	}
+	for i, opSet := range opSets {
+		slashableSharesMap := slashableSharesPerSet[i]
 
 		for strat, shares := range slashableSharesMap {
 			if _, ok := result[strat]; !ok {
@@ -338,6 +401,29 @@ func getSlashableShares(
 	return result, nil
 }
 
+// getSlashableSharesPerSet returns the slashable shares for each operator set, in a single batch if the
+// reader supports it
+func getSlashableSharesPerSet(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	opSets []allocationmanager.OperatorSet,
+	strategyAddresses []gethcommon.Address,
+	reader elChainReader,
+) ([]map[gethcommon.Address]*big.Int, error) {
+	if batch, ok := reader.(slashableSharesBatchReader); ok {
+		return batch.GetSlashableSharesForSets(ctx, operatorAddress, opSets, strategyAddresses)
+	}
+	result := make([]map[gethcommon.Address]*big.Int, len(opSets))
+	for i, opSet := range opSets {
+		slashableSharesMap, err := reader.GetSlashableShares(ctx, operatorAddress, opSet, strategyAddresses)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = slashableSharesMap
+	}
+	return result, nil
+}
+
 func getSharesFromMagnitude(totalShare *big.Int, magnitude uint64, totalMagnitude uint64) (*big.Int, *big.Float) {
 	/*
 	 * shares = totalShare * magnitude / totalMagnitude
@@ -348,13 +434,9 @@ func getSharesFromMagnitude(totalShare *big.Int, magnitude uint64, totalMagnitud
 		return big.NewInt(0), big.NewFloat(0)
 	}
 
-	opShares := big.NewInt(1)
-	opShares = opShares.Set(totalShare)
-	shares := opShares.Mul(opShares, big.NewInt(int64(magnitude)))
-	shares = shares.Div(shares, big.NewInt(int64(totalMagnitude)))
+	shares := common.GetSharesForMagnitude(totalShare, magnitude, totalMagnitude)
 
-	percentageShares := big.NewInt(1)
-	percentageShares = percentageShares.Mul(opShares, big.NewInt(100))
+	percentageShares := new(big.Int).Mul(shares, big.NewInt(100))
 	percentageSharesFloat := new(
 		big.Float,
 	).Quo(new(big.Float).SetInt(percentageShares), new(big.Float).SetInt(totalShare))
@@ -375,6 +457,8 @@ func readAndValidateShowConfig(cCtx *cli.Context, logger *logging.Logger) (*show
 	strategyAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.StrategyAddressesFlag.Name))
 	outputFile := cCtx.String(flags.OutputFileFlag.Name)
 	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	batchSize := cCtx.Int(flags.MulticallBatchSizeFlag.Name)
+	maxConcurrency := cCtx.Int(flags.MaxConcurrencyFlag.Name)
 
 	chainId := utils.NetworkNameToChainId(network)
 	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
@@ -396,6 +480,8 @@ func readAndValidateShowConfig(cCtx *cli.Context, logger *logging.Logger) (*show
 		output:                   outputFile,
 		outputType:               outputType,
 		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+		batchSize:                batchSize,
+		maxConcurrency:           maxConcurrency,
 	}, nil
 }
 
@@ -411,6 +497,8 @@ func getShowFlags() []cli.Flag {
 		&flags.OutputFileFlag,
 		&flags.OutputTypeFlag,
 		&flags.DelegationManagerAddressFlag,
+		&flags.MulticallBatchSizeFlag,
+		&flags.MaxConcurrencyFlag,
 	}
 
 	sort.Sort(cli.FlagsByName(baseFlags))
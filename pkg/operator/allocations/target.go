@@ -0,0 +1,337 @@
+package allocations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"gopkg.in/yaml.v2"
+)
+
+const maxBips = 10_000
+
+// TargetAllocationReader reads the allocations of an operator which ComputeTargetAllocations moves to the targets
+type TargetAllocationReader interface {
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+	IsOperatorSlashable(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) (bool, error)
+}
+
+// targetAllocationFile is the YAML format of a target allocation file. Each strategy lists its
+// complete allocation across all operator sets.
+type targetAllocationFile struct {
+	Strategies []targetStrategyAllocation `yaml:"strategies"`
+}
+
+type targetStrategyAllocation struct {
+	StrategyAddress string                        `yaml:"strategy_address"`
+	Allocations     []targetOperatorSetAllocation `yaml:"allocations"`
+}
+
+type targetOperatorSetAllocation struct {
+	AvsAddress    string `yaml:"avs_address"`
+	OperatorSetId uint32 `yaml:"operator_set_id"`
+	Bips          uint64 `yaml:"bips"`
+}
+
+// parseTargetAllocationsFile reads a target allocation file. YAML files (.yaml or .yml) use the
+// targetAllocationFile format, CSV files use the same columns as --csv-file.
+func parseTargetAllocationsFile(filePath string) ([]allocation, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv":
+		return parseAllocationsCSV(filePath)
+	case ".yaml", ".yml":
+		return parseTargetAllocationsYAML(filePath)
+	default:
+		return nil, fmt.Errorf("unsupported target file %s. Supported formats are .yaml, .yml and .csv", filePath)
+	}
+}
+
+func parseTargetAllocationsYAML(filePath string) ([]allocation, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var file targetAllocationFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	allocations := make([]allocation, 0)
+	for _, strategy := range file.Strategies {
+		if !gethcommon.IsHexAddress(strategy.StrategyAddress) {
+			return nil, fmt.Errorf("invalid strategy address: %s", strategy.StrategyAddress)
+		}
+		for _, a := range strategy.Allocations {
+			if !gethcommon.IsHexAddress(a.AvsAddress) {
+				return nil, fmt.Errorf("invalid avs address: %s", a.AvsAddress)
+			}
+			allocations = append(allocations, allocation{
+				AvsAddress:      gethcommon.HexToAddress(a.AvsAddress),
+				OperatorSetId:   a.OperatorSetId,
+				StrategyAddress: gethcommon.HexToAddress(strategy.StrategyAddress),
+				Bips:            a.Bips,
+			})
+		}
+	}
+	return allocations, nil
+}
+
+// validateTargetAllocations checks that the targets are unique and that the targets of each
+// strategy add up to at most 100%
+func validateTargetAllocations(targets []allocation) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("no target allocations found")
+	}
+	if err := validateDataFromCSV(targets); err != nil {
+		return err
+	}
+
+	totalBips := make(map[gethcommon.Address]uint64)
+	for _, target := range targets {
+		if target.Bips > maxBips {
+			return fmt.Errorf(
+				"bips of strategy %s for operator set %s/%d must be at most %d, got %d",
+				target.StrategyAddress.Hex(),
+				target.AvsAddress.Hex(),
+				target.OperatorSetId,
+				maxBips,
+				target.Bips,
+			)
+		}
+		totalBips[target.StrategyAddress] += target.Bips
+	}
+	for strategy, bips := range totalBips {
+		if bips > maxBips {
+			return fmt.Errorf(
+				"target allocations of strategy %s add up to %d bips, which is more than %d bips",
+				strategy.Hex(),
+				bips,
+				maxBips,
+			)
+		}
+	}
+	return nil
+}
+
+// TargetAllocation is the target allocation of a strategy to an operator set, in bips of the max magnitude
+type TargetAllocation struct {
+	AvsAddress      gethcommon.Address
+	OperatorSetId   uint32
+	StrategyAddress gethcommon.Address
+	Bips            uint64
+}
+
+// ComputeTargetAllocations validates the targets and returns the ModifyAllocations params that move the
+// allocations of the operator to them, as the target file of the update command does
+func ComputeTargetAllocations(
+	ctx context.Context,
+	reader TargetAllocationReader,
+	operatorAddress gethcommon.Address,
+	targets []TargetAllocation,
+) ([]allocationmanager.IAllocationManagerTypesAllocateParams, error) {
+	allocations := make([]allocation, 0, len(targets))
+	for _, target := range targets {
+		allocations = append(allocations, allocation(target))
+	}
+	if err := validateTargetAllocations(allocations); err != nil {
+		return nil, err
+	}
+	return computeTargetAllocations(ctx, reader, operatorAddress, allocations)
+}
+
+// computeTargetAllocations returns the ModifyAllocations params that move the allocations of the operator
+// to the targets. The targets describe the complete allocation of each strategy, so operator sets which
+// currently have an allocation of a listed strategy but are not in the targets are deallocated.
+// Unchanged allocations are skipped and all deallocations come before the allocations, so magnitude freed
+// by instant deallocations can be allocated in the same transaction.
+func computeTargetAllocations(
+	ctx context.Context,
+	reader TargetAllocationReader,
+	operatorAddress gethcommon.Address,
+	targets []allocation,
+) ([]allocationmanager.IAllocationManagerTypesAllocateParams, error) {
+	strategies := getUniqueStrategies(targets)
+	sort.Slice(strategies, func(i, j int) bool {
+		return bytes.Compare(strategies[i].Bytes(), strategies[j].Bytes()) < 0
+	})
+
+	maxMagnitudes, err := reader.GetMaxMagnitudes(ctx, operatorAddress, strategies)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+
+	targetsPerStrategy := make(map[gethcommon.Address]map[allocationmanager.OperatorSet]uint64)
+	for _, target := range targets {
+		if _, ok := targetsPerStrategy[target.StrategyAddress]; !ok {
+			targetsPerStrategy[target.StrategyAddress] = make(map[allocationmanager.OperatorSet]uint64)
+		}
+		opSet := allocationmanager.OperatorSet{Avs: target.AvsAddress, Id: target.OperatorSetId}
+		targetsPerStrategy[target.StrategyAddress][opSet] = target.Bips
+	}
+
+	slashableCache := make(map[allocationmanager.OperatorSet]bool)
+	isSlashable := func(opSet allocationmanager.OperatorSet) (bool, error) {
+		if slashable, ok := slashableCache[opSet]; ok {
+			return slashable, nil
+		}
+		slashable, err := reader.IsOperatorSlashable(ctx, operatorAddress, opSet)
+		if err != nil {
+			return false, err
+		}
+		slashableCache[opSet] = slashable
+		return slashable, nil
+	}
+
+	deallocations := newOperatorSetParams()
+	allocations := newOperatorSetParams()
+	for i, strategy := range strategies {
+		maxMagnitude := maxMagnitudes[i]
+		allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, strategy)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+		}
+
+		currentAllocations := make(map[allocationmanager.OperatorSet]elcontracts.AllocationInfo)
+		opSets := make([]allocationmanager.OperatorSet, 0)
+		for _, info := range allocationInfo {
+			opSet := allocationmanager.OperatorSet{Avs: info.AvsAddress, Id: info.OperatorSetId}
+			currentAllocations[opSet] = info
+			opSets = append(opSets, opSet)
+		}
+		for opSet := range targetsPerStrategy[strategy] {
+			if _, ok := currentAllocations[opSet]; !ok {
+				opSets = append(opSets, opSet)
+			}
+		}
+		sortOperatorSets(opSets)
+
+		// encumberedMagnitude is the magnitude of the strategy which is allocated or waiting to be
+		// deallocated once all changes of the transaction have been applied
+		encumberedMagnitude := uint64(0)
+		for _, opSet := range opSets {
+			currentMagnitude, pendingDiff, effectBlock := uint64(0), int64(0), uint32(0)
+			if info, ok := currentAllocations[opSet]; ok {
+				currentMagnitude = info.CurrentMagnitude.Uint64()
+				if info.PendingDiff != nil {
+					pendingDiff = info.PendingDiff.Int64()
+				}
+				effectBlock = info.EffectBlock
+			}
+			// Pending allocations are encumbered right away, pending deallocations only release their
+			// magnitude at the effect block
+			pendingMagnitude := uint64(int64(currentMagnitude) + pendingDiff)
+			encumbered := max(currentMagnitude, pendingMagnitude)
+
+			targetMagnitude := CalculateMagnitudeToUpdate(maxMagnitude, targetsPerStrategy[strategy][opSet])
+			if targetMagnitude == pendingMagnitude {
+				encumberedMagnitude += encumbered
+				continue
+			}
+			if pendingDiff != 0 {
+				return nil, fmt.Errorf(
+					"allocation of strategy %s to operator set %s/%d has a pending modification until block %d",
+					strategy.Hex(),
+					opSet.Avs.Hex(),
+					opSet.Id,
+					effectBlock,
+				)
+			}
+
+			if targetMagnitude > currentMagnitude {
+				allocations.add(opSet, strategy, targetMagnitude)
+				encumberedMagnitude += targetMagnitude
+				continue
+			}
+			deallocations.add(opSet, strategy, targetMagnitude)
+			slashable, err := isSlashable(opSet)
+			if err != nil {
+				return nil, eigenSdkUtils.WrapError("failed to check if operator is slashable", err)
+			}
+			// Deallocations from operator sets the operator is not slashable by take effect immediately
+			if slashable {
+				encumberedMagnitude += currentMagnitude
+			} else {
+				encumberedMagnitude += targetMagnitude
+			}
+		}
+
+		if encumberedMagnitude > maxMagnitude {
+			return nil, fmt.Errorf(
+				"strategy %s would have %d magnitude allocated or pending deallocation, which exceeds "+
+					"the max magnitude %d. Wait for the pending deallocations to complete before allocating",
+				strategy.Hex(),
+				encumberedMagnitude,
+				maxMagnitude,
+			)
+		}
+	}
+
+	return append(deallocations.params(), allocations.params()...), nil
+}
+
+// operatorSetParams groups the strategies and magnitudes of AllocateParams by operator set
+type operatorSetParams struct {
+	order   []allocationmanager.OperatorSet
+	byOpSet map[allocationmanager.OperatorSet]*allocationmanager.IAllocationManagerTypesAllocateParams
+}
+
+func newOperatorSetParams() *operatorSetParams {
+	return &operatorSetParams{
+		order:   make([]allocationmanager.OperatorSet, 0),
+		byOpSet: make(map[allocationmanager.OperatorSet]*allocationmanager.IAllocationManagerTypesAllocateParams),
+	}
+}
+
+func (o *operatorSetParams) add(opSet allocationmanager.OperatorSet, strategy gethcommon.Address, magnitude uint64) {
+	params, ok := o.byOpSet[opSet]
+	if !ok {
+		params = &allocationmanager.IAllocationManagerTypesAllocateParams{OperatorSet: opSet}
+		o.byOpSet[opSet] = params
+		o.order = append(o.order, opSet)
+	}
+	params.Strategies = append(params.Strategies, strategy)
+	params.NewMagnitudes = append(params.NewMagnitudes, magnitude)
+}
+
+func (o *operatorSetParams) params() []allocationmanager.IAllocationManagerTypesAllocateParams {
+	sortOperatorSets(o.order)
+	params := make([]allocationmanager.IAllocationManagerTypesAllocateParams, 0, len(o.order))
+	for _, opSet := range o.order {
+		params = append(params, *o.byOpSet[opSet])
+	}
+	return params
+}
+
+func sortOperatorSets(opSets []allocationmanager.OperatorSet) {
+	sort.Slice(opSets, func(i, j int) bool {
+		if c := bytes.Compare(opSets[i].Avs.Bytes(), opSets[j].Avs.Bytes()); c != 0 {
+			return c < 0
+		}
+		return opSets[i].Id < opSets[j].Id
+	})
+}
@@ -0,0 +1,180 @@
+package allocations
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndValidateTargetAllocationsFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		filePath        string
+		expectedTargets []allocation
+		expectError     bool
+	}{
+		{
+			name:     "yaml target file",
+			filePath: "testdata/target_allocations.yaml",
+			expectedTargets: []allocation{
+				{
+					AvsAddress:      gethcommon.HexToAddress("0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f"),
+					OperatorSetId:   1,
+					StrategyAddress: gethcommon.HexToAddress("0x49989b32351Eb9b8ab2d5623cF22E7F7C23e5630"),
+					Bips:            2000,
+				},
+				{
+					AvsAddress:      gethcommon.HexToAddress("0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f"),
+					OperatorSetId:   3,
+					StrategyAddress: gethcommon.HexToAddress("0x49989b32351Eb9b8ab2d5623cF22E7F7C23e5630"),
+					Bips:            1000,
+				},
+				{
+					AvsAddress:      gethcommon.HexToAddress("0x111116fE4F8C2f83E3eB2318F090557b7CD0BF76"),
+					OperatorSetId:   4,
+					StrategyAddress: gethcommon.HexToAddress("0x232326fE4F8C2f83E3eB2318F090557b7CD02222"),
+					Bips:            3000,
+				},
+			},
+		},
+		{
+			name:        "csv target file with duplicates",
+			filePath:    "testdata/allocations_duplicate.csv",
+			expectError: true,
+		},
+		{
+			name:        "csv target file above 100% for a strategy",
+			filePath:    "testdata/target_allocations_over_max.csv",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := parseTargetAllocationsFile(tt.filePath)
+			assert.NoError(t, err)
+			err = validateTargetAllocations(targets)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTargets, targets)
+		})
+	}
+}
+
+func TestComputeTargetAllocations(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	slashableSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 1}
+	nonSlashableSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 2}
+	newSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 3}
+	unchangedSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 4}
+
+	currentAllocations := []elcontracts.AllocationInfo{
+		{AvsAddress: avsAddress, OperatorSetId: 1, CurrentMagnitude: big.NewInt(500), PendingDiff: big.NewInt(0)},
+		{AvsAddress: avsAddress, OperatorSetId: 2, CurrentMagnitude: big.NewInt(300), PendingDiff: big.NewInt(0)},
+		{AvsAddress: avsAddress, OperatorSetId: 4, CurrentMagnitude: big.NewInt(50), PendingDiff: big.NewInt(0)},
+	}
+	target := func(opSet allocationmanager.OperatorSet, bips uint64) allocation {
+		return allocation{
+			AvsAddress:      opSet.Avs,
+			OperatorSetId:   opSet.Id,
+			StrategyAddress: strategyAddress,
+			Bips:            bips,
+		}
+	}
+
+	tests := []struct {
+		name                string
+		allocations         []elcontracts.AllocationInfo
+		targets             []allocation
+		expectError         bool
+		expectedAllocations []allocationmanager.IAllocationManagerTypesAllocateParams
+	}{
+		{
+			name:        "deallocations before allocations and unlisted operator sets deallocated",
+			allocations: currentAllocations,
+			targets:     []allocation{target(slashableSet, 2000), target(newSet, 4000), target(unchangedSet, 500)},
+			expectedAllocations: []allocationmanager.IAllocationManagerTypesAllocateParams{
+				{
+					OperatorSet:   slashableSet,
+					Strategies:    []gethcommon.Address{strategyAddress},
+					NewMagnitudes: []uint64{200},
+				},
+				{
+					OperatorSet:   nonSlashableSet,
+					Strategies:    []gethcommon.Address{strategyAddress},
+					NewMagnitudes: []uint64{0},
+				},
+				{
+					OperatorSet:   newSet,
+					Strategies:    []gethcommon.Address{strategyAddress},
+					NewMagnitudes: []uint64{400},
+				},
+			},
+		},
+		{
+			name:        "slashable deallocation does not free magnitude for allocations",
+			allocations: currentAllocations,
+			targets:     []allocation{target(slashableSet, 2000), target(newSet, 6000), target(unchangedSet, 500)},
+			expectError: true,
+		},
+		{
+			name: "pending modification",
+			allocations: []elcontracts.AllocationInfo{
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    1,
+					CurrentMagnitude: big.NewInt(500),
+					PendingDiff:      big.NewInt(-100),
+					EffectBlock:      100,
+				},
+			},
+			targets:     []allocation{target(slashableSet, 1000)},
+			expectError: true,
+		},
+		{
+			name: "target matches pending modification",
+			allocations: []elcontracts.AllocationInfo{
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    1,
+					CurrentMagnitude: big.NewInt(500),
+					PendingDiff:      big.NewInt(-100),
+					EffectBlock:      100,
+				},
+			},
+			targets:             []allocation{target(slashableSet, 4000)},
+			expectedAllocations: []allocationmanager.IAllocationManagerTypesAllocateParams{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeAllocationDiffReader{
+				maxMagnitudes:  map[gethcommon.Address]uint64{strategyAddress: 1000},
+				allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{strategyAddress: tt.allocations},
+				slashableSets:  map[allocationmanager.OperatorSet]bool{slashableSet: true},
+			}
+			allocations, err := computeTargetAllocations(context.Background(), reader, operatorAddress, tt.targets)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAllocations, allocations)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 type BulkModifyAllocations struct {
 	Allocations           []allocationmanager.IAllocationManagerTypesAllocateParams
 	AllocatableMagnitudes map[gethcommon.Address]uint64
+	PolicyViolations      []string
 }
 
 func (b *BulkModifyAllocations) PrintPretty() {
@@ -101,7 +102,13 @@ type updateConfig struct {
 	bipsToAllocate           uint64
 	signerConfig             *types.SignerConfig
 	csvFilePath              string
+	targetFilePath           string
+	policy                   *allocationPolicy
+	policyFilePath           string
+	overridePolicy           bool
 	isSilent                 bool
+	batchSize                int
+	maxConcurrency           int
 }
 
 type allocation struct {
@@ -126,6 +133,32 @@ type allocationDelayConfig struct {
 	callerAddress            gethcommon.Address
 }
 
+type clearQueueConfig struct {
+	network                  string
+	rpcUrl                   string
+	environment              string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	broadcast                bool
+	operatorAddress          gethcommon.Address
+	callerAddress            gethcommon.Address
+	strategyAddresses        []gethcommon.Address
+	signerConfig             *types.SignerConfig
+	delegationManagerAddress gethcommon.Address
+}
+
+type getAllocationDelayConfig struct {
+	network                  string
+	rpcUrl                   string
+	environment              string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	operatorAddress          gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+}
+
 type showConfig struct {
 	network                  string
 	rpcUrl                   string
@@ -137,12 +170,27 @@ type showConfig struct {
 	delegationManagerAddress gethcommon.Address
 	avsAddresses             []gethcommon.Address
 	strategyAddresses        []gethcommon.Address
+	batchSize                int
+	maxConcurrency           int
+}
+
+type exportConfig struct {
+	network                  string
+	rpcUrl                   string
+	environment              string
+	chainID                  *big.Int
+	output                   string
+	operatorAddress          gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+	avsAddresses             []gethcommon.Address
+	strategyAddresses        []gethcommon.Address
 }
 
 type SlashableMagnitudeHolders []SlashableMagnitudesHolder
 
 type SlashableMagnitudesHolder struct {
 	StrategyAddress          gethcommon.Address `csv:"strategy_address"`
+	TokenName                string             `csv:"token_name"`
 	AVSAddress               gethcommon.Address `csv:"avs_address"`
 	OperatorSetId            uint32             `csv:"operator_set_id"`
 	SlashableMagnitude       uint64             `csv:"-"`
@@ -215,6 +263,7 @@ func (s SlashableMagnitudeHolders) PrintPretty() {
 	// Define column headers and widths
 	headers := []string{
 		"Strategy Address",
+		"Token",
 		"AVS Address",
 		"OperatorSet ID",
 		"Slashable Shares (Wei)",
@@ -223,7 +272,7 @@ func (s SlashableMagnitudeHolders) PrintPretty() {
 		"Upcoming Shares %",
 		"Update Block",
 	}
-	widths := []int{len(headers[0]) + 1, len(headers[1]) + 3, 15, 30, 25, 30, 25, 25}
+	widths := []int{len(headers[0]) + 1, 20, len(headers[2]) + 3, 15, 30, 25, 30, 25, 25}
 
 	// print dashes
 	for _, width := range widths {
@@ -248,15 +297,16 @@ func (s SlashableMagnitudeHolders) PrintPretty() {
 
 		upcomingSharesDisplay := common.FormatNumberWithUnderscores(holder.NewAllocationShares.String())
 
-		fmt.Printf("| %-*s| %-*s| %-*d| %-*s| %-*s| %-*s| %-*s| %-*d|\n",
+		fmt.Printf("| %-*s| %-*s| %-*s| %-*d| %-*s| %-*s| %-*s| %-*s| %-*d|\n",
 			widths[0], common.ShortEthAddress(holder.StrategyAddress),
-			widths[1], common.ShortEthAddress(holder.AVSAddress),
-			widths[2], holder.OperatorSetId,
-			widths[3], common.FormatNumberWithUnderscores(holder.Shares.String()),
-			widths[4], holder.SharesPercentage+" %",
-			widths[5], upcomingSharesDisplay,
-			widths[6], holder.UpcomingSharesPercentage+" %",
-			widths[7], holder.UpdateBlock,
+			widths[1], holder.TokenName,
+			widths[2], common.ShortEthAddress(holder.AVSAddress),
+			widths[3], holder.OperatorSetId,
+			widths[4], common.FormatNumberWithUnderscores(holder.Shares.String()),
+			widths[5], holder.SharesPercentage+" %",
+			widths[6], upcomingSharesDisplay,
+			widths[7], holder.UpcomingSharesPercentage+" %",
+			widths[8], holder.UpdateBlock,
 		)
 	}
 
@@ -279,6 +329,7 @@ func (s SlashableMagnitudeHolders) PrintJSON() {
 type DeregsiteredOperatorSets []DeregisteredOperatorSet
 type DeregisteredOperatorSet struct {
 	StrategyAddress    gethcommon.Address
+	TokenName          string
 	AVSAddress         gethcommon.Address
 	OperatorSetId      uint32
 	SlashableMagnitude uint64
@@ -290,12 +341,13 @@ func (s DeregsiteredOperatorSets) PrintPretty() {
 	// Define column headers and widths
 	headers := []string{
 		"Strategy Address",
+		"Token",
 		"AVS Address",
 		"OperatorSet ID",
 		"Slashable Shares (Wei)",
 		"Shares %",
 	}
-	widths := []int{len(headers[0]) + 1, len(headers[1]) + 3, 15, 30, 25}
+	widths := []int{len(headers[0]) + 1, 20, len(headers[2]) + 3, 15, 30, 25}
 
 	// print dashes
 	for _, width := range widths {
@@ -317,12 +369,13 @@ func (s DeregsiteredOperatorSets) PrintPretty() {
 
 	// Print data rows
 	for _, holder := range s {
-		fmt.Printf("| %-*s| %-*s| %-*d| %-*s| %-*s|\n",
+		fmt.Printf("| %-*s| %-*s| %-*s| %-*d| %-*s| %-*s|\n",
 			widths[0], common.ShortEthAddress(holder.StrategyAddress),
-			widths[1], common.ShortEthAddress(holder.AVSAddress),
-			widths[2], holder.OperatorSetId,
-			widths[3], common.FormatNumberWithUnderscores(holder.Shares.String()),
-			widths[4], holder.SharesPercentage+" %",
+			widths[1], holder.TokenName,
+			widths[2], common.ShortEthAddress(holder.AVSAddress),
+			widths[3], holder.OperatorSetId,
+			widths[4], common.FormatNumberWithUnderscores(holder.Shares.String()),
+			widths[5], holder.SharesPercentage+" %",
 		)
 	}
 
@@ -354,3 +407,18 @@ type AllocDetails struct {
 	Magnitude uint64
 	Timestamp uint32
 }
+
+type historyConfig struct {
+	network                  string
+	rpcUrl                   string
+	environment              string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	operatorAddress          gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+	avsAddresses             []gethcommon.Address
+	strategyAddresses        []gethcommon.Address
+	fromBlock                *uint64
+	toBlock                  uint64
+}
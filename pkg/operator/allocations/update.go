@@ -12,6 +12,7 @@ import (
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
@@ -43,6 +44,37 @@ type elChainReader interface {
 		operatorSet allocationmanager.OperatorSet,
 		strategies []gethcommon.Address,
 	) (map[gethcommon.Address]*big.Int, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+	IsOperatorSlashable(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) (bool, error)
+}
+
+// allocatableMagnitudesBatchReader is implemented by readers which read the allocatable magnitude of
+// multiple strategies in a single batch
+type allocatableMagnitudesBatchReader interface {
+	GetAllocatableMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) (map[gethcommon.Address]uint64, error)
+}
+
+// slashableSharesBatchReader is implemented by readers which read the slashable shares of multiple
+// operator sets in a single batch
+type slashableSharesBatchReader interface {
+	GetSlashableSharesForSets(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		operatorSets []allocationmanager.OperatorSet,
+		strategies []gethcommon.Address,
+	) ([]map[gethcommon.Address]*big.Int, error)
 }
 
 func UpdateCmd(p utils.Prompter) *cli.Command {
@@ -52,6 +84,38 @@ func UpdateCmd(p utils.Prompter) *cli.Command {
 		UsageText: "update",
 		Description: `
 Command to update allocations of slashable stake
+
+Before the transaction is created, the requested magnitudes are compared with the current allocations
+of the operator. The diff shows the current and new magnitude, the resulting slashable shares and the
+block at which each change takes effect. Use --output-type json to get the diff as JSON.
+
+Rows of --csv-file which match the current allocation, or the allocation once its pending modification
+takes effect, are skipped. Rows of allocations with a pending modification are skipped with a warning.
+
+Use --target-file to describe the complete target allocation of each strategy across all operator sets
+instead of individual updates. Operator sets with a current allocation of a listed strategy which are not
+in the file are deallocated. Only changed allocations are included in the transaction, deallocations are
+ordered before allocations and the total of each strategy is checked against its max magnitude up front.
+
+The target file can be a CSV file with the same columns as --csv-file or a YAML file:
+
+strategies:
+  - strategy_address: 0x...
+    allocations:
+      - avs_address: 0x...
+        operator_set_id: 1
+        bips: 2000
+
+Use --policy to check the allocations against the limits of an allocation policy before the transaction
+is created. Allocations which increase the magnitude of an operator set and break a limit are rejected,
+unless --override-policy is set. When broadcasting, the override is confirmed after the allocation diff is
+shown, otherwise the violations are printed as warnings. Limits which are not set are not checked:
+
+max_bips_per_avs: 2000
+max_total_bips_per_strategy: 8000
+min_unallocated_bips: 2000
+allowed_avs_addresses:
+  - 0x...
 		`,
 		Flags: getUpdateFlags(),
 		After: telemetry.AfterRunAction(),
@@ -78,15 +142,17 @@ func updateAllocations(cCtx *cli.Context, p utils.Prompter) error {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
-	elReader, err := elcontracts.NewReaderFromConfig(
-		elcontracts.Config{
-			DelegationManagerAddress: config.delegationManagerAddress,
-		},
+	// All reads are pinned to the same block so the allocations and the diff are consistent
+	elReader, err := batchreader.NewReader(
+		ctx,
 		ethClient,
+		config.delegationManagerAddress,
+		config.batchSize,
+		config.maxConcurrency,
 		logger,
 	)
 	if err != nil {
-		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+		return err
 	}
 
 	allocationsToUpdate, err := generateAllocationsParams(ctx, elReader, config, logger)
@@ -94,10 +160,37 @@ func updateAllocations(cCtx *cli.Context, p utils.Prompter) error {
 		return eigenSdkUtils.WrapError("failed to generate Allocations params", err)
 	}
 
+	diffs, err := computeAllocationDiff(
+		ctx,
+		elReader,
+		config.operatorAddress,
+		allocationsToUpdate.Allocations,
+		elReader.BlockNumber(),
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to compute allocation diff", err)
+	}
+
 	if config.broadcast {
 		if config.signerConfig == nil {
 			return errors.New("signer is required for broadcasting")
 		}
+		diffs.PrintPretty()
+		if len(allocationsToUpdate.PolicyViolations) > 0 {
+			fmt.Println()
+			fmt.Println(formatPolicyViolations(config.policyFilePath, allocationsToUpdate.PolicyViolations))
+			fmt.Println()
+			confirm, err := p.Confirm(
+				"The allocations violate the allocation policy. Do you want to override the policy and continue?",
+			)
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				logger.Info("Operation cancelled")
+				return nil
+			}
+		}
 		logger.Info("Broadcasting magnitude allocation update...")
 		eLWriter, err := common.GetELWriter(
 			config.callerAddress,
@@ -161,14 +254,36 @@ func updateAllocations(cCtx *cli.Context, p utils.Prompter) error {
 			} else {
 				fmt.Println(calldataHex)
 			}
+		} else if config.outputType == utils.JsonOutputType {
+			diffsJson, err := diffs.ToJSON()
+			if err != nil {
+				return err
+			}
+			if !common.IsEmptyString(config.output) {
+				err = common.WriteToFile(diffsJson, config.output)
+				if err != nil {
+					return err
+				}
+				logger.Infof("Allocation diff written to file: %s", config.output)
+			} else {
+				fmt.Println(string(diffsJson))
+			}
 		} else {
 			if !common.IsEmptyString(config.output) {
 				fmt.Println("output file not supported for pretty output type")
 				fmt.Println()
 			}
-			allocationsToUpdate.PrintPretty()
+			diffs.PrintPretty()
 		}
 		if !config.isSilent {
+			if len(allocationsToUpdate.PolicyViolations) > 0 {
+				fmt.Println()
+				fmt.Printf(
+					"%s %s\n",
+					utils.EmojiWarning,
+					formatPolicyViolations(config.policyFilePath, allocationsToUpdate.PolicyViolations),
+				)
+			}
 			txFeeDetails := common.GetTxFeeDetails(unsignedTx)
 			fmt.Println()
 			txFeeDetails.Print()
@@ -197,6 +312,11 @@ func getUpdateFlags() []cli.Flag {
 		&flags.SilentFlag,
 		&flags.CallerAddressFlag,
 		&BipsToAllocateFlag,
+		&TargetFileFlag,
+		&PolicyFileFlag,
+		&OverridePolicyFlag,
+		&flags.MulticallBatchSizeFlag,
+		&flags.MaxConcurrencyFlag,
 	}
 	allFlags := append(baseFlags, flags.GetSignerFlags()...)
 	sort.Sort(cli.FlagsByName(allFlags))
@@ -213,7 +333,12 @@ func generateAllocationsParams(
 	var allocatableMagnitudes map[gethcommon.Address]uint64
 
 	var err error
-	if len(config.csvFilePath) == 0 {
+	if !common.IsEmptyString(config.targetFilePath) {
+		allocations, allocatableMagnitudes, err = computeAllocationsFromTargetFile(ctx, config, elReader)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to compute allocations from target file", err)
+		}
+	} else if len(config.csvFilePath) == 0 {
 		magnitude, err := elReader.GetMaxMagnitudes(
 			ctx,
 			config.operatorAddress,
@@ -233,7 +358,7 @@ func generateAllocationsParams(
 		logger.Debugf("Total Magnitude: %d", magnitude)
 		logger.Debugf("Allocatable Magnitude: %d", allocatableMagnitude)
 		logger.Debugf("Bips to allocate: %d", config.bipsToAllocate)
-		magnitudeToUpdate := calculateMagnitudeToUpdate(magnitude[0], config.bipsToAllocate)
+		magnitudeToUpdate := CalculateMagnitudeToUpdate(magnitude[0], config.bipsToAllocate)
 		logger.Debugf("Magnitude to update: %d", magnitudeToUpdate)
 		malloc := allocationmanager.IAllocationManagerTypesAllocateParams{
 			Strategies: []gethcommon.Address{config.strategyAddress},
@@ -245,22 +370,46 @@ func generateAllocationsParams(
 		}
 		allocations = append(allocations, malloc)
 	} else {
-		allocations, allocatableMagnitudes, err = computeAllocations(config.csvFilePath, config.operatorAddress, elReader)
+		allocations, allocatableMagnitudes, err = computeAllocations(
+			ctx,
+			config.csvFilePath,
+			config.operatorAddress,
+			elReader,
+			logger,
+		)
 		if err != nil {
 			return nil, eigenSdkUtils.WrapError("failed to compute allocations", err)
 		}
 	}
 
+	var policyViolations []string
+	if config.policy != nil {
+		policyViolations, err = checkAllocationPolicy(ctx, elReader, config.operatorAddress, config.policy, allocations)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to check allocation policy", err)
+		}
+		if len(policyViolations) > 0 && !config.overridePolicy {
+			return nil, fmt.Errorf(
+				"%s\nUse --%s to proceed anyway",
+				formatPolicyViolations(config.policyFilePath, policyViolations),
+				OverridePolicyFlag.Name,
+			)
+		}
+	}
+
 	return &BulkModifyAllocations{
 		Allocations:           allocations,
 		AllocatableMagnitudes: allocatableMagnitudes,
+		PolicyViolations:      policyViolations,
 	}, nil
 }
 
 func computeAllocations(
+	ctx context.Context,
 	filePath string,
 	operatorAddress gethcommon.Address,
 	elReader elChainReader,
+	logger logging.Logger,
 ) ([]allocationmanager.IAllocationManagerTypesAllocateParams, map[gethcommon.Address]uint64, error) {
 	allocations, err := parseAllocationsCSV(filePath)
 	if err != nil {
@@ -278,6 +427,21 @@ func computeAllocations(
 		return nil, nil, eigenSdkUtils.WrapError("failed to get total magnitudes", err)
 	}
 
+	allocations, err = skipUnchangedAllocations(
+		ctx,
+		elReader,
+		operatorAddress,
+		allocations,
+		strategyTotalMagnitudes,
+		logger,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(allocations) == 0 {
+		return nil, nil, errors.New("allocations already match the csv file, nothing to update")
+	}
+
 	allocatableMagnitudePerStrategy, err := parallelGetAllocatableMagnitudes(strategies, operatorAddress, elReader)
 	if err != nil {
 		return nil, nil, eigenSdkUtils.WrapError("failed to get allocatable magnitudes", err)
@@ -287,6 +451,99 @@ func computeAllocations(
 	return magnitudeAllocations, allocatableMagnitudePerStrategy, nil
 }
 
+// skipUnchangedAllocations drops the rows of a csv file which the AllocationManager would reject: rows which
+// match the current allocation, or the allocation once its pending modification takes effect, and rows of
+// allocations with a pending modification. This lets a file from the export command be applied again after
+// editing only some of its rows.
+func skipUnchangedAllocations(
+	ctx context.Context,
+	reader allocationExportReader,
+	operatorAddress gethcommon.Address,
+	allocations []allocation,
+	maxMagnitudes map[gethcommon.Address]uint64,
+	logger logging.Logger,
+) ([]allocation, error) {
+	allocationInfos := make(map[gethcommon.Address]map[allocationmanager.OperatorSet]elcontracts.AllocationInfo)
+	changed := make([]allocation, 0, len(allocations))
+	for _, a := range allocations {
+		infos, ok := allocationInfos[a.StrategyAddress]
+		if !ok {
+			allocationInfo, err := reader.GetAllocationInfo(ctx, operatorAddress, a.StrategyAddress)
+			if err != nil {
+				return nil, eigenSdkUtils.WrapError("failed to get allocation info", err)
+			}
+			infos = make(map[allocationmanager.OperatorSet]elcontracts.AllocationInfo, len(allocationInfo))
+			for _, info := range allocationInfo {
+				infos[allocationmanager.OperatorSet{Avs: info.AvsAddress, Id: info.OperatorSetId}] = info
+			}
+			allocationInfos[a.StrategyAddress] = infos
+		}
+
+		currentMagnitude, pendingDiff, effectBlock := uint64(0), int64(0), uint32(0)
+		if info, ok := infos[allocationmanager.OperatorSet{Avs: a.AvsAddress, Id: a.OperatorSetId}]; ok {
+			currentMagnitude = info.CurrentMagnitude.Uint64()
+			if info.PendingDiff != nil {
+				pendingDiff = info.PendingDiff.Int64()
+			}
+			effectBlock = info.EffectBlock
+		}
+
+		magnitude := CalculateMagnitudeToUpdate(maxMagnitudes[a.StrategyAddress], a.Bips)
+		if magnitude == uint64(int64(currentMagnitude)+pendingDiff) {
+			logger.Debugf(
+				"Skipping unchanged allocation of strategy %s to operator set %s/%d",
+				a.StrategyAddress.Hex(),
+				a.AvsAddress.Hex(),
+				a.OperatorSetId,
+			)
+			continue
+		}
+		if pendingDiff != 0 {
+			logger.Warnf(
+				"Skipping allocation of strategy %s to operator set %s/%d, it has a pending modification until block %d",
+				a.StrategyAddress.Hex(),
+				a.AvsAddress.Hex(),
+				a.OperatorSetId,
+				effectBlock,
+			)
+			continue
+		}
+		changed = append(changed, a)
+	}
+	return changed, nil
+}
+
+func computeAllocationsFromTargetFile(
+	ctx context.Context,
+	config *updateConfig,
+	elReader elChainReader,
+) ([]allocationmanager.IAllocationManagerTypesAllocateParams, map[gethcommon.Address]uint64, error) {
+	targets, err := parseTargetAllocationsFile(config.targetFilePath)
+	if err != nil {
+		return nil, nil, eigenSdkUtils.WrapError("failed to parse target file", err)
+	}
+
+	err = validateTargetAllocations(targets)
+	if err != nil {
+		return nil, nil, eigenSdkUtils.WrapError("failed to validate target allocations", err)
+	}
+
+	allocations, err := computeTargetAllocations(ctx, elReader, config.operatorAddress, targets)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(allocations) == 0 {
+		return nil, nil, errors.New("allocations already match the target file, nothing to update")
+	}
+
+	strategies := getUniqueStrategies(targets)
+	allocatableMagnitudePerStrategy, err := parallelGetAllocatableMagnitudes(strategies, config.operatorAddress, elReader)
+	if err != nil {
+		return nil, nil, eigenSdkUtils.WrapError("failed to get allocatable magnitudes", err)
+	}
+	return allocations, allocatableMagnitudePerStrategy, nil
+}
+
 func validateDataFromCSV(allocations []allocation) error {
 	// check for duplicated (avs_address,operator_set_id,strategy_address)
 	tuples := make(map[string]struct{})
@@ -312,8 +569,13 @@ func parallelGetAllocatableMagnitudes(
 	operatorAddress gethcommon.Address,
 	elReader elChainReader,
 ) (map[gethcommon.Address]uint64, error) {
+	if batch, ok := elReader.(allocatableMagnitudesBatchReader); ok {
+		return batch.GetAllocatableMagnitudes(context.Background(), operatorAddress, strategies)
+	}
+
 	strategyAllocatableMagnitudes := make(map[gethcommon.Address]uint64, len(strategies))
 	var wg sync.WaitGroup
+	var mu sync.Mutex
 	errChan := make(chan error, len(strategies))
 
 	for _, s := range strategies {
@@ -325,7 +587,9 @@ func parallelGetAllocatableMagnitudes(
 				errChan <- err
 				return
 			}
+			mu.Lock()
 			strategyAllocatableMagnitudes[strategy] = magnitude
+			mu.Unlock()
 		}(s)
 	}
 
@@ -397,7 +661,7 @@ func convertAllocationsToMagnitudeAllocations(
 	magnitudeAllocationsPerOperatorSetMap := make(map[allocationmanager.OperatorSet][]uint64)
 	for _, a := range allocations {
 		totalMag := strategyTotalMagnitudes[a.StrategyAddress]
-		magnitudeToUpdate := calculateMagnitudeToUpdate(totalMag, a.Bips)
+		magnitudeToUpdate := CalculateMagnitudeToUpdate(totalMag, a.Bips)
 
 		opSet := allocationmanager.OperatorSet{Avs: a.AvsAddress, Id: a.OperatorSetId}
 		strategies, ok := strategiesPerOperatorSetMap[opSet]
@@ -427,7 +691,7 @@ func convertAllocationsToMagnitudeAllocations(
 	return magnitudeAllocations
 }
 
-func calculateMagnitudeToUpdate(totalMagnitude uint64, bipsToAllocate uint64) uint64 {
+func CalculateMagnitudeToUpdate(totalMagnitude uint64, bipsToAllocate uint64) uint64 {
 	bigMagnitude := big.NewInt(int64(totalMagnitude))
 	bigBipsToAllocate := big.NewInt(int64(bipsToAllocate))
 	bigBipsMultiplier := big.NewInt(10_000)
@@ -445,6 +709,8 @@ func readAndValidateUpdateFlags(cCtx *cli.Context, logger logging.Logger) (*upda
 	outputType := cCtx.String(flags.OutputTypeFlag.Name)
 	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
 	isSilent := cCtx.Bool(flags.SilentFlag.Name)
+	batchSize := cCtx.Int(flags.MulticallBatchSizeFlag.Name)
+	maxConcurrency := cCtx.Int(flags.MaxConcurrencyFlag.Name)
 
 	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
 	if common.IsEmptyString(operatorAddress) {
@@ -479,8 +745,21 @@ func readAndValidateUpdateFlags(cCtx *cli.Context, logger logging.Logger) (*upda
 	}
 
 	csvFilePath := cCtx.String(flags.CSVFileFlag.Name)
+	targetFilePath := cCtx.String(TargetFileFlag.Name)
+	if !common.IsEmptyString(csvFilePath) && !common.IsEmptyString(targetFilePath) {
+		return nil, errors.New("only one of --csv-file and --target-file can be set")
+	}
 	chainId := utils.NetworkNameToChainId(network)
 
+	var policy *allocationPolicy
+	policyFilePath := cCtx.String(PolicyFileFlag.Name)
+	if !common.IsEmptyString(policyFilePath) {
+		policy, err = readAllocationPolicy(policyFilePath)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to read allocation policy", err)
+		}
+	}
+
 	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
 	if delegationManagerAddress == "" {
 		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainId)
@@ -503,9 +782,15 @@ func readAndValidateUpdateFlags(cCtx *cli.Context, logger logging.Logger) (*upda
 		bipsToAllocate:           bipsToAllocate,
 		signerConfig:             signerConfig,
 		csvFilePath:              csvFilePath,
+		targetFilePath:           targetFilePath,
+		policy:                   policy,
+		policyFilePath:           policyFilePath,
+		overridePolicy:           cCtx.Bool(OverridePolicyFlag.Name),
 		operatorSetId:            operatorSetId,
 		chainID:                  chainId,
 		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
 		isSilent:                 isSilent,
+		batchSize:                batchSize,
+		maxConcurrency:           maxConcurrency,
 	}, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
 
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
 	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 
@@ -86,10 +87,27 @@ func (f *fakeElChainReader) GetSlashableShares(
 	return nil, errors.New("not implemented")
 }
 
+func (f *fakeElChainReader) GetAllocationInfo(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) ([]elcontracts.AllocationInfo, error) {
+	return []elcontracts.AllocationInfo{}, nil
+}
+
+func (f *fakeElChainReader) IsOperatorSlashable(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	return false, nil
+}
+
 func TestGenerateAllocationsParams(t *testing.T) {
 	avsAddress := testutils.GenerateRandomEthereumAddressString()
 	strategyAddress := testutils.GenerateRandomEthereumAddressString()
 	operatorAddress := testutils.GenerateRandomEthereumAddressString()
+	policyMaxBipsPerAvs := uint64(500)
 	tests := []struct {
 		name                string
 		config              *updateConfig
@@ -119,6 +137,44 @@ func TestGenerateAllocationsParams(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "single allocation rejected by the policy",
+			config: &updateConfig{
+				operatorAddress: gethcommon.HexToAddress("0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f"),
+				avsAddress:      gethcommon.HexToAddress(avsAddress),
+				strategyAddress: gethcommon.HexToAddress(strategyAddress),
+				bipsToAllocate:  1000,
+				operatorSetId:   1,
+				policy:          &allocationPolicy{MaxBipsPerAvs: &policyMaxBipsPerAvs},
+			},
+			expectError: true,
+		},
+		{
+			name: "single allocation with overridden policy",
+			config: &updateConfig{
+				operatorAddress: gethcommon.HexToAddress("0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f"),
+				avsAddress:      gethcommon.HexToAddress(avsAddress),
+				strategyAddress: gethcommon.HexToAddress(strategyAddress),
+				bipsToAllocate:  1000,
+				operatorSetId:   1,
+				policy:          &allocationPolicy{MaxBipsPerAvs: &policyMaxBipsPerAvs},
+				overridePolicy:  true,
+			},
+			expectError: false,
+			expectedAllocations: &BulkModifyAllocations{
+				Allocations: []allocationmanager.IAllocationManagerTypesAllocateParams{
+					{
+						Strategies: []gethcommon.Address{gethcommon.HexToAddress(strategyAddress)},
+						OperatorSet: allocationmanager.OperatorSet{
+							Avs: gethcommon.HexToAddress(avsAddress),
+							Id:  1,
+						},
+						NewMagnitudes: []uint64{1e17},
+					},
+				},
+				PolicyViolations: []string{"violation"},
+			},
+		},
 		{
 			name: "csv file allocations1.csv",
 			config: &updateConfig{
@@ -221,11 +277,61 @@ func TestGenerateAllocationsParams(t *testing.T) {
 				assert.NoError(t, err)
 				assert.ElementsMatch(t, tt.expectedAllocations.Allocations, allocations.Allocations)
 				assert.Equal(t, tt.expectedAllocations.AllocatableMagnitudes, allocations.AllocatableMagnitudes)
+				assert.Len(t, allocations.PolicyViolations, len(tt.expectedAllocations.PolicyViolations))
 			}
 		})
 	}
 }
 
+func TestSkipUnchangedAllocations(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	reader := &fakeAllocationDiffReader{
+		maxMagnitudes: map[gethcommon.Address]uint64{strategyAddress: 1000},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+			strategyAddress: {
+				{AvsAddress: avsAddress, OperatorSetId: 1, CurrentMagnitude: big.NewInt(500), PendingDiff: big.NewInt(0)},
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    2,
+					CurrentMagnitude: big.NewInt(300),
+					PendingDiff:      big.NewInt(-100),
+					EffectBlock:      100,
+				},
+				{
+					AvsAddress:       avsAddress,
+					OperatorSetId:    3,
+					CurrentMagnitude: big.NewInt(100),
+					PendingDiff:      big.NewInt(100),
+					EffectBlock:      100,
+				},
+			},
+		},
+	}
+	row := func(operatorSetId uint32, bips uint64) allocation {
+		return allocation{
+			AvsAddress:      avsAddress,
+			OperatorSetId:   operatorSetId,
+			StrategyAddress: strategyAddress,
+			Bips:            bips,
+		}
+	}
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	// Unchanged rows, rows matching the pending modification and rows with a pending modification are skipped
+	allocations, err := skipUnchangedAllocations(
+		context.Background(),
+		reader,
+		operatorAddress,
+		[]allocation{row(1, 5000), row(2, 2000), row(3, 3000), row(4, 1000), row(5, 0)},
+		reader.maxMagnitudes,
+		logger,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []allocation{row(4, 1000)}, allocations)
+}
+
 func TestCalculateMagnitudeToUpdate(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -279,7 +385,7 @@ func TestCalculateMagnitudeToUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateMagnitudeToUpdate(tt.totalMagnitude, tt.bipsToAllocate)
+			result := CalculateMagnitudeToUpdate(tt.totalMagnitude, tt.bipsToAllocate)
 			assert.Equal(t, tt.expectedMagnitude, result)
 		})
 	}
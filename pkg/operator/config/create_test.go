@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	prompterMock "github.com/Layr-Labs/eigenlayer-cli/pkg/utils/mocks"
@@ -12,6 +13,12 @@ import (
 
 func TestCreateCmd_WithYesFlag(t *testing.T) {
 	// Arrange
+	// The command writes its files to the working directory, so the test runs in a temporary one
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
 	controller := gomock.NewController(t)
 	prompter := prompterMock.NewMockPrompter(controller)
 
@@ -33,7 +40,7 @@ func TestCreateCmd_WithYesFlag(t *testing.T) {
 	cCtx := cli.NewContext(app, nil, &cli.Context{Context: context.Background()})
 
 	// Act
-	err := cmd.Run(cCtx, args...)
+	err = cmd.Run(cCtx, args...)
 
 	// Assert
 	assert.NoError(t, err)
@@ -1,6 +1,7 @@
 package operator
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strings"
@@ -8,6 +9,8 @@ import (
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/command"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/user/appointee"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
@@ -15,6 +18,7 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
@@ -37,6 +41,11 @@ func NewDeregisterOperatorSetsCmd(p utils.Prompter) *cli.Command {
 		This command doesn't automatically deallocate your slashable stake from that operator set so you will have to use the 'operator allocations update' command to deallocate your stake from the operator set.
 
 		To find what operator set you are part of, use the 'eigenlayer operator allocations show' command.
+
+		Before deregistering, a safety report is printed with the allocations which stay slashable until the
+		deallocation delay has passed, operator directed rewards of the operator sets which are not yet distributed
+		and appointee permissions on the AVS contracts set in the last 90 days. Broadcasting asks for confirmation
+		unless --yes is set.
 		`,
 		getDeregistrationFlags(),
 	)
@@ -59,10 +68,45 @@ func (d DeregisterOperatorSetsCmd) Execute(cCtx *cli.Context) error {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress:    config.delegationManagerAddress,
+		RewardsCoordinatorAddress:   config.rewardsCoordinatorAddress,
+		PermissionControllerAddress: config.permissionControllerAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return err
+	}
+
+	// The report is not printed when only the call data is requested so it can be piped
+	if config.broadcast || (config.outputType != utils.CallDataOutputType && !config.isSilent) {
+		report, err := getDeregistrationReport(ctx, ethClient, contractBindings, config, logger)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to build deregistration report", err)
+		}
+		report.PrintPretty()
+	}
+
 	if config.broadcast {
 		if config.signerConfig == nil {
 			return fmt.Errorf("signer config is required to broadcast the transaction")
 		}
+		if !config.skipConfirmation {
+			confirm, err := d.prompter.Confirm(
+				fmt.Sprintf(
+					"Deregister operator %s from operator sets %v of AVS %s?",
+					config.operatorAddress.Hex(),
+					config.operatorSetIds,
+					config.avsAddress.Hex(),
+				),
+			)
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				logger.Info("Operation cancelled")
+				return nil
+			}
+		}
 		logger.Info("Signing and broadcasting deregistration transaction")
 		eLWriter, err := common.GetELWriter(
 			config.callerAddress,
@@ -92,12 +136,6 @@ func (d DeregisterOperatorSetsCmd) Execute(cCtx *cli.Context) error {
 		common.PrintTransactionInfo(receipt.TxHash.String(), config.chainID)
 	} else {
 		noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
-		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
-			DelegationManagerAddress: config.delegationManagerAddress,
-		}, ethClient, nil, logger, nil)
-		if err != nil {
-			return err
-		}
 		// If operator is a smart contract, we can't estimate gas using geth
 		// since balance of contract can be 0, as it can be called by an EOA
 		// to claim. So we hardcode the gas limit to 150_000 so that we can
@@ -196,6 +234,22 @@ func readAndValidateDeregisterConfig(cCtx *cli.Context, logger logging.Logger) (
 		}
 	}
 
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	permissionControllerAddress := cCtx.String(appointee.PermissionControllerAddressFlag.Name)
+	if common.IsEmptyString(permissionControllerAddress) {
+		permissionControllerAddress, err = common.GetPermissionControllerAddress(chainId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	operatorSetIdsString := cCtx.Uint64Slice(flags.OperatorSetIdsFlag.Name)
 	operatorSetIds := make([]uint32, len(operatorSetIdsString))
 	for i, id := range operatorSetIdsString {
@@ -203,20 +257,23 @@ func readAndValidateDeregisterConfig(cCtx *cli.Context, logger logging.Logger) (
 	}
 
 	config := &DeregisterConfig{
-		avsAddress:               avsAddress,
-		operatorSetIds:           operatorSetIds,
-		operatorAddress:          operatorAddress,
-		callerAddress:            callerAddress,
-		network:                  network,
-		environment:              environment,
-		broadcast:                broadcast,
-		rpcUrl:                   rpcUrl,
-		chainID:                  chainId,
-		signerConfig:             signerConfig,
-		output:                   output,
-		outputType:               outputType,
-		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
-		isSilent:                 isSilent,
+		avsAddress:                  avsAddress,
+		operatorSetIds:              operatorSetIds,
+		operatorAddress:             operatorAddress,
+		callerAddress:               callerAddress,
+		network:                     network,
+		environment:                 environment,
+		broadcast:                   broadcast,
+		rpcUrl:                      rpcUrl,
+		chainID:                     chainId,
+		signerConfig:                signerConfig,
+		output:                      output,
+		outputType:                  outputType,
+		delegationManagerAddress:    gethcommon.HexToAddress(delegationManagerAddress),
+		isSilent:                    isSilent,
+		skipConfirmation:            cCtx.Bool(flags.YesFlag.Name),
+		rewardsCoordinatorAddress:   gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		permissionControllerAddress: gethcommon.HexToAddress(permissionControllerAddress),
 	}
 
 	return config, nil
@@ -233,5 +290,76 @@ func getDeregistrationFlags() []cli.Flag {
 		&flags.OperatorSetIdsFlag,
 		&flags.DelegationManagerAddressFlag,
 		&flags.SilentFlag,
+		&flags.YesFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&appointee.PermissionControllerAddressFlag,
+	}
+}
+
+func getDeregistrationReport(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	contractBindings *elcontracts.ContractBindings,
+	config *DeregisterConfig,
+	logger logging.Logger,
+) (*DeregistrationReport, error) {
+	currentBlock, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get latest block number", err)
+	}
+
+	currRewardsCalculationEndTimestamp, err := contractBindings.RewardsCoordinator.CurrRewardsCalculationEndTimestamp(
+		&bind.CallOpts{Context: ctx},
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get current rewards calculation end timestamp", err)
+	}
+
+	// Operator directed rewards are retroactive, so the submissions which are not yet distributed were all
+	// created after the end of the current rewards calculation. The report is still useful without the
+	// events, so we only warn if they can't be fetched.
+	rewardSubmissions := make([]*operatorSetRewardsSubmission, 0)
+	submissionsFromBlock, err := common.FindBlockByTimestamp(
+		ctx,
+		ethClient,
+		currentBlock,
+		uint64(currRewardsCalculationEndTimestamp),
+	)
+	if err == nil {
+		rewardSubmissions, err = getOperatorSetRewardSubmissions(
+			ctx,
+			contractBindings.RewardsCoordinator,
+			config.avsAddress,
+			submissionsFromBlock,
+			currentBlock,
+		)
 	}
+	if err != nil {
+		logger.Warnf("Failed to fetch rewards submissions, pending rewards will not be shown: %s", err)
+	}
+
+	permissionsFromBlock := common.LookbackStartBlock(currentBlock, appointeeLogsLookbackBlocks)
+	logger.Debugf("Reading appointee events from block %d to %d", permissionsFromBlock, currentBlock)
+	permissions, err := getAppointeePermissions(
+		ctx,
+		contractBindings.PermissionController,
+		config.operatorAddress,
+		permissionsFromBlock,
+		currentBlock,
+	)
+	if err != nil {
+		logger.Warnf("Failed to fetch appointee events, appointee permissions will not be shown: %s", err)
+	}
+
+	return buildDeregistrationReport(
+		ctx,
+		contractBindings.AllocationManager,
+		config.operatorAddress,
+		config.avsAddress,
+		config.operatorSetIds,
+		currentBlock,
+		rewardSubmissions,
+		currRewardsCalculationEndTimestamp,
+		permissions,
+	)
 }
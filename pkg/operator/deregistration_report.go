@@ -0,0 +1,382 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	permissioncontroller "github.com/Layr-Labs/eigensdk-go/contracts/bindings/PermissionController"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// appointeeLogsLookbackBlocks is the number of blocks whose appointee events are replayed, about 90 days of
+// mainnet blocks. Permissions can't be enumerated from the PermissionController state.
+const appointeeLogsLookbackBlocks = 648_000
+
+type operatorSetRewardsSubmission = rewardscoordinator.ContractRewardsCoordinatorOperatorDirectedOperatorSetRewardsSubmissionCreated
+
+type deregistrationReader interface {
+	GetRegisteredSets(opts *bind.CallOpts, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	GetStrategiesInOperatorSet(
+		opts *bind.CallOpts,
+		operatorSet allocationmanager.OperatorSet,
+	) ([]gethcommon.Address, error)
+	GetAllocation(
+		opts *bind.CallOpts,
+		operator gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+		strategy gethcommon.Address,
+	) (allocationmanager.IAllocationManagerTypesAllocation, error)
+	DEALLOCATIONDELAY(opts *bind.CallOpts) (uint32, error)
+	GetAVSRegistrar(opts *bind.CallOpts, avs gethcommon.Address) (gethcommon.Address, error)
+}
+
+// DeregistrationReport lists what the operator keeps at stake, and what it gives up,
+// when deregistering from operator sets of an AVS.
+type DeregistrationReport struct {
+	OperatorAddress     gethcommon.Address
+	AvsAddress          gethcommon.Address
+	SlashableUntilBlock uint64
+	OperatorSets        []DeregisteredOperatorSet
+	PendingRewards      []PendingOperatorSetReward
+	AvsPermissions      []AppointeePermission
+	Warnings            []string
+}
+
+type DeregisteredOperatorSet struct {
+	OperatorSetId uint32
+	Allocations   []OperatorSetAllocation
+}
+
+type OperatorSetAllocation struct {
+	StrategyAddress  gethcommon.Address
+	CurrentMagnitude uint64
+	PendingDiff      *big.Int
+	EffectBlock      uint32
+}
+
+// PendingOperatorSetReward is an operator directed reward for the operator set which is not yet
+// included in a distribution root
+type PendingOperatorSetReward struct {
+	OperatorSetId  uint32
+	Token          gethcommon.Address
+	Amount         *big.Int
+	EndTimestamp   uint32
+	SubmissionHash gethcommon.Hash
+}
+
+type AppointeePermission struct {
+	Appointee gethcommon.Address
+	Target    gethcommon.Address
+	Selector  [4]byte
+}
+
+// buildDeregistrationReport builds the report from the current state of the AllocationManager, the operator
+// directed rewards submissions of the AVS and the current appointee permissions of the operator.
+func buildDeregistrationReport(
+	ctx context.Context,
+	reader deregistrationReader,
+	operatorAddress gethcommon.Address,
+	avsAddress gethcommon.Address,
+	operatorSetIds []uint32,
+	currentBlock uint64,
+	rewardSubmissions []*operatorSetRewardsSubmission,
+	currRewardsCalculationEndTimestamp uint32,
+	permissions []AppointeePermission,
+) (*DeregistrationReport, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	report := &DeregistrationReport{
+		OperatorAddress: operatorAddress,
+		AvsAddress:      avsAddress,
+		OperatorSets:    make([]DeregisteredOperatorSet, 0, len(operatorSetIds)),
+	}
+
+	deallocationDelay, err := reader.DEALLOCATIONDELAY(opts)
+	if err != nil {
+		return nil, err
+	}
+	report.SlashableUntilBlock = currentBlock + uint64(deallocationDelay)
+
+	registeredSets, err := reader.GetRegisteredSets(opts, operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	deregistering := make(map[uint32]bool)
+	for _, id := range operatorSetIds {
+		deregistering[id] = true
+	}
+	registered := make(map[uint32]bool)
+	remainingSetsWithAvs := 0
+	for _, set := range registeredSets {
+		if set.Avs != avsAddress {
+			continue
+		}
+		registered[set.Id] = true
+		if !deregistering[set.Id] {
+			remainingSetsWithAvs++
+		}
+	}
+
+	for _, id := range operatorSetIds {
+		if !registered[id] {
+			report.Warnings = append(
+				report.Warnings,
+				fmt.Sprintf("operator is not registered for operator set %d, the transaction will revert", id),
+			)
+			continue
+		}
+
+		operatorSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: id}
+		strategies, err := reader.GetStrategiesInOperatorSet(opts, operatorSet)
+		if err != nil {
+			return nil, err
+		}
+		deregisteredSet := DeregisteredOperatorSet{OperatorSetId: id}
+		for _, strategy := range strategies {
+			allocation, err := reader.GetAllocation(opts, operatorAddress, operatorSet, strategy)
+			if err != nil {
+				return nil, err
+			}
+			hasPendingDiff := allocation.PendingDiff != nil && allocation.PendingDiff.Sign() != 0
+			if allocation.CurrentMagnitude == 0 && !hasPendingDiff {
+				continue
+			}
+			deregisteredSet.Allocations = append(deregisteredSet.Allocations, OperatorSetAllocation{
+				StrategyAddress:  strategy,
+				CurrentMagnitude: allocation.CurrentMagnitude,
+				PendingDiff:      allocation.PendingDiff,
+				EffectBlock:      allocation.EffectBlock,
+			})
+		}
+		report.OperatorSets = append(report.OperatorSets, deregisteredSet)
+	}
+
+	for _, submission := range rewardSubmissions {
+		if submission.OperatorSet.Avs != avsAddress || !deregistering[submission.OperatorSet.Id] {
+			continue
+		}
+		rewardsSubmission := submission.OperatorDirectedRewardsSubmission
+		endTimestamp := rewardsSubmission.StartTimestamp + rewardsSubmission.Duration
+		if endTimestamp <= currRewardsCalculationEndTimestamp {
+			continue
+		}
+		for _, operatorReward := range rewardsSubmission.OperatorRewards {
+			if operatorReward.Operator != operatorAddress {
+				continue
+			}
+			report.PendingRewards = append(report.PendingRewards, PendingOperatorSetReward{
+				OperatorSetId:  submission.OperatorSet.Id,
+				Token:          rewardsSubmission.Token,
+				Amount:         operatorReward.Amount,
+				EndTimestamp:   endTimestamp,
+				SubmissionHash: submission.OperatorDirectedRewardsSubmissionHash,
+			})
+		}
+	}
+
+	// Permissions on the AVS contracts are only useful while the operator is registered with the AVS
+	if remainingSetsWithAvs == 0 {
+		registrarAddress, err := reader.GetAVSRegistrar(opts, avsAddress)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range permissions {
+			if permission.Target == avsAddress || permission.Target == registrarAddress {
+				report.AvsPermissions = append(report.AvsPermissions, permission)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// getOperatorSetRewardSubmissions returns the operator directed rewards submissions for operator sets of the AVS
+// from fromBlock to toBlock
+func getOperatorSetRewardSubmissions(
+	ctx context.Context,
+	rewardsCoordinator *rewardscoordinator.ContractRewardsCoordinator,
+	avsAddress gethcommon.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]*operatorSetRewardsSubmission, error) {
+	submissions := make([]*operatorSetRewardsSubmission, 0)
+	err := common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		iterator, err := rewardsCoordinator.FilterOperatorDirectedOperatorSetRewardsSubmissionCreated(opts, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer iterator.Close()
+		for iterator.Next() {
+			if iterator.Event.OperatorSet.Avs == avsAddress {
+				submissions = append(submissions, iterator.Event)
+			}
+		}
+		return iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// getAppointeePermissions replays the AppointeeSet and AppointeeRemoved events of the PermissionController from
+// fromBlock to toBlock to find the current appointee permissions of the operator
+func getAppointeePermissions(
+	ctx context.Context,
+	permissionController *permissioncontroller.ContractPermissionController,
+	operatorAddress gethcommon.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]AppointeePermission, error) {
+	type permissionUpdate struct {
+		permission  AppointeePermission
+		set         bool
+		blockNumber uint64
+		index       uint
+	}
+	accounts := []gethcommon.Address{operatorAddress}
+	updates := make([]permissionUpdate, 0)
+
+	err := common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		setIterator, err := permissionController.FilterAppointeeSet(opts, accounts, nil)
+		if err != nil {
+			return err
+		}
+		defer setIterator.Close()
+		for setIterator.Next() {
+			updates = append(updates, permissionUpdate{
+				permission: AppointeePermission{
+					Appointee: setIterator.Event.Appointee,
+					Target:    setIterator.Event.Target,
+					Selector:  setIterator.Event.Selector,
+				},
+				set:         true,
+				blockNumber: setIterator.Event.Raw.BlockNumber,
+				index:       setIterator.Event.Raw.Index,
+			})
+		}
+		if setIterator.Error() != nil {
+			return setIterator.Error()
+		}
+
+		removedIterator, err := permissionController.FilterAppointeeRemoved(opts, accounts, nil)
+		if err != nil {
+			return err
+		}
+		defer removedIterator.Close()
+		for removedIterator.Next() {
+			updates = append(updates, permissionUpdate{
+				permission: AppointeePermission{
+					Appointee: removedIterator.Event.Appointee,
+					Target:    removedIterator.Event.Target,
+					Selector:  removedIterator.Event.Selector,
+				},
+				blockNumber: removedIterator.Event.Raw.BlockNumber,
+				index:       removedIterator.Event.Raw.Index,
+			})
+		}
+		return removedIterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].blockNumber != updates[j].blockNumber {
+			return updates[i].blockNumber < updates[j].blockNumber
+		}
+		return updates[i].index < updates[j].index
+	})
+	current := make(map[AppointeePermission]bool)
+	permissions := make([]AppointeePermission, 0)
+	for _, update := range updates {
+		current[update.permission] = update.set
+	}
+	for _, update := range updates {
+		if current[update.permission] {
+			permissions = append(permissions, update.permission)
+			delete(current, update.permission)
+		}
+	}
+	return permissions, nil
+}
+
+func (r *DeregistrationReport) PrintPretty() {
+	fmt.Println()
+	fmt.Println("Deregistration safety report")
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Operator: %s\n", r.OperatorAddress.Hex())
+	fmt.Printf("AVS:      %s\n", r.AvsAddress.Hex())
+	fmt.Printf("The operator remains slashable by these operator sets until block ~%d\n", r.SlashableUntilBlock)
+
+	for _, operatorSet := range r.OperatorSets {
+		fmt.Println()
+		fmt.Printf("Operator set %d\n", operatorSet.OperatorSetId)
+		if len(operatorSet.Allocations) == 0 {
+			fmt.Println("  No allocations")
+			continue
+		}
+		fmt.Println("  Allocations which stay slashable until the deallocation delay has passed:")
+		for _, allocation := range operatorSet.Allocations {
+			pending := ""
+			if allocation.PendingDiff != nil && allocation.PendingDiff.Sign() != 0 {
+				pending = fmt.Sprintf(
+					" (pending change %s at block %d)",
+					allocation.PendingDiff,
+					allocation.EffectBlock,
+				)
+			}
+			fmt.Printf(
+				"  - strategy %s: magnitude %d%s\n",
+				allocation.StrategyAddress.Hex(),
+				allocation.CurrentMagnitude,
+				pending,
+			)
+		}
+	}
+
+	if len(r.PendingRewards) > 0 {
+		fmt.Println()
+		fmt.Println("Operator directed rewards not yet distributed for these operator sets:")
+		for _, reward := range r.PendingRewards {
+			fmt.Printf(
+				"  - operator set %d: %s of token %s (period ends at %d)\n",
+				reward.OperatorSetId,
+				reward.Amount,
+				reward.Token.Hex(),
+				reward.EndTimestamp,
+			)
+		}
+	}
+
+	if len(r.AvsPermissions) > 0 {
+		fmt.Println()
+		fmt.Println("Appointee permissions on AVS contracts which are no longer needed after deregistration:")
+		for _, permission := range r.AvsPermissions {
+			fmt.Printf(
+				"  - appointee %s: target %s selector 0x%x\n",
+				permission.Appointee.Hex(),
+				permission.Target.Hex(),
+				permission.Selector,
+			)
+		}
+		fmt.Println("  Use 'eigenlayer user appointee remove' to remove them")
+	}
+
+	if len(r.Warnings) > 0 {
+		fmt.Println()
+		for _, warning := range r.Warnings {
+			fmt.Printf("%s %s\n", utils.EmojiWarning, warning)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 80))
+}
@@ -0,0 +1,181 @@
+package operator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type operatorDirectedRewardsSubmission = rewardscoordinator.IRewardsCoordinatorTypesOperatorDirectedRewardsSubmission
+
+type strategyAllocations = map[gethcommon.Address]allocationmanager.IAllocationManagerTypesAllocation
+
+type fakeDeregistrationReader struct {
+	registeredSets []allocationmanager.OperatorSet
+	strategies     map[allocationmanager.OperatorSet][]gethcommon.Address
+	allocations    map[allocationmanager.OperatorSet]strategyAllocations
+	registrar      gethcommon.Address
+}
+
+func (f *fakeDeregistrationReader) GetRegisteredSets(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func (f *fakeDeregistrationReader) GetStrategiesInOperatorSet(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.strategies[operatorSet], nil
+}
+
+func (f *fakeDeregistrationReader) GetAllocation(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategy gethcommon.Address,
+) (allocationmanager.IAllocationManagerTypesAllocation, error) {
+	allocation, ok := f.allocations[operatorSet][strategy]
+	if !ok {
+		return allocationmanager.IAllocationManagerTypesAllocation{PendingDiff: big.NewInt(0)}, nil
+	}
+	return allocation, nil
+}
+
+func (f *fakeDeregistrationReader) DEALLOCATIONDELAY(opts *bind.CallOpts) (uint32, error) {
+	return 100, nil
+}
+
+func (f *fakeDeregistrationReader) GetAVSRegistrar(
+	opts *bind.CallOpts,
+	avs gethcommon.Address,
+) (gethcommon.Address, error) {
+	return f.registrar, nil
+}
+
+func TestBuildDeregistrationReport(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	registrarAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	unallocatedStrategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	tokenAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	appointeeAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	operatorSet1 := allocationmanager.OperatorSet{Avs: avsAddress, Id: 1}
+	operatorSet2 := allocationmanager.OperatorSet{Avs: avsAddress, Id: 2}
+
+	reader := &fakeDeregistrationReader{
+		registeredSets: []allocationmanager.OperatorSet{operatorSet1, operatorSet2},
+		strategies: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			operatorSet1: {strategyAddress, unallocatedStrategyAddress},
+		},
+		allocations: map[allocationmanager.OperatorSet]strategyAllocations{
+			operatorSet1: {strategyAddress: {CurrentMagnitude: 500, PendingDiff: big.NewInt(0)}},
+		},
+		registrar: registrarAddress,
+	}
+	newSubmission := func(id uint32, startTimestamp uint32) *operatorSetRewardsSubmission {
+		return &operatorSetRewardsSubmission{
+			OperatorSet: rewardscoordinator.OperatorSet{Avs: avsAddress, Id: id},
+			OperatorDirectedRewardsSubmission: operatorDirectedRewardsSubmission{
+				Token: tokenAddress,
+				OperatorRewards: []rewardscoordinator.IRewardsCoordinatorTypesOperatorReward{
+					{Operator: operatorAddress, Amount: big.NewInt(42)},
+				},
+				StartTimestamp: startTimestamp,
+				Duration:       86400,
+			},
+		}
+	}
+	rewardSubmissions := []*operatorSetRewardsSubmission{
+		// Already distributed
+		newSubmission(1, 0),
+		// Not yet distributed
+		newSubmission(1, 864000),
+		// Operator set which is not deregistered
+		newSubmission(2, 864000),
+	}
+	avsPermission := AppointeePermission{Appointee: appointeeAddress, Target: registrarAddress, Selector: [4]byte{1}}
+	permissions := []AppointeePermission{
+		avsPermission,
+		{Appointee: appointeeAddress, Target: gethcommon.HexToAddress("0x1"), Selector: [4]byte{2}},
+	}
+
+	tests := []struct {
+		name                   string
+		operatorSetIds         []uint32
+		expectedSets           []DeregisteredOperatorSet
+		expectedPendingRewards int
+		expectedPermissions    []AppointeePermission
+		expectedWarnings       int
+	}{
+		{
+			name:           "deregister from one of two operator sets",
+			operatorSetIds: []uint32{1},
+			expectedSets: []DeregisteredOperatorSet{
+				{
+					OperatorSetId: 1,
+					Allocations: []OperatorSetAllocation{
+						{StrategyAddress: strategyAddress, CurrentMagnitude: 500, PendingDiff: big.NewInt(0)},
+					},
+				},
+			},
+			expectedPendingRewards: 1,
+		},
+		{
+			name:           "deregister from all operator sets of the AVS",
+			operatorSetIds: []uint32{1, 2},
+			expectedSets: []DeregisteredOperatorSet{
+				{
+					OperatorSetId: 1,
+					Allocations: []OperatorSetAllocation{
+						{StrategyAddress: strategyAddress, CurrentMagnitude: 500, PendingDiff: big.NewInt(0)},
+					},
+				},
+				{OperatorSetId: 2},
+			},
+			expectedPendingRewards: 2,
+			expectedPermissions:    []AppointeePermission{avsPermission},
+		},
+		{
+			name:             "operator set the operator is not registered for",
+			operatorSetIds:   []uint32{3},
+			expectedSets:     []DeregisteredOperatorSet{},
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := buildDeregistrationReport(
+				context.Background(),
+				reader,
+				operatorAddress,
+				avsAddress,
+				tt.operatorSetIds,
+				1000,
+				rewardSubmissions,
+				86400,
+				permissions,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1100), report.SlashableUntilBlock)
+			assert.Equal(t, tt.expectedSets, report.OperatorSets)
+			assert.Len(t, report.PendingRewards, tt.expectedPendingRewards)
+			assert.Equal(t, tt.expectedPermissions, report.AvsPermissions)
+			assert.Len(t, report.Warnings, tt.expectedWarnings)
+		})
+	}
+}
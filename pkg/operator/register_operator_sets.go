@@ -6,7 +6,7 @@ import (
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/command"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
-	"github.com/Layr-Labs/eigenlayer-cli/pkg/keys"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/registrar"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
@@ -14,6 +14,7 @@ import (
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 
@@ -31,7 +32,18 @@ func NewRegisterOperatorSetsCmd(p utils.Prompter) *cli.Command {
 		"register-operator-sets",
 		"register operator from specified operator sets",
 		"register-operator-sets [flags]",
-		"",
+		`
+Registers the operator for operator sets of an AVS. The registration data passed to the AVS registrar
+is built according to --registrar-type:
+
+- bls: standard BLS registrar of the middleware contracts. Signs the pubkey registration message hash
+  with the BLS key from --bls-keystore-path or --bls-private-key.
+- raw: passes --registration-data to the registrar as is. Empty data is rejected unless
+  --allow-empty-registration-data is set.
+
+The registrar defaults to the one set by the AVS in the AllocationManager and can be overridden with
+--registry-coordinator-address. The registration is simulated before it is sent unless --skip-simulation is set.
+		`,
 		getRegistrationFlags(),
 	)
 
@@ -42,7 +54,7 @@ func (r RegisterOperatorSetCmd) Execute(cCtx *cli.Context) error {
 	ctx := cCtx.Context
 	logger := common.GetLogger(cCtx)
 
-	config, err := readAndValidateRegisterOperatorSetsConfig(cCtx, logger)
+	config, err := readAndValidateRegisterOperatorSetsConfig(cCtx, logger, r.prompter)
 	if err != nil {
 		return eigenSdkUtils.WrapError(err, "failed to read and validate register config")
 	}
@@ -54,68 +66,104 @@ func (r RegisterOperatorSetCmd) Execute(cCtx *cli.Context) error {
 		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 
+	_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
+		DelegationManagerAddress: config.delegationManagerAddress,
+	}, ethClient, nil, logger, nil)
+	if err != nil {
+		return err
+	}
+
+	// The registry coordinator is the registrar of AVSs using the standard middleware contracts.
+	// If it's not provided, we use the registrar the AVS has set in the AllocationManager.
+	registrarAddress := config.registryCoordinatorAddress
+	if registrarAddress == utils.ZeroAddress {
+		registrarAddress, err = contractBindings.AllocationManager.GetAVSRegistrar(
+			&bind.CallOpts{Context: ctx},
+			config.avsAddress,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get AVS registrar", err)
+		}
+		logger.Debugf("Using AVS registrar %s", registrarAddress.Hex())
+	}
+
+	registrationData, err := config.registrationDataBuilder.Build(ctx, ethClient, registrar.RegistrationRequest{
+		OperatorAddress:  config.operatorAddress,
+		AvsAddress:       config.avsAddress,
+		RegistrarAddress: registrarAddress,
+		OperatorSetIds:   config.operatorSetIds,
+	})
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to build registration data", err)
+	}
+	registerParams := allocationmanager.IAllocationManagerTypesRegisterParams{
+		Avs:            config.avsAddress,
+		OperatorSetIds: config.operatorSetIds,
+		Data:           registrationData,
+	}
+
+	if !config.skipSimulation {
+		allocationManagerAbi, err := allocationmanager.ContractAllocationManagerMetaData.GetAbi()
+		if err != nil {
+			return err
+		}
+		callData, err := allocationManagerAbi.Pack("registerForOperatorSets", config.operatorAddress, registerParams)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to pack registration call data", err)
+		}
+		err = registrar.Simulate(
+			ctx,
+			ethClient,
+			config.callerAddress,
+			contractBindings.AllocationManagerAddr,
+			callData,
+		)
+		if err != nil {
+			return err
+		}
+		logger.Info("Registration simulated successfully")
+	}
+
+	noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
+	// If caller is a smart contract, we can't estimate gas using geth
+	// since balance of contract can be 0, as it can be called by an EOA
+	// to claim. So we hardcode the gas limit to 150_000 so that we can
+	// create unsigned tx without gas limit estimation from contract bindings
+	if !config.broadcast && common.IsSmartContractAddress(config.callerAddress, ethClient) {
+		// address is a smart contract
+		noSendTxOpts.GasLimit = 150_000
+	}
+	unsignedTx, err := contractBindings.AllocationManager.RegisterForOperatorSets(
+		noSendTxOpts,
+		config.operatorAddress,
+		registerParams,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create unsigned transaction", err)
+	}
+
 	if config.broadcast {
 		if config.signerConfig == nil {
 			return fmt.Errorf("signer config is required to broadcast the transaction")
 		}
 		logger.Info("Signing and broadcasting registration transaction")
-		eLWriter, err := common.GetELWriter(
+		txMgr, err := common.GetTxManager(
 			config.callerAddress,
 			config.signerConfig,
 			ethClient,
-			elcontracts.Config{
-				DelegationManagerAddress: config.delegationManagerAddress,
-			},
 			r.prompter,
 			config.chainID,
 			logger,
 		)
-
 		if err != nil {
-			return eigenSdkUtils.WrapError("failed to get EL writer", err)
+			return eigenSdkUtils.WrapError("failed to get tx manager", err)
 		}
-		receipt, err := eLWriter.RegisterForOperatorSets(
-			ctx,
-			config.registryCoordinatorAddress,
-			elcontracts.RegistrationRequest{
-				OperatorAddress: config.operatorAddress,
-				AVSAddress:      config.avsAddress,
-				OperatorSetIds:  config.operatorSetIds,
-				BlsKeyPair:      config.blsKeyPair,
-				WaitForReceipt:  true,
-			})
+		receipt, err := txMgr.Send(ctx, unsignedTx, true)
 		if err != nil {
 			return eigenSdkUtils.WrapError("failed to register for operator sets", err)
 		}
 		common.PrintTransactionInfo(receipt.TxHash.String(), config.chainID)
 	} else {
-		noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
-		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
-			DelegationManagerAddress: config.delegationManagerAddress,
-		}, ethClient, nil, logger, nil)
-		if err != nil {
-			return err
-		}
-		// If caller is a smart contract, we can't estimate gas using geth
-		// since balance of contract can be 0, as it can be called by an EOA
-		// to claim. So we hardcode the gas limit to 150_000 so that we can
-		// create unsigned tx without gas limit estimation from contract bindings
-		if common.IsSmartContractAddress(config.callerAddress, ethClient) {
-			// address is a smart contract
-			noSendTxOpts.GasLimit = 150_000
-		}
-		unsignedTx, err := contractBindings.AllocationManager.RegisterForOperatorSets(
-			noSendTxOpts,
-			config.operatorAddress,
-			allocationmanager.IAllocationManagerTypesRegisterParams{
-				Avs:            config.avsAddress,
-				OperatorSetIds: config.operatorSetIds,
-			},
-		)
-		if err != nil {
-			return eigenSdkUtils.WrapError("failed to create unsigned transaction", err)
-		}
-
 		if config.outputType == utils.CallDataOutputType {
 			calldataHex := gethcommon.Bytes2Hex(unsignedTx.Data())
 			if !common.IsEmptyString(config.output) {
@@ -146,7 +194,11 @@ func (r RegisterOperatorSetCmd) Execute(cCtx *cli.Context) error {
 	return nil
 }
 
-func readAndValidateRegisterOperatorSetsConfig(cCtx *cli.Context, logger logging.Logger) (*RegisterConfig, error) {
+func readAndValidateRegisterOperatorSetsConfig(
+	cCtx *cli.Context,
+	logger logging.Logger,
+	p utils.Prompter,
+) (*RegisterConfig, error) {
 	network := cCtx.String(flags.NetworkFlag.Name)
 	environment := cCtx.String(flags.EnvironmentFlag.Name)
 	logger.Debugf("Using network %s and environment: %s", network, environment)
@@ -186,10 +238,6 @@ func readAndValidateRegisterOperatorSetsConfig(cCtx *cli.Context, logger logging
 	}
 
 	registryCoordinatorAddress := cCtx.String(flags.RegistryCoordinatorAddressFlag.Name)
-	if common.IsEmptyString(registryCoordinatorAddress) {
-		logger.Error("--registry-coordinator-address flag must be set")
-		return nil, fmt.Errorf("Empty registry coordinator address provided")
-	}
 
 	operatorSetIdsString := cCtx.Uint64Slice(flags.OperatorSetIdsFlag.Name)
 	operatorSetIds := make([]uint32, len(operatorSetIdsString))
@@ -197,13 +245,7 @@ func readAndValidateRegisterOperatorSetsConfig(cCtx *cli.Context, logger logging
 		operatorSetIds[i] = uint32(id)
 	}
 
-	blsPrivateKey := cCtx.String(flags.BlsPrivateKeyFlag.Name)
-	if common.IsEmptyString(blsPrivateKey) {
-		logger.Error("--bls-private-key flag must be set")
-		return nil, fmt.Errorf("Empty BLS private key provided")
-	}
-
-	blsKeyPair, err := keys.ParseBlsPrivateKey(blsPrivateKey)
+	registrationDataBuilder, err := registrar.NewRegistrationDataBuilder(cCtx, p, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +266,8 @@ func readAndValidateRegisterOperatorSetsConfig(cCtx *cli.Context, logger logging
 		delegationManagerAddress:   gethcommon.HexToAddress(delegationManagerAddress),
 		isSilent:                   isSilent,
 		registryCoordinatorAddress: gethcommon.HexToAddress(registryCoordinatorAddress),
-		blsKeyPair:                 blsKeyPair,
+		registrationDataBuilder:    registrationDataBuilder,
+		skipSimulation:             cCtx.Bool(registrar.SkipSimulationFlag.Name),
 	}
 
 	return config, nil
@@ -243,5 +286,11 @@ func getRegistrationFlags() []cli.Flag {
 		&flags.SilentFlag,
 		&flags.RegistryCoordinatorAddressFlag,
 		&flags.BlsPrivateKeyFlag,
+		&registrar.RegistrarTypeFlag,
+		&registrar.BlsKeystorePathFlag,
+		&registrar.SocketFlag,
+		&registrar.RegistrationDataFlag,
+		&registrar.AllowEmptyRegistrationDataFlag,
+		&registrar.SkipSimulationFlag,
 	}
 }
@@ -0,0 +1,109 @@
+package registrar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/keys"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/urfave/cli/v2"
+)
+
+// blsRegistrationDataBuilder builds the registration data of the standard BLS registrar
+// (the RegistryCoordinator of the middleware contracts): the operator socket and the BLS
+// public keys with a signature over the pubkey registration message hash of the operator.
+type blsRegistrationDataBuilder struct {
+	keyPair *bls.KeyPair
+	socket  string
+}
+
+func newBLSRegistrationDataBuilder(
+	cCtx *cli.Context,
+	p utils.Prompter,
+	logger logging.Logger,
+) (RegistrationDataBuilder, error) {
+	blsPrivateKey := cCtx.String(flags.BlsPrivateKeyFlag.Name)
+	keystorePath := cCtx.String(BlsKeystorePathFlag.Name)
+
+	var keyPair *bls.KeyPair
+	var err error
+	switch {
+	case !common.IsEmptyString(blsPrivateKey) && !common.IsEmptyString(keystorePath):
+		return nil, errors.New("only one of --bls-private-key and --bls-keystore-path can be set")
+	case !common.IsEmptyString(keystorePath):
+		password, err := p.InputHiddenString("Enter password to decrypt the bls private key:", "",
+			func(password string) error {
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		keyPair, err = bls.ReadPrivateKeyFromFile(keystorePath, password)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to read bls keystore", err)
+		}
+	case !common.IsEmptyString(blsPrivateKey):
+		keyPair, err = keys.ParseBlsPrivateKey(blsPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		logger.Error("--bls-keystore-path or --bls-private-key flag must be set")
+		return nil, fmt.Errorf("Empty BLS key provided")
+	}
+
+	return NewBLSRegistrationDataBuilder(keyPair, cCtx.String(SocketFlag.Name)), nil
+}
+
+// NewBLSRegistrationDataBuilder returns the builder of the standard BLS registrar for the key pair and socket
+func NewBLSRegistrationDataBuilder(keyPair *bls.KeyPair, socket string) RegistrationDataBuilder {
+	return &blsRegistrationDataBuilder{
+		keyPair: keyPair,
+		socket:  socket,
+	}
+}
+
+func (b *blsRegistrationDataBuilder) Build(
+	ctx context.Context,
+	backend bind.ContractBackend,
+	request RegistrationRequest,
+) ([]byte, error) {
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinator(request.RegistrarAddress, backend)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create registry coordinator", err)
+	}
+	messageHash, err := registryCoordinator.PubkeyRegistrationMessageHash(
+		&bind.CallOpts{Context: ctx},
+		request.OperatorAddress,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get pubkey registration message hash", err)
+	}
+	return encodeBLSRegistrationData(b.keyPair, b.socket, messageHash)
+}
+
+// encodeBLSRegistrationData signs the pubkey registration message hash and encodes it with the
+// public keys and socket in the format expected for a normal (non churn) registration.
+func encodeBLSRegistrationData(keyPair *bls.KeyPair, socket string, messageHash regcoord.BN254G1Point) ([]byte, error) {
+	signature := keyPair.SignHashedToCurveMessage(chainioutils.ConvertBn254GethToGnark(messageHash))
+	pubkeyRegistrationParams := regcoord.IBLSApkRegistryTypesPubkeyRegistrationParams{
+		PubkeyRegistrationSignature: chainioutils.ConvertToBN254G1Point(signature.G1Point),
+		PubkeyG1:                    chainioutils.ConvertToBN254G1Point(keyPair.GetPubKeyG1()),
+		PubkeyG2:                    chainioutils.ConvertToBN254G2Point(keyPair.GetPubKeyG2()),
+	}
+	return elcontracts.AbiEncodeNormalRegistrationParams(socket, pubkeyRegistrationParams)
+}
@@ -0,0 +1,45 @@
+package registrar
+
+import "github.com/urfave/cli/v2"
+
+var (
+	RegistrarTypeFlag = cli.StringFlag{
+		Name:    "registrar-type",
+		Aliases: []string{"rt"},
+		Usage:   "Format of the registration data expected by the AVS registrar. Supported types: 'bls', 'raw'",
+		Value:   BLSRegistrarType,
+		EnvVars: []string{"REGISTRAR_TYPE"},
+	}
+
+	BlsKeystorePathFlag = cli.StringFlag{
+		Name:    "bls-keystore-path",
+		Aliases: []string{"bkp"},
+		Usage:   "Path to the BLS keystore created by 'eigenlayer keys create'. Used by the 'bls' registrar type",
+		EnvVars: []string{"BLS_KEYSTORE_PATH"},
+	}
+
+	SocketFlag = cli.StringFlag{
+		Name:    "socket",
+		Usage:   "Socket of the operator registered with the AVS. Used by the 'bls' registrar type",
+		EnvVars: []string{"SOCKET"},
+	}
+
+	RegistrationDataFlag = cli.StringFlag{
+		Name:    "registration-data",
+		Aliases: []string{"rd"},
+		Usage:   "Hex encoded registration data passed to the AVS registrar as is. Used by the 'raw' registrar type",
+		EnvVars: []string{"REGISTRATION_DATA"},
+	}
+
+	AllowEmptyRegistrationDataFlag = cli.BoolFlag{
+		Name:    "allow-empty-registration-data",
+		Usage:   "Allow empty registration data with the 'raw' registrar type, for registrars which expect none",
+		EnvVars: []string{"ALLOW_EMPTY_REGISTRATION_DATA"},
+	}
+
+	SkipSimulationFlag = cli.BoolFlag{
+		Name:    "skip-simulation",
+		Usage:   "Skip simulating the registration against the AVS registrar before sending it",
+		EnvVars: []string{"SKIP_SIMULATION"},
+	}
+)
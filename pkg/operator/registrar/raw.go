@@ -0,0 +1,63 @@
+package registrar
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/urfave/cli/v2"
+)
+
+// rawRegistrationDataBuilder passes user provided registration data to the registrar as is.
+// It is meant for AVSs with custom registrars which have no builder yet.
+type rawRegistrationDataBuilder struct {
+	data []byte
+}
+
+func newRawRegistrationDataBuilder(
+	cCtx *cli.Context,
+	p utils.Prompter,
+	logger logging.Logger,
+) (RegistrationDataBuilder, error) {
+	data, err := decodeRawRegistrationData(
+		cCtx.String(RegistrationDataFlag.Name),
+		cCtx.Bool(AllowEmptyRegistrationDataFlag.Name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rawRegistrationDataBuilder{data: data}, nil
+}
+
+// decodeRawRegistrationData decodes the hex registration data. Empty data is rejected unless allowed, since
+// most registrars revert on it and it usually means --registration-data was forgotten.
+func decodeRawRegistrationData(hexData string, allowEmpty bool) ([]byte, error) {
+	data, err := hex.DecodeString(common.Trim0x(hexData))
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to decode registration data", err)
+	}
+	if len(data) == 0 && !allowEmpty {
+		return nil, fmt.Errorf(
+			"--%s must be set for the 'raw' registrar type. Use --%s if the registrar expects empty data",
+			RegistrationDataFlag.Name,
+			AllowEmptyRegistrationDataFlag.Name,
+		)
+	}
+	return data, nil
+}
+
+func (r *rawRegistrationDataBuilder) Build(
+	ctx context.Context,
+	backend bind.ContractBackend,
+	request RegistrationRequest,
+) ([]byte, error) {
+	return r.data, nil
+}
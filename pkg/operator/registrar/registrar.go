@@ -0,0 +1,74 @@
+package registrar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	BLSRegistrarType = "bls"
+	RawRegistrarType = "raw"
+)
+
+// RegistrationRequest describes the registration the data is built for
+type RegistrationRequest struct {
+	OperatorAddress  gethcommon.Address
+	AvsAddress       gethcommon.Address
+	RegistrarAddress gethcommon.Address
+	OperatorSetIds   []uint32
+}
+
+// RegistrationDataBuilder builds the opaque registration data which the AllocationManager
+// forwards to the registrar of the AVS when an operator registers for operator sets.
+type RegistrationDataBuilder interface {
+	Build(ctx context.Context, backend bind.ContractBackend, request RegistrationRequest) ([]byte, error)
+}
+
+type builderFactory func(cCtx *cli.Context, p utils.Prompter, logger logging.Logger) (RegistrationDataBuilder, error)
+
+var builderFactories = map[string]builderFactory{
+	BLSRegistrarType: newBLSRegistrationDataBuilder,
+	RawRegistrarType: newRawRegistrationDataBuilder,
+}
+
+// NewRegistrationDataBuilder returns the builder for the registrar type selected with --registrar-type
+func NewRegistrationDataBuilder(
+	cCtx *cli.Context,
+	p utils.Prompter,
+	logger logging.Logger,
+) (RegistrationDataBuilder, error) {
+	registrarType := strings.ToLower(cCtx.String(RegistrarTypeFlag.Name))
+	factory, ok := builderFactories[registrarType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported registrar type: %s", registrarType)
+	}
+	return factory(cCtx, p, logger)
+}
+
+// Simulate executes the transaction data against the latest block without sending it, so that
+// registrations rejected by the AVS registrar fail before anything is signed.
+func Simulate(
+	ctx context.Context,
+	caller ethereum.ContractCaller,
+	from gethcommon.Address,
+	to gethcommon.Address,
+	data []byte,
+) error {
+	_, err := caller.CallContract(ctx, ethereum.CallMsg{From: from, To: &to, Data: data}, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("registration simulation reverted", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package registrar
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	bn254utils "github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBLSRegistrationData(t *testing.T) {
+	keyPair, err := bls.NewKeyPairFromString("12248929636257230549931416853095037629726205319386239410403476017439825112537")
+	assert.NoError(t, err)
+
+	message := [32]byte{1, 2, 3}
+	messageHash := chainioutils.ConvertToBN254G1Point(&bls.G1Point{G1Affine: bn254utils.MapToCurve(message)})
+
+	data, err := encodeBLSRegistrationData(keyPair, "operator.example.com:32005", messageHash)
+	assert.NoError(t, err)
+
+	// The registration params are encoded as (uint8 registrationType, string socket, PubkeyRegistrationParams).
+	// The pubkey params are a static tuple, so the signature and G1 pubkey follow the socket offset in the head.
+	assert.Zero(t, new(big.Int).SetBytes(data[0:32]).Sign(), "normal registration type")
+
+	signature := &bls.Signature{G1Point: bls.NewG1Point(
+		new(big.Int).SetBytes(data[64:96]),
+		new(big.Int).SetBytes(data[96:128]),
+	)}
+	valid, err := signature.Verify(keyPair.GetPubKeyG2(), message)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	pubkeyG1 := keyPair.GetPubKeyG1()
+	assert.Equal(t, pubkeyG1.X.BigInt(new(big.Int)), new(big.Int).SetBytes(data[128:160]))
+	assert.Equal(t, pubkeyG1.Y.BigInt(new(big.Int)), new(big.Int).SetBytes(data[160:192]))
+	assert.Contains(t, string(data), "operator.example.com:32005")
+}
+
+func TestRawRegistrationDataBuilder(t *testing.T) {
+	builder := &rawRegistrationDataBuilder{data: []byte{0xde, 0xad}}
+	data, err := builder.Build(context.Background(), nil, RegistrationRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xde, 0xad}, data)
+}
+
+func TestDecodeRawRegistrationData(t *testing.T) {
+	data, err := decodeRawRegistrationData("0xdead", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xde, 0xad}, data)
+
+	_, err = decodeRawRegistrationData("", false)
+	assert.ErrorContains(t, err, "--allow-empty-registration-data")
+
+	data, err = decodeRawRegistrationData("0x", true)
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+
+	_, err = decodeRawRegistrationData("0xzz", true)
+	assert.Error(t, err)
+}
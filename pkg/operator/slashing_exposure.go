@@ -0,0 +1,590 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/batchreader"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+// beaconChainETHStrategyAddress is the virtual strategy of native restaked ETH. Its shares are ETH in wei.
+var beaconChainETHStrategyAddress = gethcommon.HexToAddress("0xbeaC0eeEeeeeEEeEeEEEEeeEEeEeeeEeeEEBEaC0")
+
+type slashingExposureReader interface {
+	GetRegisteredSets(ctx context.Context, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	GetAllocatedSets(ctx context.Context, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	IsOperatorSlashable(
+		ctx context.Context,
+		operator gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) (bool, error)
+	GetStrategiesInOperatorSet(
+		ctx context.Context,
+		operatorSet allocationmanager.OperatorSet,
+	) ([]gethcommon.Address, error)
+	GetAllocations(
+		ctx context.Context,
+		operator gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+		strategies []gethcommon.Address,
+	) ([]allocationmanager.IAllocationManagerTypesAllocation, error)
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operator gethcommon.Address,
+		strategies []gethcommon.Address,
+	) ([]uint64, error)
+	GetOperatorShares(
+		ctx context.Context,
+		operator gethcommon.Address,
+		strategies []gethcommon.Address,
+	) ([]*big.Int, error)
+	GetSlashableSharesInQueue(
+		ctx context.Context,
+		operator gethcommon.Address,
+		strategies []gethcommon.Address,
+	) ([]*big.Int, error)
+}
+
+// strategyUnderlyingReader converts strategy shares into amounts of the underlying token
+type strategyUnderlyingReader interface {
+	UnderlyingToken(ctx context.Context, strategy gethcommon.Address) (gethcommon.Address, string, error)
+	SharesToUnderlying(ctx context.Context, strategy gethcommon.Address, shares *big.Int) (*big.Int, error)
+}
+
+type chainStrategyUnderlyingReader struct {
+	reader    *batchreader.Reader
+	ethClient *ethclient.Client
+}
+
+func (r *chainStrategyUnderlyingReader) UnderlyingToken(
+	ctx context.Context,
+	strategyAddress gethcommon.Address,
+) (gethcommon.Address, string, error) {
+	if strategyAddress == beaconChainETHStrategyAddress {
+		return gethcommon.Address{}, "Beacon Chain ETH", nil
+	}
+	_, tokenAddress, err := r.reader.GetStrategyAndUnderlyingToken(ctx, strategyAddress)
+	if err != nil {
+		return gethcommon.Address{}, "", err
+	}
+	return tokenAddress, erc20.GetTokenName(tokenAddress, r.ethClient), nil
+}
+
+func (r *chainStrategyUnderlyingReader) SharesToUnderlying(
+	ctx context.Context,
+	strategyAddress gethcommon.Address,
+	shares *big.Int,
+) (*big.Int, error) {
+	if strategyAddress == beaconChainETHStrategyAddress {
+		return new(big.Int).Set(shares), nil
+	}
+	return r.reader.SharesToUnderlying(ctx, strategyAddress, shares)
+}
+
+// SlashingExposureReport lists the stake every operator set can slash. Token amounts are in the smallest
+// unit of the token.
+type SlashingExposureReport struct {
+	OperatorAddress gethcommon.Address    `json:"operator_address"`
+	BlockNumber     uint64                `json:"block_number"`
+	OperatorSets    []OperatorSetExposure `json:"operator_sets"`
+	WorstCaseLosses []AvsWorstCaseLoss    `json:"worst_case_losses"`
+}
+
+type OperatorSetExposure struct {
+	AvsAddress    gethcommon.Address `json:"avs_address"`
+	OperatorSetId uint32             `json:"operator_set_id"`
+	Registered    bool               `json:"registered"`
+	Strategies    []StrategyExposure `json:"strategies"`
+}
+
+// StrategyExposure is the slashable stake of a strategy in an operator set, now and once the pending
+// allocation change takes effect
+type StrategyExposure struct {
+	StrategyAddress        gethcommon.Address `json:"strategy_address"`
+	TokenAddress           gethcommon.Address `json:"token_address"`
+	TokenName              string             `json:"token_name"`
+	CurrentMagnitude       uint64             `json:"current_magnitude"`
+	PendingMagnitude       uint64             `json:"pending_magnitude"`
+	MaxMagnitude           uint64             `json:"max_magnitude"`
+	CurrentSlashableShares *big.Int           `json:"current_slashable_shares"`
+	PendingSlashableShares *big.Int           `json:"pending_slashable_shares"`
+	CurrentUnderlying      *big.Int           `json:"current_underlying"`
+	PendingUnderlying      *big.Int           `json:"pending_underlying"`
+}
+
+// AvsWorstCaseLoss is what the operator loses if the AVS slashes all of its operator sets by 100%
+type AvsWorstCaseLoss struct {
+	AvsAddress gethcommon.Address `json:"avs_address"`
+	Losses     []StrategyLoss     `json:"losses"`
+}
+
+type StrategyLoss struct {
+	StrategyAddress gethcommon.Address `json:"strategy_address"`
+	TokenAddress    gethcommon.Address `json:"token_address"`
+	TokenName       string             `json:"token_name"`
+	Shares          *big.Int           `json:"shares"`
+	Underlying      *big.Int           `json:"underlying"`
+}
+
+func SlashingExposureCmd(p utils.Prompter) *cli.Command {
+	slashingExposureCmd := &cli.Command{
+		Name:      "slashing-exposure",
+		Usage:     "Show the stake each AVS can slash",
+		UsageText: "slashing-exposure",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to show the slashing exposure of the operator
+
+For each operator set that can slash the operator, the slashable shares of every strategy are shown now
+and once pending allocation changes take effect, together with the amount of the underlying token.
+
+The worst-case loss of an AVS is what the operator loses if the AVS slashes all of its operator sets by
+100%. Pending deallocations stay slashable until they take effect and pending allocations become
+slashable once they take effect, so the larger of the current and pending amounts is used.
+
+Shares stakers queued for withdrawal stay slashable until the withdrawal delay has passed, so they are
+included in the slashable shares.
+`,
+		Flags: getSlashingExposureFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return slashingExposureAction(cCtx)
+		},
+	}
+	return slashingExposureCmd
+}
+
+func slashingExposureAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateSlashingExposureConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate slashing exposure config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	// All reads are pinned to the same block so the allocations and shares are consistent
+	elReader, err := batchreader.NewReader(ctx, ethClient, config.delegationManagerAddress, 0, 0, logger)
+	if err != nil {
+		return err
+	}
+
+	report, err := buildSlashingExposureReport(
+		ctx,
+		elReader,
+		&chainStrategyUnderlyingReader{reader: elReader, ethClient: ethClient},
+		config.operatorAddress,
+		config.avsAddresses,
+		elReader.BlockNumber(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if config.outputType == utils.JsonOutputType {
+		reportJson, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(reportJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Slashing exposure written to file: %s", config.output)
+		} else {
+			fmt.Println(string(reportJson))
+		}
+		return nil
+	}
+
+	if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+	report.PrintPretty()
+	return nil
+}
+
+// buildSlashingExposureReport reads the allocations of the operator to every operator set which can
+// slash it at currentBlock: the registered sets and the deregistered sets whose deallocation delay has
+// not passed yet. The readers must read at currentBlock.
+func buildSlashingExposureReport(
+	ctx context.Context,
+	reader slashingExposureReader,
+	underlyingReader strategyUnderlyingReader,
+	operatorAddress gethcommon.Address,
+	avsAddresses []gethcommon.Address,
+	currentBlock uint64,
+) (*SlashingExposureReport, error) {
+	report := &SlashingExposureReport{
+		OperatorAddress: operatorAddress,
+		BlockNumber:     currentBlock,
+		OperatorSets:    make([]OperatorSetExposure, 0),
+		WorstCaseLosses: make([]AvsWorstCaseLoss, 0),
+	}
+
+	registeredSets, err := reader.GetRegisteredSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+	allocatedSets, err := reader.GetAllocatedSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get allocated operator sets", err)
+	}
+	registered := make(map[allocationmanager.OperatorSet]bool, len(registeredSets))
+	for _, opSet := range registeredSets {
+		registered[opSet] = true
+	}
+	operatorSets := make([]allocationmanager.OperatorSet, 0, len(registeredSets)+len(allocatedSets))
+	seen := make(map[allocationmanager.OperatorSet]bool)
+	for _, opSet := range append(registeredSets, allocatedSets...) {
+		if seen[opSet] || !common.MatchesAddressFilter(avsAddresses, opSet.Avs) {
+			continue
+		}
+		seen[opSet] = true
+		operatorSets = append(operatorSets, opSet)
+	}
+	sort.Slice(operatorSets, func(i, j int) bool {
+		if operatorSets[i].Avs != operatorSets[j].Avs {
+			return bytes.Compare(operatorSets[i].Avs.Bytes(), operatorSets[j].Avs.Bytes()) < 0
+		}
+		return operatorSets[i].Id < operatorSets[j].Id
+	})
+
+	strategyCache := newStrategyExposureCache(ctx, reader, underlyingReader, operatorAddress)
+	for _, opSet := range operatorSets {
+		slashable, err := reader.IsOperatorSlashable(ctx, operatorAddress, opSet)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to check if operator is slashable", err)
+		}
+		if !slashable {
+			continue
+		}
+		strategies, err := reader.GetStrategiesInOperatorSet(ctx, opSet)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get strategies in operator set", err)
+		}
+
+		exposure := OperatorSetExposure{
+			AvsAddress:    opSet.Avs,
+			OperatorSetId: opSet.Id,
+			Registered:    registered[opSet],
+			Strategies:    make([]StrategyExposure, 0, len(strategies)),
+		}
+		allocations, err := reader.GetAllocations(ctx, operatorAddress, opSet, strategies)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get allocations", err)
+		}
+		for i, strategyAddress := range strategies {
+			allocation := allocations[i]
+			pendingMagnitude := allocation.CurrentMagnitude
+			if allocation.PendingDiff != nil && allocation.PendingDiff.Sign() != 0 {
+				pendingMagnitude = new(big.Int).Add(
+					new(big.Int).SetUint64(allocation.CurrentMagnitude),
+					allocation.PendingDiff,
+				).Uint64()
+			}
+			if allocation.CurrentMagnitude == 0 && pendingMagnitude == 0 {
+				continue
+			}
+
+			info, err := strategyCache.get(strategyAddress)
+			if err != nil {
+				return nil, err
+			}
+			strategyExposure := StrategyExposure{
+				StrategyAddress:  strategyAddress,
+				TokenAddress:     info.tokenAddress,
+				TokenName:        info.tokenName,
+				CurrentMagnitude: allocation.CurrentMagnitude,
+				PendingMagnitude: pendingMagnitude,
+				MaxMagnitude:     info.maxMagnitude,
+			}
+			strategyExposure.CurrentSlashableShares = common.GetSharesForMagnitude(
+				info.shares,
+				allocation.CurrentMagnitude,
+				info.maxMagnitude,
+			)
+			strategyExposure.PendingSlashableShares = common.GetSharesForMagnitude(
+				info.shares,
+				pendingMagnitude,
+				info.maxMagnitude,
+			)
+			strategyExposure.CurrentUnderlying, err = underlyingReader.SharesToUnderlying(
+				ctx,
+				strategyAddress,
+				strategyExposure.CurrentSlashableShares,
+			)
+			if err != nil {
+				return nil, eigenSdkUtils.WrapError("failed to convert shares to underlying", err)
+			}
+			strategyExposure.PendingUnderlying, err = underlyingReader.SharesToUnderlying(
+				ctx,
+				strategyAddress,
+				strategyExposure.PendingSlashableShares,
+			)
+			if err != nil {
+				return nil, eigenSdkUtils.WrapError("failed to convert shares to underlying", err)
+			}
+			exposure.Strategies = append(exposure.Strategies, strategyExposure)
+		}
+		report.OperatorSets = append(report.OperatorSets, exposure)
+	}
+
+	report.WorstCaseLosses = getWorstCaseLosses(report.OperatorSets)
+	return report, nil
+}
+
+// getWorstCaseLosses sums, for each AVS, the larger of the current and pending slashable stake of every
+// strategy over all of its operator sets. Allocations to different operator sets never overlap, so the
+// AVS can slash all of them.
+func getWorstCaseLosses(operatorSets []OperatorSetExposure) []AvsWorstCaseLoss {
+	losses := make([]AvsWorstCaseLoss, 0)
+	lossIndex := make(map[gethcommon.Address]int)
+	for _, opSet := range operatorSets {
+		i, ok := lossIndex[opSet.AvsAddress]
+		if !ok {
+			i = len(losses)
+			lossIndex[opSet.AvsAddress] = i
+			losses = append(losses, AvsWorstCaseLoss{AvsAddress: opSet.AvsAddress, Losses: make([]StrategyLoss, 0)})
+		}
+		for _, s := range opSet.Strategies {
+			shares, underlying := s.CurrentSlashableShares, s.CurrentUnderlying
+			if s.PendingSlashableShares.Cmp(shares) > 0 {
+				shares, underlying = s.PendingSlashableShares, s.PendingUnderlying
+			}
+			j := findStrategyLoss(losses[i].Losses, s.StrategyAddress)
+			if j < 0 {
+				losses[i].Losses = append(losses[i].Losses, StrategyLoss{
+					StrategyAddress: s.StrategyAddress,
+					TokenAddress:    s.TokenAddress,
+					TokenName:       s.TokenName,
+					Shares:          new(big.Int),
+					Underlying:      new(big.Int),
+				})
+				j = len(losses[i].Losses) - 1
+			}
+			losses[i].Losses[j].Shares.Add(losses[i].Losses[j].Shares, shares)
+			losses[i].Losses[j].Underlying.Add(losses[i].Losses[j].Underlying, underlying)
+		}
+	}
+	return losses
+}
+
+func findStrategyLoss(losses []StrategyLoss, strategyAddress gethcommon.Address) int {
+	for i, loss := range losses {
+		if loss.StrategyAddress == strategyAddress {
+			return i
+		}
+	}
+	return -1
+}
+
+type strategyExposureInfo struct {
+	tokenAddress gethcommon.Address
+	tokenName    string
+	// shares are the delegated shares of the operator together with the shares in the withdrawal queue,
+	// which stay slashable until the withdrawals can be completed
+	shares       *big.Int
+	maxMagnitude uint64
+}
+
+// strategyExposureCache reads the operator shares, max magnitude and token of a strategy once, since
+// strategies are shared by many operator sets
+type strategyExposureCache struct {
+	ctx              context.Context
+	reader           slashingExposureReader
+	underlyingReader strategyUnderlyingReader
+	operatorAddress  gethcommon.Address
+	infos            map[gethcommon.Address]strategyExposureInfo
+}
+
+func newStrategyExposureCache(
+	ctx context.Context,
+	reader slashingExposureReader,
+	underlyingReader strategyUnderlyingReader,
+	operatorAddress gethcommon.Address,
+) *strategyExposureCache {
+	return &strategyExposureCache{
+		ctx:              ctx,
+		reader:           reader,
+		underlyingReader: underlyingReader,
+		operatorAddress:  operatorAddress,
+		infos:            make(map[gethcommon.Address]strategyExposureInfo),
+	}
+}
+
+func (c *strategyExposureCache) get(strategyAddress gethcommon.Address) (strategyExposureInfo, error) {
+	if info, ok := c.infos[strategyAddress]; ok {
+		return info, nil
+	}
+	strategies := []gethcommon.Address{strategyAddress}
+	shares, err := c.reader.GetOperatorShares(c.ctx, c.operatorAddress, strategies)
+	if err != nil {
+		return strategyExposureInfo{}, eigenSdkUtils.WrapError("failed to get operator shares", err)
+	}
+	queuedShares, err := c.reader.GetSlashableSharesInQueue(c.ctx, c.operatorAddress, strategies)
+	if err != nil {
+		return strategyExposureInfo{}, eigenSdkUtils.WrapError("failed to get slashable shares in queue", err)
+	}
+	maxMagnitudes, err := c.reader.GetMaxMagnitudes(c.ctx, c.operatorAddress, strategies)
+	if err != nil {
+		return strategyExposureInfo{}, eigenSdkUtils.WrapError("failed to get max magnitudes", err)
+	}
+	tokenAddress, tokenName, err := c.underlyingReader.UnderlyingToken(c.ctx, strategyAddress)
+	if err != nil {
+		return strategyExposureInfo{}, eigenSdkUtils.WrapError("failed to get underlying token", err)
+	}
+	info := strategyExposureInfo{
+		tokenAddress: tokenAddress,
+		tokenName:    tokenName,
+		shares:       new(big.Int).Add(shares[0], queuedShares[0]),
+		maxMagnitude: maxMagnitudes[0],
+	}
+	c.infos[strategyAddress] = info
+	return info, nil
+}
+
+func (r *SlashingExposureReport) PrintPretty() {
+	fmt.Println()
+	fmt.Println("Slashing exposure report")
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Operator: %s\n", r.OperatorAddress.Hex())
+	fmt.Printf("Block:    %d\n", r.BlockNumber)
+	if len(r.OperatorSets) == 0 {
+		fmt.Println()
+		fmt.Println("No operator set can slash the operator")
+		fmt.Println(strings.Repeat("-", 80))
+		return
+	}
+
+	for _, opSet := range r.OperatorSets {
+		fmt.Println()
+		status := ""
+		if !opSet.Registered {
+			status = " (deregistered, slashable until the deallocation delay has passed)"
+		}
+		fmt.Printf("AVS %s, operator set %d%s\n", opSet.AvsAddress.Hex(), opSet.OperatorSetId, status)
+		if len(opSet.Strategies) == 0 {
+			fmt.Println("  No allocations")
+			continue
+		}
+		for _, s := range opSet.Strategies {
+			fmt.Printf("  - strategy %s (%s)\n", s.StrategyAddress.Hex(), s.TokenName)
+			fmt.Printf(
+				"      current: %s shares = %s underlying (magnitude %s)\n",
+				common.FormatNumberWithUnderscores(s.CurrentSlashableShares.String()),
+				common.FormatNumberWithUnderscores(s.CurrentUnderlying.String()),
+				common.FormatNumberWithUnderscores(common.Uint64ToString(s.CurrentMagnitude)),
+			)
+			if s.PendingMagnitude != s.CurrentMagnitude {
+				fmt.Printf(
+					"      pending: %s shares = %s underlying (magnitude %s)\n",
+					common.FormatNumberWithUnderscores(s.PendingSlashableShares.String()),
+					common.FormatNumberWithUnderscores(s.PendingUnderlying.String()),
+					common.FormatNumberWithUnderscores(common.Uint64ToString(s.PendingMagnitude)),
+				)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Worst-case loss if an AVS slashes 100%:")
+	for _, loss := range r.WorstCaseLosses {
+		fmt.Printf("  AVS %s\n", loss.AvsAddress.Hex())
+		if len(loss.Losses) == 0 {
+			fmt.Println("    Nothing allocated")
+		}
+		for _, l := range loss.Losses {
+			fmt.Printf(
+				"    - %s %s (%s shares of strategy %s)\n",
+				common.FormatNumberWithUnderscores(l.Underlying.String()),
+				l.TokenName,
+				common.FormatNumberWithUnderscores(l.Shares.String()),
+				l.StrategyAddress.Hex(),
+			)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 80))
+}
+
+func getSlashingExposureFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.AVSAddressesFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func readAndValidateSlashingExposureConfig(
+	cCtx *cli.Context,
+	logger logging.Logger,
+) (*slashingExposureConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+	avsAddresses := common.ConvertStringSliceToGethAddressSlice(cCtx.StringSlice(flags.AVSAddressesFlag.Name))
+
+	chainID := utils.NetworkNameToChainId(network)
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	var err error
+	if delegationManagerAddress == "" {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &slashingExposureConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		environment:              environment,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		operatorAddress:          gethcommon.HexToAddress(operatorAddress),
+		avsAddresses:             avsAddresses,
+		delegationManagerAddress: gethcommon.HexToAddress(delegationManagerAddress),
+	}, nil
+}
@@ -0,0 +1,238 @@
+package operator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSlashingExposureReader struct {
+	registeredSets []allocationmanager.OperatorSet
+	allocatedSets  []allocationmanager.OperatorSet
+	notSlashable   map[allocationmanager.OperatorSet]bool
+	strategies     map[allocationmanager.OperatorSet][]gethcommon.Address
+	allocations    map[allocationmanager.OperatorSet]strategyAllocations
+	maxMagnitudes  map[gethcommon.Address]uint64
+	shares         map[gethcommon.Address]*big.Int
+	queuedShares   map[gethcommon.Address]*big.Int
+}
+
+func (f *fakeSlashingExposureReader) GetRegisteredSets(
+	ctx context.Context,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func (f *fakeSlashingExposureReader) GetAllocatedSets(
+	ctx context.Context,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.allocatedSets, nil
+}
+
+func (f *fakeSlashingExposureReader) IsOperatorSlashable(
+	ctx context.Context,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	return !f.notSlashable[operatorSet], nil
+}
+
+func (f *fakeSlashingExposureReader) GetStrategiesInOperatorSet(
+	ctx context.Context,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.strategies[operatorSet], nil
+}
+
+func (f *fakeSlashingExposureReader) GetAllocations(
+	ctx context.Context,
+	operator gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+	strategies []gethcommon.Address,
+) ([]allocationmanager.IAllocationManagerTypesAllocation, error) {
+	result := make([]allocationmanager.IAllocationManagerTypesAllocation, len(strategies))
+	for i, strategy := range strategies {
+		allocation, ok := f.allocations[operatorSet][strategy]
+		if !ok {
+			allocation = allocationmanager.IAllocationManagerTypesAllocation{PendingDiff: big.NewInt(0)}
+		}
+		result[i] = allocation
+	}
+	return result, nil
+}
+
+func (f *fakeSlashingExposureReader) GetMaxMagnitudes(
+	ctx context.Context,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]uint64, error) {
+	result := make([]uint64, len(strategies))
+	for i, strategy := range strategies {
+		result[i] = f.maxMagnitudes[strategy]
+	}
+	return result, nil
+}
+
+func (f *fakeSlashingExposureReader) GetOperatorShares(
+	ctx context.Context,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]*big.Int, error) {
+	result := make([]*big.Int, len(strategies))
+	for i, strategy := range strategies {
+		result[i] = f.shares[strategy]
+	}
+	return result, nil
+}
+
+func (f *fakeSlashingExposureReader) GetSlashableSharesInQueue(
+	ctx context.Context,
+	operator gethcommon.Address,
+	strategies []gethcommon.Address,
+) ([]*big.Int, error) {
+	result := make([]*big.Int, len(strategies))
+	for i, strategy := range strategies {
+		result[i] = big.NewInt(0)
+		if shares, ok := f.queuedShares[strategy]; ok {
+			result[i] = shares
+		}
+	}
+	return result, nil
+}
+
+// fakeStrategyUnderlyingReader converts shares with a fixed exchange rate of 2 underlying per share
+type fakeStrategyUnderlyingReader struct{}
+
+func (f *fakeStrategyUnderlyingReader) UnderlyingToken(
+	ctx context.Context,
+	strategy gethcommon.Address,
+) (gethcommon.Address, string, error) {
+	return strategy, "TOKEN", nil
+}
+
+func (f *fakeStrategyUnderlyingReader) SharesToUnderlying(
+	ctx context.Context,
+	strategy gethcommon.Address,
+	shares *big.Int,
+) (*big.Int, error) {
+	return new(big.Int).Mul(shares, big.NewInt(2)), nil
+}
+
+func TestBuildSlashingExposureReport(t *testing.T) {
+	operator := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avs := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	otherAvs := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	strategyA := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyB := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+
+	opSet1 := allocationmanager.OperatorSet{Avs: avs, Id: 1}
+	opSet2 := allocationmanager.OperatorSet{Avs: avs, Id: 2}
+	deregisteredSet := allocationmanager.OperatorSet{Avs: otherAvs, Id: 0}
+	expiredSet := allocationmanager.OperatorSet{Avs: otherAvs, Id: 1}
+
+	reader := &fakeSlashingExposureReader{
+		registeredSets: []allocationmanager.OperatorSet{opSet2, opSet1},
+		allocatedSets:  []allocationmanager.OperatorSet{opSet1, deregisteredSet, expiredSet},
+		notSlashable:   map[allocationmanager.OperatorSet]bool{expiredSet: true},
+		strategies: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			opSet1:          {strategyA, strategyB},
+			opSet2:          {strategyA},
+			deregisteredSet: {strategyB},
+			expiredSet:      {strategyB},
+		},
+		allocations: map[allocationmanager.OperatorSet]strategyAllocations{
+			opSet1: {
+				// Pending deallocation: stays slashable until it takes effect
+				strategyA: {CurrentMagnitude: 500, PendingDiff: big.NewInt(-200)},
+			},
+			opSet2: {
+				// Pending allocation: becomes slashable once it takes effect
+				strategyA: {CurrentMagnitude: 100, PendingDiff: big.NewInt(150)},
+			},
+			deregisteredSet: {
+				strategyB: {CurrentMagnitude: 1000, PendingDiff: big.NewInt(0)},
+			},
+			expiredSet: {
+				strategyB: {CurrentMagnitude: 1000, PendingDiff: big.NewInt(0)},
+			},
+		},
+		maxMagnitudes: map[gethcommon.Address]uint64{strategyA: 1000, strategyB: 2000},
+		shares: map[gethcommon.Address]*big.Int{
+			strategyA: big.NewInt(10_000),
+			strategyB: big.NewInt(4_000),
+		},
+		// Queued withdrawals stay slashable until they can be completed
+		queuedShares: map[gethcommon.Address]*big.Int{strategyB: big.NewInt(1_000)},
+	}
+
+	t.Run("all slashable operator sets", func(t *testing.T) {
+		report, err := buildSlashingExposureReport(
+			context.Background(),
+			reader,
+			&fakeStrategyUnderlyingReader{},
+			operator,
+			nil,
+			100,
+		)
+		assert.NoError(t, err)
+		assert.Len(t, report.OperatorSets, 3)
+
+		set1 := report.OperatorSets[0]
+		assert.Equal(t, opSet1.Id, set1.OperatorSetId)
+		assert.True(t, set1.Registered)
+		// strategyB has no allocation in opSet1
+		assert.Len(t, set1.Strategies, 1)
+		assert.Equal(t, uint64(300), set1.Strategies[0].PendingMagnitude)
+		assert.Equal(t, int64(5_000), set1.Strategies[0].CurrentSlashableShares.Int64())
+		assert.Equal(t, int64(3_000), set1.Strategies[0].PendingSlashableShares.Int64())
+		assert.Equal(t, int64(10_000), set1.Strategies[0].CurrentUnderlying.Int64())
+		assert.Equal(t, int64(6_000), set1.Strategies[0].PendingUnderlying.Int64())
+
+		set2 := report.OperatorSets[1]
+		assert.Equal(t, opSet2.Id, set2.OperatorSetId)
+		assert.Equal(t, int64(1_000), set2.Strategies[0].CurrentSlashableShares.Int64())
+		assert.Equal(t, int64(2_500), set2.Strategies[0].PendingSlashableShares.Int64())
+
+		deregistered := report.OperatorSets[2]
+		assert.Equal(t, otherAvs, deregistered.AvsAddress)
+		assert.False(t, deregistered.Registered)
+		// (4000 + 1000 queued) * 1000 / 2000
+		assert.Equal(t, int64(2_500), deregistered.Strategies[0].CurrentSlashableShares.Int64())
+
+		assert.Len(t, report.WorstCaseLosses, 2)
+		avsLoss := report.WorstCaseLosses[0]
+		assert.Equal(t, avs, avsLoss.AvsAddress)
+		assert.Len(t, avsLoss.Losses, 1)
+		// max(5000, 3000) + max(1000, 2500)
+		assert.Equal(t, int64(7_500), avsLoss.Losses[0].Shares.Int64())
+		assert.Equal(t, int64(15_000), avsLoss.Losses[0].Underlying.Int64())
+
+		otherAvsLoss := report.WorstCaseLosses[1]
+		assert.Equal(t, int64(2_500), otherAvsLoss.Losses[0].Shares.Int64())
+	})
+
+	t.Run("filtered by AVS", func(t *testing.T) {
+		report, err := buildSlashingExposureReport(
+			context.Background(),
+			reader,
+			&fakeStrategyUnderlyingReader{},
+			operator,
+			[]gethcommon.Address{otherAvs},
+			100,
+		)
+		assert.NoError(t, err)
+		assert.Len(t, report.OperatorSets, 1)
+		assert.Len(t, report.WorstCaseLosses, 1)
+		assert.Equal(t, otherAvs, report.WorstCaseLosses[0].AvsAddress)
+	})
+}
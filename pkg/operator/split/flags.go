@@ -24,4 +24,12 @@ var (
 		Usage:   "Operator set ID to set operator split",
 		EnvVars: []string{"OPERATOR_SET_ID"},
 	}
+
+	SplitsFileFlag = cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "Path to the CSV file with the split updates. Columns: type,avs_address,operator_set_id,split",
+		Required: true,
+		EnvVars:  []string{"SPLITS_FILE"},
+	}
 )
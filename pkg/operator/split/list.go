@@ -0,0 +1,486 @@
+package split
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	PISplitType          = "pi"
+	AVSSplitType         = "avs"
+	OperatorSetSplitType = "operatorset"
+)
+
+type elChainReader interface {
+	GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error)
+	GetOperatorAVSSplit(ctx context.Context, operator gethcommon.Address, avs gethcommon.Address) (uint16, error)
+	GetOperatorSetSplit(
+		ctx context.Context,
+		operator gethcommon.Address,
+		operatorSet rewardscoordinator.OperatorSet,
+	) (uint16, error)
+	GetRegisteredSets(ctx context.Context, operatorAddress gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+}
+
+// splitUpdate is the latest split update of the operator found in RewardsCoordinator events
+type splitUpdate struct {
+	newSplit    uint16
+	activatedAt uint32
+}
+
+type splitUpdates struct {
+	pi           *splitUpdate
+	avs          map[gethcommon.Address]splitUpdate
+	operatorSets map[rewardscoordinator.OperatorSet]splitUpdate
+}
+
+func newSplitUpdates() *splitUpdates {
+	return &splitUpdates{
+		avs:          make(map[gethcommon.Address]splitUpdate),
+		operatorSets: make(map[rewardscoordinator.OperatorSet]splitUpdate),
+	}
+}
+
+func ListCmd(p utils.Prompter) *cli.Command {
+	listCmd := &cli.Command{
+		Name:      "list",
+		Usage:     "List the current and pending rewards splits of the operator",
+		UsageText: "list [flags]",
+		Description: `
+Lists the programmatic incentives split and the split of every AVS and operator set the operator
+is registered for or has a pending split for, with the pending split and its activation time.
+		`,
+		Flags: getListFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return listSplits(cCtx)
+		},
+	}
+
+	return listCmd
+}
+
+func listSplits(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateListSplitsConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate list splits config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elConfig := elcontracts.Config{
+		DelegationManagerAddress:  config.delegationManagerAddress,
+		RewardsCoordinatorAddress: config.rewardsCoordinatorAddress,
+	}
+	elReader, _, contractBindings, err := elcontracts.BuildClients(elConfig, ethClient, nil, logger, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	header, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block header", err)
+	}
+
+	updates, err := getSplitUpdates(ctx, ethClient, contractBindings.RewardsCoordinator, config.operatorAddress, header)
+	if err != nil {
+		// Without the events we can still show the active splits, just not the pending ones.
+		logger.Warnf("Failed to fetch split events, pending splits will not be shown: %s", err)
+		updates = newSplitUpdates()
+	}
+
+	splits, err := getOperatorSplits(ctx, elReader, config.operatorAddress, updates, header.Time)
+	if err != nil {
+		return err
+	}
+
+	if config.outputType == utils.JsonOutputType {
+		splitsJson, err := json.MarshalIndent(splits, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(splitsJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Splits written to file: %s", config.output)
+		} else {
+			fmt.Println(string(splitsJson))
+		}
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("Rewards splits for operator %s\n", config.operatorAddress.Hex())
+	OperatorSplits(splits).PrintPretty()
+	return nil
+}
+
+// getSplitUpdates returns the latest split update of the operator for programmatic incentives, every
+// AVS and every operator set, as emitted by the RewardsCoordinator. A split update is only pending
+// for the activation delay after it is queued, so only the events of that window are read.
+func getSplitUpdates(
+	ctx context.Context,
+	headerReader common.HeaderReader,
+	rewardsCoordinator *rewardscoordinator.ContractRewardsCoordinator,
+	operatorAddress gethcommon.Address,
+	latestHeader *types.Header,
+) (*splitUpdates, error) {
+	activationDelay, err := rewardsCoordinator.ActivationDelay(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get activation delay", err)
+	}
+	latestBlock := latestHeader.Number.Uint64()
+	fromTimestamp := uint64(0)
+	if latestHeader.Time > uint64(activationDelay) {
+		fromTimestamp = latestHeader.Time - uint64(activationDelay)
+	}
+	fromBlock, err := common.FindBlockByTimestamp(ctx, headerReader, latestBlock, fromTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := newSplitUpdates()
+	operators := []gethcommon.Address{operatorAddress}
+	err = common.FilterLogsInRanges(ctx, fromBlock, latestBlock, func(opts *bind.FilterOpts) error {
+		piIterator, err := rewardsCoordinator.FilterOperatorPISplitBipsSet(opts, nil, operators)
+		if err != nil {
+			return err
+		}
+		defer piIterator.Close()
+		for piIterator.Next() {
+			updates.pi = &splitUpdate{
+				newSplit:    piIterator.Event.NewOperatorPISplitBips,
+				activatedAt: piIterator.Event.ActivatedAt,
+			}
+		}
+		if piIterator.Error() != nil {
+			return piIterator.Error()
+		}
+
+		avsIterator, err := rewardsCoordinator.FilterOperatorAVSSplitBipsSet(opts, nil, operators, nil)
+		if err != nil {
+			return err
+		}
+		defer avsIterator.Close()
+		for avsIterator.Next() {
+			updates.avs[avsIterator.Event.Avs] = splitUpdate{
+				newSplit:    avsIterator.Event.NewOperatorAVSSplitBips,
+				activatedAt: avsIterator.Event.ActivatedAt,
+			}
+		}
+		if avsIterator.Error() != nil {
+			return avsIterator.Error()
+		}
+
+		operatorSetIterator, err := rewardsCoordinator.FilterOperatorSetSplitBipsSet(opts, nil, operators)
+		if err != nil {
+			return err
+		}
+		defer operatorSetIterator.Close()
+		for operatorSetIterator.Next() {
+			updates.operatorSets[operatorSetIterator.Event.OperatorSet] = splitUpdate{
+				newSplit:    operatorSetIterator.Event.NewOperatorSetSplitBips,
+				activatedAt: operatorSetIterator.Event.ActivatedAt,
+			}
+		}
+		return operatorSetIterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// getOperatorSplits returns the split of the operator for programmatic incentives and for every AVS
+// and operator set the operator is registered for or has a pending split for.
+func getOperatorSplits(
+	ctx context.Context,
+	elReader elChainReader,
+	operatorAddress gethcommon.Address,
+	updates *splitUpdates,
+	now uint64,
+) ([]OperatorSplit, error) {
+	splits := make([]OperatorSplit, 0)
+
+	piSplit, err := elReader.GetOperatorPISplit(ctx, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get operator PI split", err)
+	}
+	splits = append(splits, newOperatorSplit(PISplitType, gethcommon.Address{}, nil, piSplit, updates.pi, now))
+
+	registeredSets, err := elReader.GetRegisteredSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+
+	avsSet := make(map[gethcommon.Address]bool)
+	operatorSetSet := make(map[rewardscoordinator.OperatorSet]bool)
+	for _, opSet := range registeredSets {
+		avsSet[opSet.Avs] = true
+		operatorSetSet[rewardscoordinator.OperatorSet{Avs: opSet.Avs, Id: opSet.Id}] = true
+	}
+	for avs := range updates.avs {
+		avsSet[avs] = true
+	}
+	for opSet := range updates.operatorSets {
+		operatorSetSet[opSet] = true
+	}
+
+	avsAddresses := make([]gethcommon.Address, 0, len(avsSet))
+	for avs := range avsSet {
+		avsAddresses = append(avsAddresses, avs)
+	}
+	sort.Slice(avsAddresses, func(i, j int) bool {
+		return bytes.Compare(avsAddresses[i].Bytes(), avsAddresses[j].Bytes()) < 0
+	})
+	for _, avs := range avsAddresses {
+		avsSplit, err := elReader.GetOperatorAVSSplit(ctx, operatorAddress, avs)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get operator AVS split", err)
+		}
+		var update *splitUpdate
+		if u, ok := updates.avs[avs]; ok {
+			update = &u
+		}
+		splits = append(splits, newOperatorSplit(AVSSplitType, avs, nil, avsSplit, update, now))
+	}
+
+	operatorSets := make([]rewardscoordinator.OperatorSet, 0, len(operatorSetSet))
+	for opSet := range operatorSetSet {
+		operatorSets = append(operatorSets, opSet)
+	}
+	sort.Slice(operatorSets, func(i, j int) bool {
+		c := bytes.Compare(operatorSets[i].Avs.Bytes(), operatorSets[j].Avs.Bytes())
+		if c != 0 {
+			return c < 0
+		}
+		return operatorSets[i].Id < operatorSets[j].Id
+	})
+	for _, opSet := range operatorSets {
+		opSetSplit, err := elReader.GetOperatorSetSplit(ctx, operatorAddress, opSet)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get operator set split", err)
+		}
+		var update *splitUpdate
+		if u, ok := updates.operatorSets[opSet]; ok {
+			update = &u
+		}
+		id := opSet.Id
+		splits = append(splits, newOperatorSplit(OperatorSetSplitType, opSet.Avs, &id, opSetSplit, update, now))
+	}
+
+	return splits, nil
+}
+
+func newOperatorSplit(
+	splitType string,
+	avsAddress gethcommon.Address,
+	operatorSetId *uint32,
+	currentSplit uint16,
+	update *splitUpdate,
+	now uint64,
+) OperatorSplit {
+	operatorSplit := OperatorSplit{
+		Type:          splitType,
+		OperatorSetId: operatorSetId,
+		CurrentSplit:  currentSplit,
+	}
+	if splitType != PISplitType {
+		operatorSplit.AvsAddress = avsAddress.Hex()
+	}
+	if update != nil && uint64(update.activatedAt) > now {
+		pendingSplit := update.newSplit
+		activatedAt := update.activatedAt
+		operatorSplit.PendingSplit = &pendingSplit
+		operatorSplit.ActivatedAt = &activatedAt
+	}
+	return operatorSplit
+}
+
+// pendingActivation returns the activation timestamp of the pending split of the given type, if any
+func (u *splitUpdates) pendingActivation(splitType string, avs gethcommon.Address, operatorSetId uint32, now uint64) (uint32, bool) {
+	var update *splitUpdate
+	switch splitType {
+	case PISplitType:
+		update = u.pi
+	case AVSSplitType:
+		if v, ok := u.avs[avs]; ok {
+			update = &v
+		}
+	case OperatorSetSplitType:
+		if v, ok := u.operatorSets[rewardscoordinator.OperatorSet{Avs: avs, Id: operatorSetId}]; ok {
+			update = &v
+		}
+	}
+	if update == nil || uint64(update.activatedAt) <= now {
+		return 0, false
+	}
+	return update.activatedAt, true
+}
+
+type OperatorSplits []OperatorSplit
+
+func (s OperatorSplits) PrintPretty() {
+	headers := []string{
+		"Type",
+		"AVS Address",
+		"Operator Set ID",
+		"Current Split (bips)",
+		"Pending Split (bips)",
+		"Activation Time (UTC)",
+	}
+	widths := []int{12, 44, 16, 21, 21, 25}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+
+	// Print header
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+
+	// Print separator
+	for _, width := range widths {
+		fmt.Print("|", strings.Repeat("-", width+1))
+	}
+	fmt.Println("|")
+
+	for _, split := range s {
+		avsAddress := "-"
+		if !common.IsEmptyString(split.AvsAddress) {
+			avsAddress = split.AvsAddress
+		}
+		operatorSetId := "-"
+		if split.OperatorSetId != nil {
+			operatorSetId = fmt.Sprintf("%d", *split.OperatorSetId)
+		}
+		pendingSplit := "-"
+		activatedAt := "-"
+		if split.PendingSplit != nil {
+			pendingSplit = fmt.Sprintf("%d", *split.PendingSplit)
+			activatedAt = time.Unix(int64(*split.ActivatedAt), 0).UTC().Format(time.DateTime)
+		}
+		fmt.Printf(
+			"| %-*s| %-*s| %-*s| %-*d| %-*s| %-*s|\n",
+			widths[0], split.Type,
+			widths[1], avsAddress,
+			widths[2], operatorSetId,
+			widths[3], split.CurrentSplit,
+			widths[4], pendingSplit,
+			widths[5], activatedAt,
+		)
+	}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+}
+
+func readAndValidateListSplitsConfig(cCtx *cli.Context, logger logging.Logger) (*listSplitsConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	rewardsCoordinatorAddress, delegationManagerAddress, err := getContractAddresses(cCtx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listSplitsConfig{
+		network:                   network,
+		rpcUrl:                    rpcUrl,
+		chainID:                   chainID,
+		output:                    output,
+		outputType:                outputType,
+		operatorAddress:           gethcommon.HexToAddress(operatorAddress),
+		rewardsCoordinatorAddress: rewardsCoordinatorAddress,
+		delegationManagerAddress:  delegationManagerAddress,
+	}, nil
+}
+
+func getContractAddresses(cCtx *cli.Context, chainID *big.Int) (gethcommon.Address, gethcommon.Address, error) {
+	var err error
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return gethcommon.Address{}, gethcommon.Address{}, err
+		}
+	}
+
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	if common.IsEmptyString(delegationManagerAddress) {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return gethcommon.Address{}, gethcommon.Address{}, err
+		}
+	}
+
+	return gethcommon.HexToAddress(rewardsCoordinatorAddress), gethcommon.HexToAddress(delegationManagerAddress), nil
+}
+
+func getListFlags() []cli.Flag {
+	listFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(listFlags))
+	return listFlags
+}
@@ -0,0 +1,442 @@
+package split
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gocarina/gocsv"
+	"github.com/urfave/cli/v2"
+)
+
+const maxSplitBips = 10_000
+
+func SetCmd(p utils.Prompter) *cli.Command {
+	setCmd := &cli.Command{
+		Name:      "set",
+		Usage:     "Queue multiple rewards split updates from a CSV file",
+		UsageText: "set --file <splits.csv> [flags]",
+		Description: `
+Queues a split update for every row of the CSV file. The file has the columns
+'type,avs_address,operator_set_id,split' where type is one of 'pi', 'avs' or 'operatorset'.
+
+Split updates only take effect after the RewardsCoordinator activation delay, and a split can't be
+updated again while a previous update is still pending.
+		`,
+		Flags: getSetFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return setSplits(cCtx, p)
+		},
+	}
+
+	return setCmd
+}
+
+func setSplits(cCtx *cli.Context, p utils.Prompter) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateSetSplitsConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate set splits config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	elConfig := elcontracts.Config{
+		DelegationManagerAddress:  config.delegationManagerAddress,
+		RewardsCoordinatorAddress: config.rewardsCoordinatorAddress,
+	}
+	elReader, _, contractBindings, err := elcontracts.BuildClients(elConfig, ethClient, nil, logger, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	header, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block header", err)
+	}
+
+	updates, err := getSplitUpdates(ctx, ethClient, contractBindings.RewardsCoordinator, config.operatorAddress, header)
+	if err != nil {
+		logger.Warnf("Failed to fetch split events, unable to check for pending splits: %s", err)
+	} else if err := checkNoPendingSplits(config.splits, updates, header.Time); err != nil {
+		return err
+	}
+
+	activationDelay, err := elReader.GetActivationDelay(ctx)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get activation delay", err)
+	}
+
+	printSplitRecords(config.splits)
+	activationTime := time.Unix(int64(header.Time)+int64(activationDelay), 0).UTC()
+	fmt.Printf(
+		"%s Split updates are not effective immediately. They activate after the RewardsCoordinator activation delay of %s (around %s UTC if included now).\n",
+		utils.EmojiWarning,
+		time.Duration(activationDelay)*time.Second,
+		activationTime.Format(time.DateTime),
+	)
+	fmt.Println()
+
+	if config.broadcast {
+		if config.signerConfig == nil {
+			return errors.New("signer is required for broadcasting")
+		}
+		confirm, err := p.Confirm(
+			fmt.Sprintf("This will queue %d split update(s) for the operator. Do you want to continue?", len(config.splits)),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			logger.Info("Operation cancelled")
+			return nil
+		}
+
+		eLWriter, err := common.GetELWriter(
+			config.callerAddress,
+			config.signerConfig,
+			ethClient,
+			elConfig,
+			p,
+			config.chainID,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get EL writer", err)
+		}
+
+		for _, record := range config.splits {
+			receipt, err := sendSplitUpdate(ctx, eLWriter, config.operatorAddress, record)
+			if err != nil {
+				return eigenSdkUtils.WrapError(fmt.Sprintf("failed to set %s split", record.Type), err)
+			}
+			common.PrintTransactionInfo(receipt.TxHash.String(), config.chainID)
+		}
+		return nil
+	}
+
+	noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
+	// If caller is a smart contract, we can't estimate gas using geth
+	// since balance of contract can be 0, as it can be called by an EOA
+	// to claim. So we hardcode the gas limit to 150_000 so that we can
+	// create unsigned tx without gas limit estimation from contract bindings
+	if common.IsSmartContractAddress(config.callerAddress, ethClient) {
+		// address is a smart contract
+		noSendTxOpts.GasLimit = 150_000
+	}
+
+	unsignedTxs := make([]*gethtypes.Transaction, 0, len(config.splits))
+	for _, record := range config.splits {
+		unsignedTx, err := buildSplitUpdateTx(noSendTxOpts, contractBindings, config.operatorAddress, record)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to create unsigned tx", err)
+		}
+		unsignedTxs = append(unsignedTxs, unsignedTx)
+	}
+
+	if !common.IsEmptyString(config.safeBatchFile) {
+		batch := common.NewSafeBatch(
+			config.chainID,
+			config.callerAddress,
+			"Operator split updates",
+			fmt.Sprintf("Set %d rewards splits for operator %s", len(unsignedTxs), config.operatorAddress.Hex()),
+		)
+		for _, unsignedTx := range unsignedTxs {
+			batch.AddTransaction(unsignedTx)
+		}
+		if err := batch.WriteToFile(config.safeBatchFile); err != nil {
+			return err
+		}
+		logger.Infof("Safe transaction batch written to file: %s", config.safeBatchFile)
+		return nil
+	}
+
+	if config.outputType == utils.CallDataOutputType {
+		lines := make([]string, 0, len(unsignedTxs))
+		for _, unsignedTx := range unsignedTxs {
+			lines = append(lines, gethcommon.Bytes2Hex(unsignedTx.Data()))
+		}
+		calldata := strings.Join(lines, "\n")
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile([]byte(calldata), config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Call data written to file: %s", config.output)
+		} else {
+			fmt.Println(calldata)
+		}
+	} else if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+
+	if !config.isSilent {
+		for _, unsignedTx := range unsignedTxs {
+			fmt.Println()
+			common.GetTxFeeDetails(unsignedTx).Print()
+		}
+		fmt.Println("To broadcast the split updates, use the --broadcast flag")
+	}
+	return nil
+}
+
+func sendSplitUpdate(
+	ctx context.Context,
+	eLWriter *elcontracts.ChainWriter,
+	operatorAddress gethcommon.Address,
+	record splitRecord,
+) (*gethtypes.Receipt, error) {
+	avsAddress := gethcommon.HexToAddress(record.AvsAddress)
+	switch record.Type {
+	case PISplitType:
+		return eLWriter.SetOperatorPISplit(ctx, operatorAddress, record.Split, true)
+	case AVSSplitType:
+		return eLWriter.SetOperatorAVSSplit(ctx, operatorAddress, avsAddress, record.Split, true)
+	default:
+		operatorSet := rewardscoordinator.OperatorSet{Avs: avsAddress, Id: record.OperatorSetId}
+		return eLWriter.SetOperatorSetSplit(ctx, operatorAddress, operatorSet, record.Split, true)
+	}
+}
+
+func buildSplitUpdateTx(
+	noSendTxOpts *bind.TransactOpts,
+	contractBindings *elcontracts.ContractBindings,
+	operatorAddress gethcommon.Address,
+	record splitRecord,
+) (*gethtypes.Transaction, error) {
+	avsAddress := gethcommon.HexToAddress(record.AvsAddress)
+	switch record.Type {
+	case PISplitType:
+		return contractBindings.RewardsCoordinator.SetOperatorPISplit(noSendTxOpts, operatorAddress, record.Split)
+	case AVSSplitType:
+		return contractBindings.RewardsCoordinator.SetOperatorAVSSplit(
+			noSendTxOpts,
+			operatorAddress,
+			avsAddress,
+			record.Split,
+		)
+	default:
+		operatorSet := rewardscoordinator.OperatorSet{Avs: avsAddress, Id: record.OperatorSetId}
+		return contractBindings.RewardsCoordinator.SetOperatorSetSplit(
+			noSendTxOpts,
+			operatorAddress,
+			operatorSet,
+			record.Split,
+		)
+	}
+}
+
+// checkNoPendingSplits fails if any of the splits to update still has a pending update,
+// since the RewardsCoordinator reverts in that case.
+func checkNoPendingSplits(records []splitRecord, updates *splitUpdates, now uint64) error {
+	pending := make([]string, 0)
+	for _, record := range records {
+		activatedAt, ok := updates.pendingActivation(
+			record.Type,
+			gethcommon.HexToAddress(record.AvsAddress),
+			record.OperatorSetId,
+			now,
+		)
+		if ok {
+			pending = append(pending, fmt.Sprintf(
+				"%s (activates at %s UTC)",
+				describeSplitRecord(record),
+				time.Unix(int64(activatedAt), 0).UTC().Format(time.DateTime),
+			))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("splits with a pending update can't be updated again: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func describeSplitRecord(record splitRecord) string {
+	switch record.Type {
+	case PISplitType:
+		return "programmatic incentives split"
+	case AVSSplitType:
+		return fmt.Sprintf("split for AVS %s", record.AvsAddress)
+	default:
+		return fmt.Sprintf("split for operator set %s/%d", record.AvsAddress, record.OperatorSetId)
+	}
+}
+
+func printSplitRecords(records []splitRecord) {
+	fmt.Println()
+	fmt.Println("Split updates to be queued")
+	for _, record := range records {
+		fmt.Printf("  - %s: %d bips\n", describeSplitRecord(record), record.Split)
+	}
+	fmt.Println()
+}
+
+func parseSplitsCSV(filePath string) ([]splitRecord, error) {
+	var records []splitRecord
+	file, err := os.OpenFile(filePath, os.O_RDONLY, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if err := gocsv.UnmarshalFile(file, &records); err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		records[i].Type = strings.ToLower(strings.TrimSpace(records[i].Type))
+		records[i].AvsAddress = strings.TrimSpace(records[i].AvsAddress)
+	}
+	return records, nil
+}
+
+func validateSplitRecords(records []splitRecord) error {
+	if len(records) == 0 {
+		return errors.New("no split updates found in the CSV file")
+	}
+
+	seen := make(map[string]bool)
+	for i, record := range records {
+		line := i + 2 // account for the header row
+		if record.Split > maxSplitBips {
+			return fmt.Errorf("line %d: split %d must be between 0 and %d bips", line, record.Split, maxSplitBips)
+		}
+
+		var key string
+		switch record.Type {
+		case PISplitType:
+			key = PISplitType
+		case AVSSplitType, OperatorSetSplitType:
+			if !gethcommon.IsHexAddress(record.AvsAddress) {
+				return fmt.Errorf("line %d: invalid AVS address '%s'", line, record.AvsAddress)
+			}
+			key = fmt.Sprintf("%s-%s", record.Type, gethcommon.HexToAddress(record.AvsAddress).Hex())
+			if record.Type == OperatorSetSplitType {
+				key = fmt.Sprintf("%s-%d", key, record.OperatorSetId)
+			}
+		default:
+			return fmt.Errorf(
+				"line %d: invalid split type '%s', must be one of '%s', '%s' or '%s'",
+				line,
+				record.Type,
+				PISplitType,
+				AVSSplitType,
+				OperatorSetSplitType,
+			)
+		}
+
+		if seen[key] {
+			return fmt.Errorf("line %d: duplicate %s", line, describeSplitRecord(record))
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func readAndValidateSetSplitsConfig(cCtx *cli.Context, logger logging.Logger) (*setSplitsConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
+	isSilent := cCtx.Bool(flags.SilentFlag.Name)
+	safeBatchFile := cCtx.String(flags.SafeBatchFileFlag.Name)
+
+	operatorAddressString := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddressString) {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+	operatorAddress := gethcommon.HexToAddress(operatorAddressString)
+	callerAddress := common.PopulateCallerAddress(cCtx, logger, operatorAddress, operatorAddressString)
+
+	csvFilePath := cCtx.String(SplitsFileFlag.Name)
+	splits, err := parseSplitsCSV(csvFilePath)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to parse splits CSV file", err)
+	}
+	if err := validateSplitRecords(splits); err != nil {
+		return nil, err
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	// Get signerConfig
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		// We don't want to throw error since people can still use it to generate the calldata
+		// without broadcasting it
+		logger.Debugf("Failed to get signer config: %s", err)
+	}
+
+	rewardsCoordinatorAddress, delegationManagerAddress, err := getContractAddresses(cCtx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &setSplitsConfig{
+		network:                   network,
+		rpcUrl:                    rpcUrl,
+		chainID:                   chainID,
+		signerConfig:              signerConfig,
+		broadcast:                 broadcast,
+		output:                    output,
+		outputType:                outputType,
+		safeBatchFile:             safeBatchFile,
+		isSilent:                  isSilent,
+		operatorAddress:           operatorAddress,
+		callerAddress:             callerAddress,
+		rewardsCoordinatorAddress: rewardsCoordinatorAddress,
+		delegationManagerAddress:  delegationManagerAddress,
+		splits:                    splits,
+	}, nil
+}
+
+func getSetFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.BroadcastFlag,
+		&flags.VerboseFlag,
+		&flags.SilentFlag,
+		&flags.OperatorAddressFlag,
+		&flags.CallerAddressFlag,
+		&SplitsFileFlag,
+		&flags.DelegationManagerAddressFlag,
+		&flags.SafeBatchFileFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+	}
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
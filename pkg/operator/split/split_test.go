@@ -0,0 +1,201 @@
+package split
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeElChainReader struct {
+	piSplit           uint16
+	avsSplits         map[gethcommon.Address]uint16
+	operatorSetSplits map[rewardscoordinator.OperatorSet]uint16
+	registeredSets    []allocationmanager.OperatorSet
+}
+
+func (f *fakeElChainReader) GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error) {
+	return f.piSplit, nil
+}
+
+func (f *fakeElChainReader) GetOperatorAVSSplit(
+	ctx context.Context,
+	operator gethcommon.Address,
+	avs gethcommon.Address,
+) (uint16, error) {
+	return f.avsSplits[avs], nil
+}
+
+func (f *fakeElChainReader) GetOperatorSetSplit(
+	ctx context.Context,
+	operator gethcommon.Address,
+	operatorSet rewardscoordinator.OperatorSet,
+) (uint16, error) {
+	return f.operatorSetSplits[operatorSet], nil
+}
+
+func (f *fakeElChainReader) GetRegisteredSets(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func TestParseAndValidateSplitsCSV(t *testing.T) {
+	tests := []struct {
+		name            string
+		filePath        string
+		expectedRecords []splitRecord
+		expectError     bool
+	}{
+		{
+			name:     "valid splits file",
+			filePath: "testdata/splits.csv",
+			expectedRecords: []splitRecord{
+				{Type: PISplitType, Split: 1000},
+				{Type: AVSSplitType, AvsAddress: "0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f", Split: 500},
+				{
+					Type:          OperatorSetSplitType,
+					AvsAddress:    "0x2222AAC0C980Cc029624b7ff55B88Bc6F63C538f",
+					OperatorSetId: 1,
+					Split:         2000,
+				},
+			},
+		},
+		{
+			name:        "invalid split type",
+			filePath:    "testdata/splits_invalid_type.csv",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := parseSplitsCSV(tt.filePath)
+			assert.NoError(t, err)
+			err = validateSplitRecords(records)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRecords, records)
+		})
+	}
+}
+
+func TestValidateSplitRecords(t *testing.T) {
+	avsAddress := testutils.GenerateRandomEthereumAddressString()
+	tests := []struct {
+		name        string
+		records     []splitRecord
+		expectError bool
+	}{
+		{
+			name:        "empty records",
+			records:     []splitRecord{},
+			expectError: true,
+		},
+		{
+			name:        "split above 10000 bips",
+			records:     []splitRecord{{Type: PISplitType, Split: 10_001}},
+			expectError: true,
+		},
+		{
+			name:        "invalid avs address",
+			records:     []splitRecord{{Type: AVSSplitType, AvsAddress: "0x1234", Split: 100}},
+			expectError: true,
+		},
+		{
+			name: "duplicate operator set split",
+			records: []splitRecord{
+				{Type: OperatorSetSplitType, AvsAddress: avsAddress, OperatorSetId: 1, Split: 100},
+				{Type: OperatorSetSplitType, AvsAddress: avsAddress, OperatorSetId: 1, Split: 200},
+			},
+			expectError: true,
+		},
+		{
+			name: "same AVS for avs and operator set splits",
+			records: []splitRecord{
+				{Type: AVSSplitType, AvsAddress: avsAddress, Split: 100},
+				{Type: OperatorSetSplitType, AvsAddress: avsAddress, OperatorSetId: 1, Split: 200},
+				{Type: OperatorSetSplitType, AvsAddress: avsAddress, OperatorSetId: 2, Split: 200},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSplitRecords(tt.records)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetOperatorSplits(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	registeredAvs := gethcommon.HexToAddress("0x1111111111111111111111111111111111111111")
+	splitOnlyAvs := gethcommon.HexToAddress("0x2222222222222222222222222222222222222222")
+	registeredSet := rewardscoordinator.OperatorSet{Avs: registeredAvs, Id: 1}
+
+	reader := &fakeElChainReader{
+		piSplit:           1000,
+		avsSplits:         map[gethcommon.Address]uint16{registeredAvs: 500, splitOnlyAvs: 700},
+		operatorSetSplits: map[rewardscoordinator.OperatorSet]uint16{registeredSet: 300},
+		registeredSets:    []allocationmanager.OperatorSet{{Avs: registeredAvs, Id: 1}},
+	}
+	updates := newSplitUpdates()
+	updates.pi = &splitUpdate{newSplit: 1000, activatedAt: 50}
+	updates.avs[splitOnlyAvs] = splitUpdate{newSplit: 900, activatedAt: 200}
+	updates.operatorSets[registeredSet] = splitUpdate{newSplit: 400, activatedAt: 150}
+
+	splits, err := getOperatorSplits(context.Background(), reader, operatorAddress, updates, 100)
+	assert.NoError(t, err)
+
+	pendingAvsSplit := uint16(900)
+	pendingAvsActivation := uint32(200)
+	pendingOpSetSplit := uint16(400)
+	pendingOpSetActivation := uint32(150)
+	opSetId := uint32(1)
+	assert.Equal(t, []OperatorSplit{
+		{Type: PISplitType, CurrentSplit: 1000},
+		{Type: AVSSplitType, AvsAddress: registeredAvs.Hex(), CurrentSplit: 500},
+		{
+			Type:         AVSSplitType,
+			AvsAddress:   splitOnlyAvs.Hex(),
+			CurrentSplit: 700,
+			PendingSplit: &pendingAvsSplit,
+			ActivatedAt:  &pendingAvsActivation,
+		},
+		{
+			Type:          OperatorSetSplitType,
+			AvsAddress:    registeredAvs.Hex(),
+			OperatorSetId: &opSetId,
+			CurrentSplit:  300,
+			PendingSplit:  &pendingOpSetSplit,
+			ActivatedAt:   &pendingOpSetActivation,
+		},
+	}, splits)
+}
+
+func TestCheckNoPendingSplits(t *testing.T) {
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	updates := newSplitUpdates()
+	updates.avs[avsAddress] = splitUpdate{newSplit: 900, activatedAt: 200}
+
+	records := []splitRecord{{Type: AVSSplitType, AvsAddress: avsAddress.Hex(), Split: 100}}
+	assert.Error(t, checkNoPendingSplits(records, updates, 100))
+	assert.NoError(t, checkNoPendingSplits(records, updates, 200))
+	assert.NoError(t, checkNoPendingSplits([]splitRecord{{Type: PISplitType, Split: 100}}, updates, 100))
+}
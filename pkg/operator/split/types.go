@@ -34,3 +34,49 @@ type GetOperatorAVSSplitConfig struct {
 	AVSAddress      gethcommon.Address
 	OperatorSetId   int
 }
+
+type OperatorSplit struct {
+	Type          string  `json:"type"`
+	AvsAddress    string  `json:"avs_address,omitempty"`
+	OperatorSetId *uint32 `json:"operator_set_id,omitempty"`
+	CurrentSplit  uint16  `json:"current_split_bips"`
+	PendingSplit  *uint16 `json:"pending_split_bips,omitempty"`
+	ActivatedAt   *uint32 `json:"activated_at,omitempty"`
+}
+
+type listSplitsConfig struct {
+	network                   string
+	rpcUrl                    string
+	chainID                   *big.Int
+	output                    string
+	outputType                string
+	operatorAddress           gethcommon.Address
+	rewardsCoordinatorAddress gethcommon.Address
+	delegationManagerAddress  gethcommon.Address
+}
+
+// splitRecord is a row of the splits CSV file. avs_address is ignored for the 'pi' type
+// and operator_set_id is only used for the 'operatorset' type.
+type splitRecord struct {
+	Type          string `csv:"type"`
+	AvsAddress    string `csv:"avs_address"`
+	OperatorSetId uint32 `csv:"operator_set_id"`
+	Split         uint16 `csv:"split"`
+}
+
+type setSplitsConfig struct {
+	network                   string
+	rpcUrl                    string
+	chainID                   *big.Int
+	signerConfig              *types.SignerConfig
+	broadcast                 bool
+	output                    string
+	outputType                string
+	safeBatchFile             string
+	isSilent                  bool
+	operatorAddress           gethcommon.Address
+	callerAddress             gethcommon.Address
+	rewardsCoordinatorAddress gethcommon.Address
+	delegationManagerAddress  gethcommon.Address
+	splits                    []splitRecord
+}
@@ -0,0 +1,20 @@
+package operator
+
+import (
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/split"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+func SplitsCmd(p utils.Prompter) *cli.Command {
+	var splitsCmd = &cli.Command{
+		Name:  "splits",
+		Usage: "Rewards split overview and bulk split updates for operators",
+		Subcommands: []*cli.Command{
+			split.ListCmd(p),
+			split.SetCmd(p),
+		},
+	}
+
+	return splitsCmd
+}
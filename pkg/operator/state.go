@@ -0,0 +1,15 @@
+package operator
+
+import (
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/state"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+func PlanCmd(p utils.Prompter) *cli.Command {
+	return state.PlanCmd(p)
+}
+
+func ApplyCmd(p utils.Prompter) *cli.Command {
+	return state.ApplyCmd(p)
+}
@@ -0,0 +1,229 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/user/appointee"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func ApplyCmd(p utils.Prompter) *cli.Command {
+	applyCmd := &cli.Command{
+		Name:      "apply",
+		Usage:     "Apply the actions required to move the operator to the state described in an operator state file",
+		UsageText: "apply --file <operator-state.yaml> [flags]",
+		Description: `
+Computes the same plan as 'operator plan' and executes it action by action with the --broadcast flag.
+Without --broadcast, the unsigned transactions are printed as calldata or, with --safe-batch-file,
+written as a Safe Transaction Builder batch which can be imported in the Safe web app.
+
+Registering for operator sets requires --bls-private-key and a registry_coordinator_address in the state file.
+		`,
+		Flags: getApplyFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return applyAction(cCtx, p)
+		},
+	}
+
+	return applyCmd
+}
+
+func applyAction(cCtx *cli.Context, p utils.Prompter) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateStateConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate apply config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	plan, err := computePlanFromChain(ctx, ethClient, config, logger)
+	if err != nil {
+		return err
+	}
+
+	if config.outputType != utils.CallDataOutputType || config.broadcast {
+		plan.PrintPretty()
+	}
+	if len(plan.Actions) == 0 {
+		return nil
+	}
+
+	elConfig := elcontracts.Config{
+		DelegationManagerAddress:    config.delegationManagerAddress,
+		RewardsCoordinatorAddress:   config.rewardsCoordinatorAddress,
+		PermissionControllerAddress: config.permissionControllerAddress,
+	}
+
+	if config.broadcast {
+		if config.signerConfig == nil {
+			return errors.New("signer is required for broadcasting")
+		}
+		confirm, err := p.Confirm(
+			fmt.Sprintf("This will send %d transaction(s) to apply the plan. Do you want to continue?", len(plan.Actions)),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			logger.Info("Operation cancelled")
+			return nil
+		}
+
+		eLWriter, err := common.GetELWriter(
+			config.callerAddress,
+			config.signerConfig,
+			ethClient,
+			elConfig,
+			p,
+			config.chainID,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get EL writer", err)
+		}
+
+		for _, action := range plan.Actions {
+			logger.Infof("Applying step %d: %s", action.Step, action.Description)
+			receipt, err := action.send(ctx, eLWriter, config.blsKeyPair)
+			if err != nil {
+				// Actions already applied are picked up by the next plan, so
+				// re-running apply continues from the failed step.
+				return eigenSdkUtils.WrapError(fmt.Sprintf("failed to apply step %d (%s)", action.Step, action.Type), err)
+			}
+			common.PrintTransactionInfo(receipt.TxHash.String(), config.chainID)
+		}
+		return nil
+	}
+
+	noSendTxOpts := common.GetNoSendTxOpts(config.callerAddress)
+	_, _, contractBindings, err := elcontracts.BuildClients(elConfig, ethClient, nil, logger, nil)
+	if err != nil {
+		return err
+	}
+	// If caller is a smart contract, we can't estimate gas using geth
+	// since balance of contract can be 0, as it can be called by an EOA
+	// to claim. So we hardcode the gas limit to 150_000 so that we can
+	// create unsigned tx without gas limit estimation from contract bindings
+	if common.IsSmartContractAddress(config.callerAddress, ethClient) {
+		// address is a smart contract
+		noSendTxOpts.GasLimit = 150_000
+	}
+
+	builder := &txBuilder{
+		ContractBindings: contractBindings,
+		backend:          ethClient,
+		blsKeyPair:       config.blsKeyPair,
+	}
+	unsignedTxs := make([]*gethtypes.Transaction, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		unsignedTx, err := action.build(ctx, noSendTxOpts, builder)
+		if err != nil {
+			return eigenSdkUtils.WrapError(
+				fmt.Sprintf("failed to create unsigned tx for step %d (%s)", action.Step, action.Type),
+				err,
+			)
+		}
+		unsignedTxs = append(unsignedTxs, unsignedTx)
+	}
+
+	if !common.IsEmptyString(config.safeBatchFile) {
+		batch := common.NewSafeBatch(
+			config.chainID,
+			config.callerAddress,
+			"Operator state update",
+			fmt.Sprintf("Apply %s for operator %s", config.stateFile, config.operatorAddress.Hex()),
+		)
+		for _, unsignedTx := range unsignedTxs {
+			batch.AddTransaction(unsignedTx)
+		}
+		if err := batch.WriteToFile(config.safeBatchFile); err != nil {
+			return err
+		}
+		logger.Infof("Safe transaction batch written to file: %s", config.safeBatchFile)
+		return nil
+	}
+
+	if config.outputType == utils.CallDataOutputType {
+		lines := make([]string, 0, len(unsignedTxs))
+		for i, unsignedTx := range unsignedTxs {
+			lines = append(lines, fmt.Sprintf(
+				"%d,%s,%s,%s",
+				plan.Actions[i].Step,
+				plan.Actions[i].Type,
+				unsignedTx.To().Hex(),
+				gethcommon.Bytes2Hex(unsignedTx.Data()),
+			))
+		}
+		calldata := strings.Join(lines, "\n")
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile([]byte(calldata), config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Call data written to file: %s", config.output)
+		} else {
+			fmt.Println(calldata)
+		}
+	} else if !common.IsEmptyString(config.output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+
+	if !config.isSilent {
+		for i, unsignedTx := range unsignedTxs {
+			fmt.Println()
+			fmt.Printf("Step %d (%s)\n", plan.Actions[i].Step, plan.Actions[i].Type)
+			common.GetTxFeeDetails(unsignedTx).Print()
+		}
+		fmt.Println("To broadcast the transactions, use the --broadcast flag")
+	}
+	return nil
+}
+
+func getApplyFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.BroadcastFlag,
+		&flags.VerboseFlag,
+		&flags.SilentFlag,
+		&flags.OperatorAddressFlag,
+		&flags.CallerAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+		&flags.BlsPrivateKeyFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&appointee.PermissionControllerAddressFlag,
+		&StateFileFlag,
+		&flags.SafeBatchFileFlag,
+	}
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
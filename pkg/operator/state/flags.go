@@ -0,0 +1,13 @@
+package state
+
+import "github.com/urfave/cli/v2"
+
+var (
+	StateFileFlag = cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "Path to the operator state YAML file describing the desired state of the operator",
+		Required: true,
+		EnvVars:  []string{"OPERATOR_STATE_FILE"},
+	}
+)
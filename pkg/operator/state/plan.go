@@ -0,0 +1,918 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/keys"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/allocations"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/registrar"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/rewards"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/user/appointee"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+// metadataURILookbackBlocks is the number of blocks searched for the current metadata URI of the operator, about
+// a month of mainnet blocks. An older URI is reported as unknown and an update is planned.
+const metadataURILookbackBlocks = 216_000
+
+type elChainReader interface {
+	GetOperatorDetails(ctx context.Context, operator eigensdkTypes.Operator) (eigensdkTypes.Operator, error)
+	GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error)
+	GetOperatorAVSSplit(ctx context.Context, operator gethcommon.Address, avs gethcommon.Address) (uint16, error)
+	GetOperatorSetSplit(
+		ctx context.Context,
+		operator gethcommon.Address,
+		operatorSet rewardscoordinator.OperatorSet,
+	) (uint16, error)
+	GetMaxMagnitudes(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddresses []gethcommon.Address,
+	) ([]uint64, error)
+	GetAllocationInfo(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		strategyAddress gethcommon.Address,
+	) ([]elcontracts.AllocationInfo, error)
+	GetRegisteredSets(ctx context.Context, operatorAddress gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	IsOperatorSlashable(
+		ctx context.Context,
+		operatorAddress gethcommon.Address,
+		operatorSet allocationmanager.OperatorSet,
+	) (bool, error)
+	ListAppointeePermissions(
+		ctx context.Context,
+		accountAddress gethcommon.Address,
+		appointeeAddress gethcommon.Address,
+	) ([]gethcommon.Address, [][4]byte, error)
+}
+
+func PlanCmd(p utils.Prompter) *cli.Command {
+	planCmd := &cli.Command{
+		Name:      "plan",
+		Usage:     "Show the actions required to move the operator to the state described in an operator state file",
+		UsageText: "plan --file <operator-state.yaml> [flags]",
+		Description: `
+Reads the desired operator state (metadata URI, delegation approver, allocation delay, splits,
+operator set registrations, allocations and appointee permissions) from a YAML file, compares it
+with the current onchain state and prints the ordered list of actions 'operator apply' would execute.
+		`,
+		Flags: getPlanFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return planAction(cCtx)
+		},
+	}
+
+	return planCmd
+}
+
+func planAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateStateConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate plan config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	plan, err := computePlanFromChain(ctx, ethClient, config, logger)
+	if err != nil {
+		return err
+	}
+
+	if config.outputType == utils.JsonOutputType {
+		planJson, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.output) {
+			err = common.WriteToFile(planJson, config.output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Plan written to file: %s", config.output)
+		} else {
+			fmt.Println(string(planJson))
+		}
+		return nil
+	}
+
+	plan.PrintPretty()
+	return nil
+}
+
+func computePlanFromChain(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	config *stateConfig,
+	logger logging.Logger,
+) (*Plan, error) {
+	elConfig := elcontracts.Config{
+		DelegationManagerAddress:    config.delegationManagerAddress,
+		RewardsCoordinatorAddress:   config.rewardsCoordinatorAddress,
+		PermissionControllerAddress: config.permissionControllerAddress,
+	}
+	elReader, err := elcontracts.NewReaderFromConfig(elConfig, ethClient, logger)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	currentMetadataURI := ""
+	if !common.IsEmptyString(config.desiredState.MetadataURI) {
+		_, _, contractBindings, err := elcontracts.BuildClients(elConfig, ethClient, nil, logger, nil)
+		if err != nil {
+			return nil, err
+		}
+		latestBlock, err := ethClient.BlockNumber(ctx)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get latest block number", err)
+		}
+		currentMetadataURI, err = getCurrentMetadataURI(ctx, contractBindings, config.operatorAddress, latestBlock)
+		if err != nil {
+			// If we can't tell what the current URI is, we always plan an update.
+			logger.Warnf("Failed to fetch current metadata URI: %s", err)
+		}
+		if common.IsEmptyString(currentMetadataURI) {
+			logger.Debugf(
+				"No metadata URI update in the last %d blocks, planning an update",
+				metadataURILookbackBlocks,
+			)
+		}
+	}
+
+	return buildPlan(ctx, elReader, config.desiredState, config.operatorAddress, currentMetadataURI, logger)
+}
+
+// getCurrentMetadataURI returns the metadata URI of the latest OperatorMetadataURIUpdated event of the operator
+// within the last metadataURILookbackBlocks blocks. The metadata URI is only stored in events, so the logs are
+// read newest first and the scan stops at the first range with an update.
+func getCurrentMetadataURI(
+	ctx context.Context,
+	contractBindings *elcontracts.ContractBindings,
+	operatorAddress gethcommon.Address,
+	latestBlock uint64,
+) (string, error) {
+	metadataURI := ""
+	err := common.FilterLatestLogsInRanges(
+		ctx,
+		common.LookbackStartBlock(latestBlock, metadataURILookbackBlocks),
+		latestBlock,
+		func(opts *bind.FilterOpts) (bool, error) {
+			iterator, err := contractBindings.DelegationManager.FilterOperatorMetadataURIUpdated(
+				opts,
+				[]gethcommon.Address{operatorAddress},
+			)
+			if err != nil {
+				return false, err
+			}
+			defer iterator.Close()
+
+			found := false
+			for iterator.Next() {
+				metadataURI = iterator.Event.MetadataURI
+				found = true
+			}
+			return found, iterator.Error()
+		},
+	)
+	return metadataURI, err
+}
+
+// buildPlan diffs the desired state against the chain and returns the ordered list of actions.
+// Actions are ordered so that each one is valid after the previous ones have landed: operator
+// details first, then registrations, allocation changes with deallocations first, deregistrations once
+// stake has been moved, and finally splits and appointee permissions.
+func buildPlan(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	currentMetadataURI string,
+	logger logging.Logger,
+) (*Plan, error) {
+	plan := &Plan{
+		OperatorAddress: operatorAddress,
+		Actions:         make([]*Action, 0),
+	}
+
+	err := planOperatorDetails(ctx, elReader, desiredState, operatorAddress, currentMetadataURI, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	deregistrations, err := planRegistrations(ctx, elReader, desiredState, operatorAddress, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = planAllocations(ctx, elReader, desiredState, operatorAddress, plan, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Actions = append(plan.Actions, deregistrations...)
+
+	err = planSplits(ctx, elReader, desiredState, operatorAddress, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = planAppointees(ctx, elReader, desiredState, operatorAddress, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, action := range plan.Actions {
+		action.Step = i + 1
+	}
+	return plan, nil
+}
+
+func planOperatorDetails(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	currentMetadataURI string,
+	plan *Plan,
+) error {
+	if common.IsEmptyString(desiredState.DelegationApprover) && desiredState.AllocationDelay == nil &&
+		common.IsEmptyString(desiredState.MetadataURI) {
+		return nil
+	}
+
+	details, err := elReader.GetOperatorDetails(ctx, eigensdkTypes.Operator{Address: operatorAddress.Hex()})
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get operator details", err)
+	}
+
+	if !common.IsEmptyString(desiredState.DelegationApprover) {
+		approver := gethcommon.HexToAddress(desiredState.DelegationApprover)
+		if approver != gethcommon.HexToAddress(details.DelegationApproverAddress) {
+			plan.Actions = append(plan.Actions, &Action{
+				Type: UpdateDelegationApproverAction,
+				Description: fmt.Sprintf(
+					"Update delegation approver from %s to %s",
+					details.DelegationApproverAddress,
+					approver.Hex(),
+				),
+				send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+					return w.UpdateOperatorDetails(ctx, eigensdkTypes.Operator{
+						Address:                   operatorAddress.Hex(),
+						DelegationApproverAddress: approver.Hex(),
+					}, true)
+				},
+				build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+					return b.DelegationManager.ModifyOperatorDetails(opts, operatorAddress, approver)
+				},
+			})
+		}
+	}
+
+	if !common.IsEmptyString(desiredState.MetadataURI) && desiredState.MetadataURI != currentMetadataURI {
+		metadataURI := desiredState.MetadataURI
+		current := currentMetadataURI
+		if common.IsEmptyString(current) {
+			current = "unknown"
+		}
+		plan.Actions = append(plan.Actions, &Action{
+			Type:        UpdateMetadataURIAction,
+			Description: fmt.Sprintf("Update metadata URI from %s to %s", current, metadataURI),
+			send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+				return w.UpdateMetadataURI(ctx, operatorAddress, metadataURI, true)
+			},
+			build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+				return b.DelegationManager.UpdateOperatorMetadataURI(opts, operatorAddress, metadataURI)
+			},
+		})
+	}
+
+	if desiredState.AllocationDelay != nil && *desiredState.AllocationDelay != details.AllocationDelay {
+		delay := *desiredState.AllocationDelay
+		plan.Actions = append(plan.Actions, &Action{
+			Type: SetAllocationDelayAction,
+			Description: fmt.Sprintf(
+				"Set allocation delay from %d to %d blocks",
+				details.AllocationDelay,
+				delay,
+			),
+			send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+				return w.SetAllocationDelay(ctx, operatorAddress, delay, true)
+			},
+			build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+				return b.AllocationManager.SetAllocationDelay(opts, operatorAddress, delay)
+			},
+		})
+	}
+
+	return nil
+}
+
+// planRegistrations appends the registrations to the plan and returns the deregistrations
+// separately, since those have to happen after stake is deallocated.
+func planRegistrations(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	plan *Plan,
+) ([]*Action, error) {
+	deregistrations := make([]*Action, 0)
+	if len(desiredState.OperatorSets) == 0 {
+		return deregistrations, nil
+	}
+
+	registeredSets, err := elReader.GetRegisteredSets(ctx, operatorAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+	registered := make(map[gethcommon.Address]map[uint32]bool)
+	for _, opSet := range registeredSets {
+		if _, ok := registered[opSet.Avs]; !ok {
+			registered[opSet.Avs] = make(map[uint32]bool)
+		}
+		registered[opSet.Avs][opSet.Id] = true
+	}
+
+	for _, desired := range desiredState.OperatorSets {
+		avsAddress := gethcommon.HexToAddress(desired.AvsAddress)
+		registryCoordinatorAddress := gethcommon.HexToAddress(desired.RegistryCoordinatorAddress)
+
+		desiredIds := make(map[uint32]bool)
+		toRegister := make([]uint32, 0)
+		for _, id := range desired.OperatorSetIds {
+			desiredIds[id] = true
+			if !registered[avsAddress][id] {
+				toRegister = append(toRegister, id)
+			}
+		}
+		toDeregister := make([]uint32, 0)
+		for id := range registered[avsAddress] {
+			if !desiredIds[id] {
+				toDeregister = append(toDeregister, id)
+			}
+		}
+		sort.Slice(toDeregister, func(i, j int) bool { return toDeregister[i] < toDeregister[j] })
+
+		if len(toRegister) > 0 {
+			plan.Actions = append(plan.Actions, &Action{
+				Type:        RegisterOperatorSetsAction,
+				Description: fmt.Sprintf("Register for operator sets %v of AVS %s", toRegister, avsAddress.Hex()),
+				send: func(ctx context.Context, w *elcontracts.ChainWriter, blsKeyPair *bls.KeyPair) (*gethtypes.Receipt, error) {
+					if err := validateRegistration(avsAddress, registryCoordinatorAddress, blsKeyPair); err != nil {
+						return nil, err
+					}
+					return w.RegisterForOperatorSets(ctx, registryCoordinatorAddress, elcontracts.RegistrationRequest{
+						OperatorAddress: operatorAddress,
+						AVSAddress:      avsAddress,
+						OperatorSetIds:  toRegister,
+						BlsKeyPair:      blsKeyPair,
+						WaitForReceipt:  true,
+					})
+				},
+				build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+					if err := validateRegistration(avsAddress, registryCoordinatorAddress, b.blsKeyPair); err != nil {
+						return nil, err
+					}
+					// The registry coordinator reverts without the BLS pubkey registration, so the data is
+					// built the same way as ChainWriter.RegisterForOperatorSets does when sending.
+					data, err := registrar.NewBLSRegistrationDataBuilder(b.blsKeyPair, "").Build(
+						ctx,
+						b.backend,
+						registrar.RegistrationRequest{
+							OperatorAddress:  operatorAddress,
+							AvsAddress:       avsAddress,
+							RegistrarAddress: registryCoordinatorAddress,
+							OperatorSetIds:   toRegister,
+						},
+					)
+					if err != nil {
+						return nil, eigenSdkUtils.WrapError("failed to build registration data", err)
+					}
+					return b.AllocationManager.RegisterForOperatorSets(
+						opts,
+						operatorAddress,
+						allocationmanager.IAllocationManagerTypesRegisterParams{
+							Avs:            avsAddress,
+							OperatorSetIds: toRegister,
+							Data:           data,
+						},
+					)
+				},
+			})
+		}
+
+		if len(toDeregister) > 0 {
+			deregistrations = append(deregistrations, &Action{
+				Type:        DeregisterOperatorSetsAction,
+				Description: fmt.Sprintf("Deregister from operator sets %v of AVS %s", toDeregister, avsAddress.Hex()),
+				send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+					return w.DeregisterFromOperatorSets(ctx, operatorAddress, elcontracts.DeregistrationRequest{
+						AVSAddress:     avsAddress,
+						OperatorSetIds: toDeregister,
+						WaitForReceipt: true,
+					})
+				},
+				build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+					return b.AllocationManager.DeregisterFromOperatorSets(
+						opts,
+						allocationmanager.IAllocationManagerTypesDeregisterParams{
+							Operator:       operatorAddress,
+							Avs:            avsAddress,
+							OperatorSetIds: toDeregister,
+						},
+					)
+				},
+			})
+		}
+	}
+
+	return deregistrations, nil
+}
+
+// validateRegistration checks that a registration for operator sets of the AVS can be sent or built
+func validateRegistration(
+	avsAddress gethcommon.Address,
+	registryCoordinatorAddress gethcommon.Address,
+	blsKeyPair *bls.KeyPair,
+) error {
+	if blsKeyPair == nil {
+		return fmt.Errorf("--%s flag must be set to register for operator sets", flags.BlsPrivateKeyFlag.Name)
+	}
+	if registryCoordinatorAddress == (gethcommon.Address{}) {
+		return fmt.Errorf("registry_coordinator_address must be set for AVS %s", avsAddress.Hex())
+	}
+	return nil
+}
+
+// planAllocations plans the allocation changes with the target allocations of the update command, so the
+// allocations of each listed strategy are complete and the encumbered magnitude is checked. Deallocations
+// and allocations go in a single transaction, deallocations first.
+func planAllocations(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	plan *Plan,
+	logger logging.Logger,
+) error {
+	if len(desiredState.Allocations) == 0 {
+		return nil
+	}
+
+	targets := make([]allocations.TargetAllocation, 0, len(desiredState.Allocations))
+	for _, a := range desiredState.Allocations {
+		targets = append(targets, allocations.TargetAllocation{
+			AvsAddress:      gethcommon.HexToAddress(a.AvsAddress),
+			OperatorSetId:   a.OperatorSetId,
+			StrategyAddress: gethcommon.HexToAddress(a.StrategyAddress),
+			Bips:            a.Bips,
+		})
+	}
+	params, err := allocations.ComputeTargetAllocations(ctx, elReader, operatorAddress, targets)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to plan allocations", err)
+	}
+	logger.Debugf("Planned allocation changes for %d operator sets", len(params))
+
+	if len(params) > 0 {
+		plan.Actions = append(plan.Actions, newModifyAllocationsAction(operatorAddress, params))
+	}
+	return nil
+}
+
+func newModifyAllocationsAction(
+	operatorAddress gethcommon.Address,
+	params []allocationmanager.IAllocationManagerTypesAllocateParams,
+) *Action {
+	descriptions := make([]string, 0)
+	for _, p := range params {
+		for i, strategy := range p.Strategies {
+			descriptions = append(descriptions, fmt.Sprintf(
+				"%s/%d %s -> %d",
+				common.ShortEthAddress(p.OperatorSet.Avs),
+				p.OperatorSet.Id,
+				common.ShortEthAddress(strategy),
+				p.NewMagnitudes[i],
+			))
+		}
+	}
+	return &Action{
+		Type:        ModifyAllocationsAction,
+		Description: fmt.Sprintf("Modify allocations: %s", strings.Join(descriptions, ", ")),
+		send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+			return w.ModifyAllocations(ctx, operatorAddress, params, true)
+		},
+		build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+			return b.AllocationManager.ModifyAllocations(opts, operatorAddress, params)
+		},
+	}
+}
+
+func planSplits(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	plan *Plan,
+) error {
+	splits := desiredState.Splits
+	if splits == nil {
+		return nil
+	}
+
+	if splits.PI != nil {
+		currentSplit, err := elReader.GetOperatorPISplit(ctx, operatorAddress)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get operator PI split", err)
+		}
+		desiredSplit := *splits.PI
+		if currentSplit != desiredSplit {
+			plan.Actions = append(plan.Actions, &Action{
+				Type:        SetPISplitAction,
+				Description: fmt.Sprintf("Set programmatic incentives split from %d to %d bips", currentSplit, desiredSplit),
+				send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+					return w.SetOperatorPISplit(ctx, operatorAddress, desiredSplit, true)
+				},
+				build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+					return b.RewardsCoordinator.SetOperatorPISplit(opts, operatorAddress, desiredSplit)
+				},
+			})
+		}
+	}
+
+	for _, s := range splits.AVS {
+		avsAddress := gethcommon.HexToAddress(s.AvsAddress)
+		desiredSplit := s.Split
+		currentSplit, err := elReader.GetOperatorAVSSplit(ctx, operatorAddress, avsAddress)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get operator AVS split", err)
+		}
+		if currentSplit == desiredSplit {
+			continue
+		}
+		plan.Actions = append(plan.Actions, &Action{
+			Type: SetAVSSplitAction,
+			Description: fmt.Sprintf(
+				"Set split for AVS %s from %d to %d bips",
+				avsAddress.Hex(),
+				currentSplit,
+				desiredSplit,
+			),
+			send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+				return w.SetOperatorAVSSplit(ctx, operatorAddress, avsAddress, desiredSplit, true)
+			},
+			build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+				return b.RewardsCoordinator.SetOperatorAVSSplit(opts, operatorAddress, avsAddress, desiredSplit)
+			},
+		})
+	}
+
+	for _, s := range splits.OperatorSets {
+		operatorSet := rewardscoordinator.OperatorSet{Avs: gethcommon.HexToAddress(s.AvsAddress), Id: s.OperatorSetId}
+		desiredSplit := s.Split
+		currentSplit, err := elReader.GetOperatorSetSplit(ctx, operatorAddress, operatorSet)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get operator set split", err)
+		}
+		if currentSplit == desiredSplit {
+			continue
+		}
+		plan.Actions = append(plan.Actions, &Action{
+			Type: SetOperatorSetSplitAction,
+			Description: fmt.Sprintf(
+				"Set split for operator set %s/%d from %d to %d bips",
+				operatorSet.Avs.Hex(),
+				operatorSet.Id,
+				currentSplit,
+				desiredSplit,
+			),
+			send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+				return w.SetOperatorSetSplit(ctx, operatorAddress, operatorSet, desiredSplit, true)
+			},
+			build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+				return b.RewardsCoordinator.SetOperatorSetSplit(opts, operatorAddress, operatorSet, desiredSplit)
+			},
+		})
+	}
+
+	return nil
+}
+
+type permission struct {
+	target   gethcommon.Address
+	selector [4]byte
+}
+
+func planAppointees(
+	ctx context.Context,
+	elReader elChainReader,
+	desiredState *OperatorState,
+	operatorAddress gethcommon.Address,
+	plan *Plan,
+) error {
+	removals := make([]*Action, 0)
+	for _, appointeeState := range desiredState.Appointees {
+		appointee := gethcommon.HexToAddress(appointeeState.AppointeeAddress)
+		targets, selectors, err := elReader.ListAppointeePermissions(ctx, operatorAddress, appointee)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to list appointee permissions", err)
+		}
+		current := make(map[permission]bool)
+		for i := range targets {
+			current[permission{target: targets[i], selector: selectors[i]}] = true
+		}
+
+		desired := make(map[permission]bool)
+		for _, p := range appointeeState.Permissions {
+			selector, err := common.ValidateAndConvertSelectorString(p.Selector)
+			if err != nil {
+				return eigenSdkUtils.WrapError(fmt.Sprintf("invalid selector for appointee %s", appointee.Hex()), err)
+			}
+			perm := permission{target: gethcommon.HexToAddress(p.Target), selector: selector}
+			desired[perm] = true
+			if current[perm] {
+				continue
+			}
+			plan.Actions = append(plan.Actions, newAppointeeAction(SetAppointeeAction, operatorAddress, appointee, perm))
+		}
+
+		for i := range targets {
+			perm := permission{target: targets[i], selector: selectors[i]}
+			if desired[perm] {
+				continue
+			}
+			removals = append(removals, newAppointeeAction(RemoveAppointeeAction, operatorAddress, appointee, perm))
+		}
+	}
+	plan.Actions = append(plan.Actions, removals...)
+	return nil
+}
+
+func newAppointeeAction(
+	actionType ActionType,
+	operatorAddress gethcommon.Address,
+	appointee gethcommon.Address,
+	perm permission,
+) *Action {
+	if actionType == RemoveAppointeeAction {
+		return &Action{
+			Type: RemoveAppointeeAction,
+			Description: fmt.Sprintf(
+				"Remove permission of appointee %s to call 0x%x on %s",
+				appointee.Hex(),
+				perm.selector,
+				perm.target.Hex(),
+			),
+			send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+				return w.RemovePermission(ctx, elcontracts.RemovePermissionRequest{
+					AccountAddress:   operatorAddress,
+					AppointeeAddress: appointee,
+					Target:           perm.target,
+					Selector:         perm.selector,
+					WaitForReceipt:   true,
+				})
+			},
+			build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+				return b.PermissionController.RemoveAppointee(opts, operatorAddress, appointee, perm.target, perm.selector)
+			},
+		}
+	}
+	return &Action{
+		Type: SetAppointeeAction,
+		Description: fmt.Sprintf(
+			"Allow appointee %s to call 0x%x on %s",
+			appointee.Hex(),
+			perm.selector,
+			perm.target.Hex(),
+		),
+		send: func(ctx context.Context, w *elcontracts.ChainWriter, _ *bls.KeyPair) (*gethtypes.Receipt, error) {
+			return w.SetPermission(ctx, elcontracts.SetPermissionRequest{
+				AccountAddress:   operatorAddress,
+				AppointeeAddress: appointee,
+				Target:           perm.target,
+				Selector:         perm.selector,
+				WaitForReceipt:   true,
+			})
+		},
+		build: func(ctx context.Context, opts *bind.TransactOpts, b *txBuilder) (*gethtypes.Transaction, error) {
+			return b.PermissionController.SetAppointee(opts, operatorAddress, appointee, perm.target, perm.selector)
+		},
+	}
+}
+
+func (p *Plan) PrintPretty() {
+	fmt.Println()
+	fmt.Printf("Plan for operator %s\n", p.OperatorAddress.Hex())
+	fmt.Println()
+	if len(p.Actions) == 0 {
+		fmt.Printf("%s No changes. Operator state matches the state file\n", utils.EmojiCheckMark)
+	}
+	for _, action := range p.Actions {
+		fmt.Printf("%3d. [%s] %s\n", action.Step, action.Type, action.Description)
+	}
+	fmt.Println()
+	if len(p.Actions) > 0 {
+		fmt.Printf("%d action(s) to apply\n", len(p.Actions))
+	}
+}
+
+func readAndValidateStateConfig(cCtx *cli.Context, logger logging.Logger) (*stateConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(flags.EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
+	isSilent := cCtx.Bool(flags.SilentFlag.Name)
+	safeBatchFile := cCtx.String(flags.SafeBatchFileFlag.Name)
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+	if common.IsEmptyString(environment) {
+		environment = common.GetEnvFromNetwork(network)
+	}
+	logger.Debugf("Using network %s and environment: %s", network, environment)
+
+	stateFile := cCtx.String(StateFileFlag.Name)
+	var desiredState OperatorState
+	if err := utils.ReadYamlConfig(stateFile, &desiredState); err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to read operator state file", err)
+	}
+	if err := validateStateAddresses(&desiredState); err != nil {
+		return nil, eigenSdkUtils.WrapError("invalid operator state file", err)
+	}
+
+	operatorAddressString := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddressString) {
+		operatorAddressString = desiredState.OperatorAddress
+	}
+	if common.IsEmptyString(operatorAddressString) {
+		logger.Error("--operator-address flag or operator_address in the state file must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+	if !gethcommon.IsHexAddress(operatorAddressString) {
+		return nil, fmt.Errorf("invalid operator address %s", operatorAddressString)
+	}
+	operatorAddress := gethcommon.HexToAddress(operatorAddressString)
+	callerAddress := common.PopulateCallerAddress(cCtx, logger, operatorAddress, operatorAddressString)
+
+	// Get signerConfig
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		// We don't want to throw error since people can still use it to generate the plan
+		// without broadcasting it
+		logger.Debugf("Failed to get signer config: %s", err)
+	}
+
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	if common.IsEmptyString(delegationManagerAddress) {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(rewards.RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	permissionControllerAddress := cCtx.String(appointee.PermissionControllerAddressFlag.Name)
+	if common.IsEmptyString(permissionControllerAddress) {
+		permissionControllerAddress, err = common.GetPermissionControllerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var blsKeyPair *bls.KeyPair
+	blsPrivateKey := cCtx.String(flags.BlsPrivateKeyFlag.Name)
+	if !common.IsEmptyString(blsPrivateKey) {
+		blsKeyPair, err = keys.ParseBlsPrivateKey(blsPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &stateConfig{
+		network:                     network,
+		rpcUrl:                      rpcUrl,
+		environment:                 environment,
+		chainID:                     chainID,
+		stateFile:                   stateFile,
+		desiredState:                &desiredState,
+		operatorAddress:             operatorAddress,
+		callerAddress:               callerAddress,
+		delegationManagerAddress:    gethcommon.HexToAddress(delegationManagerAddress),
+		rewardsCoordinatorAddress:   gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		permissionControllerAddress: gethcommon.HexToAddress(permissionControllerAddress),
+		signerConfig:                signerConfig,
+		broadcast:                   broadcast,
+		output:                      output,
+		outputType:                  outputType,
+		safeBatchFile:               safeBatchFile,
+		isSilent:                    isSilent,
+		blsKeyPair:                  blsKeyPair,
+	}, nil
+}
+
+// validateStateAddresses checks the addresses of the state file, since HexToAddress would turn a typo into the
+// zero address and plan changes against it
+func validateStateAddresses(state *OperatorState) error {
+	type namedAddress struct {
+		name    string
+		address string
+	}
+	addresses := make([]namedAddress, 0)
+	if !common.IsEmptyString(state.OperatorAddress) {
+		addresses = append(addresses, namedAddress{"operator_address", state.OperatorAddress})
+	}
+	if !common.IsEmptyString(state.DelegationApprover) {
+		addresses = append(addresses, namedAddress{"delegation_approver", state.DelegationApprover})
+	}
+	if state.Splits != nil {
+		for _, s := range state.Splits.AVS {
+			addresses = append(addresses, namedAddress{"splits.avs.avs_address", s.AvsAddress})
+		}
+		for _, s := range state.Splits.OperatorSets {
+			addresses = append(addresses, namedAddress{"splits.operator_sets.avs_address", s.AvsAddress})
+		}
+	}
+	for _, o := range state.OperatorSets {
+		addresses = append(addresses, namedAddress{"operator_sets.avs_address", o.AvsAddress})
+		if !common.IsEmptyString(o.RegistryCoordinatorAddress) {
+			addresses = append(
+				addresses,
+				namedAddress{"operator_sets.registry_coordinator_address", o.RegistryCoordinatorAddress},
+			)
+		}
+	}
+	for _, a := range state.Allocations {
+		addresses = append(addresses, namedAddress{"allocations.avs_address", a.AvsAddress})
+		addresses = append(addresses, namedAddress{"allocations.strategy_address", a.StrategyAddress})
+	}
+	for _, a := range state.Appointees {
+		addresses = append(addresses, namedAddress{"appointees.appointee_address", a.AppointeeAddress})
+		for _, p := range a.Permissions {
+			addresses = append(addresses, namedAddress{"appointees.permissions.target", p.Target})
+		}
+	}
+
+	for _, a := range addresses {
+		if !gethcommon.IsHexAddress(a.address) {
+			return fmt.Errorf("invalid %s %q", a.name, a.address)
+		}
+	}
+	return nil
+}
+
+func getPlanFlags() []cli.Flag {
+	planFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.EnvironmentFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+		&rewards.RewardsCoordinatorAddressFlag,
+		&appointee.PermissionControllerAddressFlag,
+		&StateFileFlag,
+	}
+	sort.Sort(cli.FlagsByName(planFlags))
+	return planFlags
+}
@@ -0,0 +1,352 @@
+package state
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigensdkTypes "github.com/Layr-Labs/eigensdk-go/types"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeElChainReader struct {
+	approver          string
+	allocationDelay   uint32
+	piSplit           uint16
+	avsSplits         map[gethcommon.Address]uint16
+	operatorSetSplits map[rewardscoordinator.OperatorSet]uint16
+	maxMagnitudes     map[gethcommon.Address]uint64
+	allocationInfo    map[gethcommon.Address][]elcontracts.AllocationInfo
+	registeredSets    []allocationmanager.OperatorSet
+	permissions       map[gethcommon.Address][]permission
+	slashableSets     map[allocationmanager.OperatorSet]bool
+}
+
+func (f *fakeElChainReader) GetOperatorDetails(
+	ctx context.Context,
+	operator eigensdkTypes.Operator,
+) (eigensdkTypes.Operator, error) {
+	return eigensdkTypes.Operator{
+		Address:                   operator.Address,
+		DelegationApproverAddress: f.approver,
+		AllocationDelay:           f.allocationDelay,
+	}, nil
+}
+
+func (f *fakeElChainReader) GetOperatorPISplit(ctx context.Context, operator gethcommon.Address) (uint16, error) {
+	return f.piSplit, nil
+}
+
+func (f *fakeElChainReader) GetOperatorAVSSplit(
+	ctx context.Context,
+	operator gethcommon.Address,
+	avs gethcommon.Address,
+) (uint16, error) {
+	return f.avsSplits[avs], nil
+}
+
+func (f *fakeElChainReader) GetOperatorSetSplit(
+	ctx context.Context,
+	operator gethcommon.Address,
+	operatorSet rewardscoordinator.OperatorSet,
+) (uint16, error) {
+	return f.operatorSetSplits[operatorSet], nil
+}
+
+func (f *fakeElChainReader) GetMaxMagnitudes(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddresses []gethcommon.Address,
+) ([]uint64, error) {
+	magnitudes := make([]uint64, 0, len(strategyAddresses))
+	for _, strategy := range strategyAddresses {
+		magnitudes = append(magnitudes, f.maxMagnitudes[strategy])
+	}
+	return magnitudes, nil
+}
+
+func (f *fakeElChainReader) GetAllocationInfo(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	strategyAddress gethcommon.Address,
+) ([]elcontracts.AllocationInfo, error) {
+	return f.allocationInfo[strategyAddress], nil
+}
+
+func (f *fakeElChainReader) GetRegisteredSets(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func (f *fakeElChainReader) IsOperatorSlashable(
+	ctx context.Context,
+	operatorAddress gethcommon.Address,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	return f.slashableSets[operatorSet], nil
+}
+
+func (f *fakeElChainReader) ListAppointeePermissions(
+	ctx context.Context,
+	accountAddress gethcommon.Address,
+	appointeeAddress gethcommon.Address,
+) ([]gethcommon.Address, [][4]byte, error) {
+	targets := make([]gethcommon.Address, 0)
+	selectors := make([][4]byte, 0)
+	for _, p := range f.permissions[appointeeAddress] {
+		targets = append(targets, p.target)
+		selectors = append(selectors, p.selector)
+	}
+	return targets, selectors, nil
+}
+
+func actionTypes(plan *Plan) []ActionType {
+	types := make([]ActionType, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		types = append(types, action.Type)
+	}
+	return types
+}
+
+func TestBuildPlan(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyA := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyB := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	approver := testutils.GenerateRandomEthereumAddressString()
+	appointeeAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	target := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	piSplit := uint16(1000)
+	allocationDelay := uint32(10)
+
+	tests := []struct {
+		name          string
+		reader        *fakeElChainReader
+		desiredState  *OperatorState
+		currentURI    string
+		expectedTypes []ActionType
+	}{
+		{
+			name:          "empty state file results in no actions",
+			reader:        &fakeElChainReader{},
+			desiredState:  &OperatorState{},
+			expectedTypes: []ActionType{},
+		},
+		{
+			name: "state matching the chain results in no actions",
+			reader: &fakeElChainReader{
+				approver:        gethcommon.HexToAddress(approver).Hex(),
+				allocationDelay: allocationDelay,
+				piSplit:         piSplit,
+				registeredSets:  []allocationmanager.OperatorSet{{Avs: avsAddress, Id: 1}},
+			},
+			desiredState: &OperatorState{
+				MetadataURI:        "https://example.com/metadata.json",
+				DelegationApprover: approver,
+				AllocationDelay:    &allocationDelay,
+				Splits:             &SplitsState{PI: &piSplit},
+				OperatorSets: []OperatorSetState{
+					{AvsAddress: avsAddress.Hex(), OperatorSetIds: []uint32{1}},
+				},
+			},
+			currentURI:    "https://example.com/metadata.json",
+			expectedTypes: []ActionType{},
+		},
+		{
+			name: "full diff is ordered",
+			reader: &fakeElChainReader{
+				registeredSets: []allocationmanager.OperatorSet{{Avs: avsAddress, Id: 2}},
+				maxMagnitudes:  map[gethcommon.Address]uint64{strategyA: 1e18, strategyB: 1e18},
+				allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+					strategyA: {
+						{
+							AvsAddress:       avsAddress,
+							OperatorSetId:    2,
+							CurrentMagnitude: big.NewInt(5e17),
+							PendingDiff:      big.NewInt(0),
+						},
+					},
+				},
+				permissions: map[gethcommon.Address][]permission{
+					appointeeAddress: {{target: target, selector: [4]byte{0xde, 0xad, 0xbe, 0xef}}},
+				},
+			},
+			desiredState: &OperatorState{
+				MetadataURI:        "https://example.com/metadata.json",
+				DelegationApprover: approver,
+				Splits: &SplitsState{
+					PI:  &piSplit,
+					AVS: []AVSSplitState{{AvsAddress: avsAddress.Hex(), Split: 500}},
+				},
+				OperatorSets: []OperatorSetState{
+					{AvsAddress: avsAddress.Hex(), OperatorSetIds: []uint32{1}},
+				},
+				Allocations: []AllocationState{
+					{AvsAddress: avsAddress.Hex(), OperatorSetId: 2, StrategyAddress: strategyA.Hex(), Bips: 0},
+					{AvsAddress: avsAddress.Hex(), OperatorSetId: 1, StrategyAddress: strategyB.Hex(), Bips: 2000},
+				},
+				Appointees: []AppointeeState{
+					{
+						AppointeeAddress: appointeeAddress.Hex(),
+						Permissions:      []PermissionState{{Target: target.Hex(), Selector: "0x12345678"}},
+					},
+				},
+			},
+			expectedTypes: []ActionType{
+				UpdateDelegationApproverAction,
+				UpdateMetadataURIAction,
+				RegisterOperatorSetsAction,
+				ModifyAllocationsAction,
+				DeregisterOperatorSetsAction,
+				SetPISplitAction,
+				SetAVSSplitAction,
+				SetAppointeeAction,
+				RemoveAppointeeAction,
+			},
+		},
+	}
+
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := buildPlan(
+				context.Background(),
+				tt.reader,
+				tt.desiredState,
+				operatorAddress,
+				tt.currentURI,
+				logger,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTypes, actionTypes(plan))
+			for i, action := range plan.Actions {
+				assert.Equal(t, i+1, action.Step)
+			}
+		})
+	}
+}
+
+func TestBuildPlanAllocationMagnitudes(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	strategy := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	reader := &fakeElChainReader{
+		maxMagnitudes: map[gethcommon.Address]uint64{strategy: 1e18},
+		allocationInfo: map[gethcommon.Address][]elcontracts.AllocationInfo{
+			strategy: {
+				{AvsAddress: avsAddress, OperatorSetId: 1, CurrentMagnitude: big.NewInt(3e17), PendingDiff: big.NewInt(0)},
+				{AvsAddress: avsAddress, OperatorSetId: 2, CurrentMagnitude: big.NewInt(1e17), PendingDiff: big.NewInt(0)},
+			},
+		},
+	}
+	desiredState := &OperatorState{
+		Allocations: []AllocationState{
+			{AvsAddress: avsAddress.Hex(), OperatorSetId: 1, StrategyAddress: strategy.Hex(), Bips: 1000},
+			{AvsAddress: avsAddress.Hex(), OperatorSetId: 2, StrategyAddress: strategy.Hex(), Bips: 4000},
+		},
+	}
+
+	plan, err := buildPlan(context.Background(), reader, desiredState, operatorAddress, "", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []ActionType{ModifyAllocationsAction}, actionTypes(plan))
+	assert.Equal(
+		t,
+		"Modify allocations: "+common.ShortEthAddress(avsAddress)+"/1 "+common.ShortEthAddress(strategy)+
+			" -> 100000000000000000, "+common.ShortEthAddress(avsAddress)+"/2 "+common.ShortEthAddress(strategy)+
+			" -> 400000000000000000",
+		plan.Actions[0].Description,
+	)
+
+	// A slashable deallocation only frees its magnitude after the deallocation delay, so the allocation
+	// can't be planned along with it
+	desiredState.Allocations[1].Bips = 8000
+	reader.slashableSets = map[allocationmanager.OperatorSet]bool{{Avs: avsAddress, Id: 1}: true}
+	_, err = buildPlan(context.Background(), reader, desiredState, operatorAddress, "", logger)
+	assert.ErrorContains(t, err, "exceeds the max magnitude")
+
+	// Allocations with a pending modification can't be changed
+	reader.allocationInfo[strategy][0].PendingDiff = big.NewInt(-1e17)
+	_, err = buildPlan(context.Background(), reader, desiredState, operatorAddress, "", logger)
+	assert.ErrorContains(t, err, "pending modification")
+}
+
+func TestBuildPlanInvalidInput(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	_, err := buildPlan(context.Background(), &fakeElChainReader{}, &OperatorState{
+		Allocations: []AllocationState{
+			{AvsAddress: operatorAddress.Hex(), StrategyAddress: operatorAddress.Hex(), Bips: 10_001},
+		},
+	}, operatorAddress, "", logger)
+	assert.Error(t, err)
+
+	_, err = buildPlan(context.Background(), &fakeElChainReader{}, &OperatorState{
+		Appointees: []AppointeeState{
+			{
+				AppointeeAddress: operatorAddress.Hex(),
+				Permissions:      []PermissionState{{Target: operatorAddress.Hex(), Selector: "0x1234"}},
+			},
+		},
+	}, operatorAddress, "", logger)
+	assert.Error(t, err)
+}
+
+func TestBuildRegistrationRequiresBlsKey(t *testing.T) {
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	registryCoordinator := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	plan, err := buildPlan(context.Background(), &fakeElChainReader{}, &OperatorState{
+		OperatorSets: []OperatorSetState{
+			{AvsAddress: avsAddress.Hex(), RegistryCoordinatorAddress: registryCoordinator.Hex(), OperatorSetIds: []uint32{1}},
+		},
+	}, operatorAddress, "", logger)
+	assert.NoError(t, err)
+	assert.Equal(t, []ActionType{RegisterOperatorSetsAction}, actionTypes(plan))
+
+	// Without the BLS key the registration data can't be built and the registrar would revert
+	_, err = plan.Actions[0].build(context.Background(), nil, &txBuilder{})
+	assert.ErrorContains(t, err, "bls-private-key")
+}
+
+func TestValidateStateAddresses(t *testing.T) {
+	address := testutils.GenerateRandomEthereumAddressString()
+
+	assert.NoError(t, validateStateAddresses(&OperatorState{
+		OperatorAddress: address,
+		Splits:          &SplitsState{AVS: []AVSSplitState{{AvsAddress: address}}},
+		OperatorSets:    []OperatorSetState{{AvsAddress: address}},
+		Allocations:     []AllocationState{{AvsAddress: address, StrategyAddress: address}},
+		Appointees: []AppointeeState{
+			{AppointeeAddress: address, Permissions: []PermissionState{{Target: address}}},
+		},
+	}))
+
+	invalidStates := []*OperatorState{
+		{DelegationApprover: "0x1234"},
+		{Splits: &SplitsState{OperatorSets: []OperatorSetSplitState{{AvsAddress: ""}}}},
+		{OperatorSets: []OperatorSetState{{AvsAddress: address, RegistryCoordinatorAddress: "coordinator"}}},
+		{Allocations: []AllocationState{{AvsAddress: address, StrategyAddress: address + "0"}}},
+		{Appointees: []AppointeeState{{AppointeeAddress: address, Permissions: []PermissionState{{Target: "0x"}}}}},
+	}
+	for _, state := range invalidStates {
+		assert.Error(t, validateStateAddresses(state))
+	}
+}
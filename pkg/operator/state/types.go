@@ -0,0 +1,134 @@
+package state
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// OperatorState is the desired state of an operator as described in the operator state file.
+// Any field which is omitted is not managed and will be left untouched.
+type OperatorState struct {
+	OperatorAddress    string             `yaml:"operator_address"`
+	MetadataURI        string             `yaml:"metadata_uri"`
+	DelegationApprover string             `yaml:"delegation_approver"`
+	AllocationDelay    *uint32            `yaml:"allocation_delay"`
+	Splits             *SplitsState       `yaml:"splits"`
+	OperatorSets       []OperatorSetState `yaml:"operator_sets"`
+	Allocations        []AllocationState  `yaml:"allocations"`
+	Appointees         []AppointeeState   `yaml:"appointees"`
+}
+
+type SplitsState struct {
+	PI           *uint16                 `yaml:"pi"`
+	AVS          []AVSSplitState         `yaml:"avs"`
+	OperatorSets []OperatorSetSplitState `yaml:"operator_sets"`
+}
+
+type AVSSplitState struct {
+	AvsAddress string `yaml:"avs_address"`
+	Split      uint16 `yaml:"split"`
+}
+
+type OperatorSetSplitState struct {
+	AvsAddress    string `yaml:"avs_address"`
+	OperatorSetId uint32 `yaml:"operator_set_id"`
+	Split         uint16 `yaml:"split"`
+}
+
+// OperatorSetState is the full list of operator sets of an AVS the operator should be registered for.
+// Registered operator sets of the AVS which are not listed will be deregistered.
+type OperatorSetState struct {
+	AvsAddress                 string   `yaml:"avs_address"`
+	OperatorSetIds             []uint32 `yaml:"operator_set_ids"`
+	RegistryCoordinatorAddress string   `yaml:"registry_coordinator_address"`
+}
+
+// AllocationState is the allocation of a strategy to an operator set. As in the target file of the allocations
+// update command, the allocations of each listed strategy are complete: operator sets which are not listed
+// for the strategy are deallocated.
+type AllocationState struct {
+	AvsAddress      string `yaml:"avs_address"`
+	OperatorSetId   uint32 `yaml:"operator_set_id"`
+	StrategyAddress string `yaml:"strategy_address"`
+	Bips            uint64 `yaml:"bips"`
+}
+
+// AppointeeState is the full list of permissions an appointee should have.
+// Permissions of the appointee which are not listed will be removed.
+type AppointeeState struct {
+	AppointeeAddress string            `yaml:"appointee_address"`
+	Permissions      []PermissionState `yaml:"permissions"`
+}
+
+type PermissionState struct {
+	Target   string `yaml:"target"`
+	Selector string `yaml:"selector"`
+}
+
+type ActionType string
+
+const (
+	UpdateDelegationApproverAction ActionType = "update-delegation-approver"
+	UpdateMetadataURIAction        ActionType = "update-metadata-uri"
+	SetAllocationDelayAction       ActionType = "set-allocation-delay"
+	RegisterOperatorSetsAction     ActionType = "register-operator-sets"
+	ModifyAllocationsAction        ActionType = "modify-allocations"
+	DeregisterOperatorSetsAction   ActionType = "deregister-operator-sets"
+	SetPISplitAction               ActionType = "set-pi-split"
+	SetAVSSplitAction              ActionType = "set-avs-split"
+	SetOperatorSetSplitAction      ActionType = "set-operatorset-split"
+	SetAppointeeAction             ActionType = "set-appointee"
+	RemoveAppointeeAction          ActionType = "remove-appointee"
+)
+
+// Action is a single onchain change required to move the operator to the desired state.
+type Action struct {
+	Step        int        `json:"step"`
+	Type        ActionType `json:"type"`
+	Description string     `json:"description"`
+
+	send  func(ctx context.Context, writer *elcontracts.ChainWriter, blsKeyPair *bls.KeyPair) (*gethtypes.Receipt, error)
+	build func(ctx context.Context, opts *bind.TransactOpts, builder *txBuilder) (*gethtypes.Transaction, error)
+}
+
+// txBuilder holds the contract bindings and inputs actions use to create their unsigned transactions
+type txBuilder struct {
+	*elcontracts.ContractBindings
+	backend    bind.ContractBackend
+	blsKeyPair *bls.KeyPair
+}
+
+type Plan struct {
+	OperatorAddress gethcommon.Address `json:"operator_address"`
+	Actions         []*Action          `json:"actions"`
+}
+
+type stateConfig struct {
+	network                     string
+	rpcUrl                      string
+	environment                 string
+	chainID                     *big.Int
+	stateFile                   string
+	desiredState                *OperatorState
+	operatorAddress             gethcommon.Address
+	callerAddress               gethcommon.Address
+	delegationManagerAddress    gethcommon.Address
+	rewardsCoordinatorAddress   gethcommon.Address
+	permissionControllerAddress gethcommon.Address
+	signerConfig                *types.SignerConfig
+	broadcast                   bool
+	output                      string
+	outputType                  string
+	safeBatchFile               string
+	isSilent                    bool
+	blsKeyPair                  *bls.KeyPair
+}
@@ -3,26 +3,29 @@ package operator
 import (
 	"math/big"
 
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operator/registrar"
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
-	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type DeregisterConfig struct {
-	avsAddress               common.Address
-	operatorSetIds           []uint32
-	operatorAddress          common.Address
-	callerAddress            common.Address
-	network                  string
-	environment              string
-	broadcast                bool
-	rpcUrl                   string
-	chainID                  *big.Int
-	signerConfig             *types.SignerConfig
-	output                   string
-	outputType               string
-	delegationManagerAddress common.Address
-	isSilent                 bool
+	avsAddress                  common.Address
+	operatorSetIds              []uint32
+	operatorAddress             common.Address
+	callerAddress               common.Address
+	network                     string
+	environment                 string
+	broadcast                   bool
+	rpcUrl                      string
+	chainID                     *big.Int
+	signerConfig                *types.SignerConfig
+	output                      string
+	outputType                  string
+	delegationManagerAddress    common.Address
+	isSilent                    bool
+	skipConfirmation            bool
+	rewardsCoordinatorAddress   common.Address
+	permissionControllerAddress common.Address
 }
 
 type RegisterConfig struct {
@@ -41,5 +44,18 @@ type RegisterConfig struct {
 	delegationManagerAddress   common.Address
 	isSilent                   bool
 	registryCoordinatorAddress common.Address
-	blsKeyPair                 *bls.KeyPair
+	registrationDataBuilder    registrar.RegistrationDataBuilder
+	skipSimulation             bool
+}
+
+type slashingExposureConfig struct {
+	network                  string
+	rpcUrl                   string
+	environment              string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	operatorAddress          common.Address
+	avsAddresses             []common.Address
+	delegationManagerAddress common.Address
 }
@@ -0,0 +1,21 @@
+package pkg
+
+import (
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/operatorsets"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+func OperatorSetsCmd(prompter utils.Prompter) *cli.Command {
+	var operatorSetsCmd = &cli.Command{
+		Name:  "operator-sets",
+		Usage: "Discover operator sets of AVSs",
+		Subcommands: []*cli.Command{
+			operatorsets.ListCmd(prompter),
+			operatorsets.ShowCmd(prompter),
+			operatorsets.RegisteredCmd(prompter),
+		},
+	}
+
+	return operatorSetsCmd
+}
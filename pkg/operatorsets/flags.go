@@ -0,0 +1,61 @@
+package operatorsets
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	AVSAddressFlag = cli.StringFlag{
+		Name:    "avs-address",
+		Aliases: []string{"aa", "avs"},
+		Usage:   "AVS address of the operator sets",
+		EnvVars: []string{"AVS_ADDRESS"},
+	}
+)
+
+func getAvsAddress(cCtx *cli.Context, logger logging.Logger) (gethcommon.Address, error) {
+	avsAddress := cCtx.String(AVSAddressFlag.Name)
+	if common.IsEmptyString(avsAddress) {
+		logger.Error("--avs-address flag must be set")
+		return gethcommon.Address{}, fmt.Errorf("Empty AVS address provided")
+	}
+	if !gethcommon.IsHexAddress(avsAddress) {
+		return gethcommon.Address{}, fmt.Errorf("invalid AVS address: %s", avsAddress)
+	}
+	return gethcommon.HexToAddress(avsAddress), nil
+}
+
+// getOptionalOperatorAddress returns nil if no operator address was provided
+func getOptionalOperatorAddress(cCtx *cli.Context) (*gethcommon.Address, error) {
+	operatorAddress := cCtx.String(flags.OperatorAddressFlag.Name)
+	if common.IsEmptyString(operatorAddress) {
+		return nil, nil
+	}
+	if !gethcommon.IsHexAddress(operatorAddress) {
+		return nil, fmt.Errorf("invalid operator address: %s", operatorAddress)
+	}
+	address := gethcommon.HexToAddress(operatorAddress)
+	return &address, nil
+}
+
+func getDelegationManagerAddress(cCtx *cli.Context, chainID *big.Int) (gethcommon.Address, error) {
+	var err error
+	delegationManagerAddress := cCtx.String(flags.DelegationManagerAddressFlag.Name)
+	if common.IsEmptyString(delegationManagerAddress) {
+		delegationManagerAddress, err = common.GetDelegationManagerAddress(chainID)
+		if err != nil {
+			return gethcommon.Address{}, err
+		}
+	}
+	return gethcommon.HexToAddress(delegationManagerAddress), nil
+}
@@ -0,0 +1,180 @@
+package operatorsets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func ListCmd(p utils.Prompter) *cli.Command {
+	listCmd := &cli.Command{
+		Name:      "list",
+		Usage:     "List the operator sets of an AVS",
+		UsageText: "list [flags]",
+		Description: `
+Lists every operator set of the AVS with its strategies, number of members and minimum slashable stake.
+If --operator-address is provided, it also shows whether the operator is registered for each operator set
+and the slashable stake of the operator.
+		`,
+		Flags: getListFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return listOperatorSets(cCtx)
+		},
+	}
+
+	return listCmd
+}
+
+func listOperatorSets(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateListConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate list operator sets config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	allocationManager, err := getAllocationManager(ethClient, config.delegationManagerAddress, logger)
+	if err != nil {
+		return err
+	}
+
+	blockNumber, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block number", err)
+	}
+
+	operatorSetIds, complete, err := discoverOperatorSetIds(ctx, allocationManager, config.avsAddress)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to discover operator sets", err)
+	}
+	if !complete {
+		fromBlock := common.LookbackStartBlock(blockNumber, operatorSetLogsLookbackBlocks)
+		logger.Debugf(
+			"Not all operator sets found by ID, falling back to OperatorSetCreated events from block %d to %d",
+			fromBlock,
+			blockNumber,
+		)
+		createdIds, err := getCreatedOperatorSetIds(ctx, allocationManager, config.avsAddress, fromBlock, blockNumber)
+		if err != nil {
+			logger.Warnf("Failed to fetch operator set events, some operator sets may be missing: %s", err)
+		} else {
+			operatorSetIds = mergeOperatorSetIds(operatorSetIds, createdIds)
+		}
+	}
+
+	registeredSets, err := getRegisteredSets(ctx, allocationManager, config.operatorAddress)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+
+	operatorSets := make(OperatorSets, 0, len(operatorSetIds))
+	for _, id := range operatorSetIds {
+		info, err := getOperatorSetInfo(
+			ctx,
+			allocationManager,
+			allocationmanager.OperatorSet{Avs: config.avsAddress, Id: id},
+			config.operatorAddress,
+			registeredSets,
+			uint32(blockNumber),
+			false,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError(fmt.Sprintf("failed to get operator set %d", id), err)
+		}
+		operatorSets = append(operatorSets, *info)
+	}
+
+	if config.outputType != utils.JsonOutputType {
+		fmt.Println()
+		fmt.Printf("Found %d operator sets for AVS %s\n", len(operatorSets), config.avsAddress.Hex())
+	}
+	return writeOutput(operatorSets, config.outputType, config.output, logger)
+}
+
+func getAllocationManager(
+	ethClient *ethclient.Client,
+	delegationManagerAddress gethcommon.Address,
+	logger logging.Logger,
+) (*allocationmanager.ContractAllocationManager, error) {
+	elConfig := elcontracts.Config{
+		DelegationManagerAddress: delegationManagerAddress,
+	}
+	_, _, contractBindings, err := elcontracts.BuildClients(elConfig, ethClient, nil, logger, nil)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+	return contractBindings.AllocationManager, nil
+}
+
+func readAndValidateListConfig(cCtx *cli.Context, logger logging.Logger) (*listConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	avsAddress, err := getAvsAddress(cCtx, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	operatorAddress, err := getOptionalOperatorAddress(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	delegationManagerAddress, err := getDelegationManagerAddress(cCtx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		avsAddress:               avsAddress,
+		operatorAddress:          operatorAddress,
+		delegationManagerAddress: delegationManagerAddress,
+	}, nil
+}
+
+func getListFlags() []cli.Flag {
+	listFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&AVSAddressFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(listFlags))
+	return listFlags
+}
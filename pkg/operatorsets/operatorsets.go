@@ -0,0 +1,338 @@
+package operatorsets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// maxOperatorSetIdGap is the number of consecutive unused operator set IDs after which
+// we stop probing the AllocationManager for more operator sets of an AVS.
+const maxOperatorSetIdGap = 64
+
+// operatorSetLogsLookbackBlocks is the number of blocks whose OperatorSetCreated events are read when probing
+// by ID doesn't find every operator set, about 90 days of mainnet blocks
+const operatorSetLogsLookbackBlocks = 648_000
+
+// allocationManagerReader is the subset of the AllocationManager view functions used to
+// discover operator sets. The ChainReader rejects operator set ID 0, which is a valid
+// operator set since slashing, so we read from the contract bindings directly.
+type allocationManagerReader interface {
+	GetOperatorSetCount(opts *bind.CallOpts, avs gethcommon.Address) (*big.Int, error)
+	IsOperatorSet(opts *bind.CallOpts, operatorSet allocationmanager.OperatorSet) (bool, error)
+	GetStrategiesInOperatorSet(
+		opts *bind.CallOpts,
+		operatorSet allocationmanager.OperatorSet,
+	) ([]gethcommon.Address, error)
+	GetMemberCount(opts *bind.CallOpts, operatorSet allocationmanager.OperatorSet) (*big.Int, error)
+	GetMembers(opts *bind.CallOpts, operatorSet allocationmanager.OperatorSet) ([]gethcommon.Address, error)
+	GetRegisteredSets(opts *bind.CallOpts, operator gethcommon.Address) ([]allocationmanager.OperatorSet, error)
+	GetMinimumSlashableStake(
+		opts *bind.CallOpts,
+		operatorSet allocationmanager.OperatorSet,
+		operators []gethcommon.Address,
+		strategies []gethcommon.Address,
+		futureBlock uint32,
+	) ([][]*big.Int, error)
+}
+
+// discoverOperatorSetIds probes the AllocationManager for the operator sets of the AVS. Operator set IDs
+// are chosen by the AVS, so they are not guaranteed to be sequential. The returned bool is false if fewer
+// operator sets than reported by the AllocationManager were found before giving up.
+func discoverOperatorSetIds(
+	ctx context.Context,
+	reader allocationManagerReader,
+	avsAddress gethcommon.Address,
+) ([]uint32, bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	count, err := reader.GetOperatorSetCount(opts, avsAddress)
+	if err != nil {
+		return nil, false, err
+	}
+
+	operatorSetIds := make([]uint32, 0, count.Uint64())
+	misses := 0
+	for id := uint32(0); uint64(len(operatorSetIds)) < count.Uint64() && misses < maxOperatorSetIdGap; id++ {
+		exists, err := reader.IsOperatorSet(opts, allocationmanager.OperatorSet{Avs: avsAddress, Id: id})
+		if err != nil {
+			return nil, false, err
+		}
+		if !exists {
+			misses++
+			continue
+		}
+		misses = 0
+		operatorSetIds = append(operatorSetIds, id)
+	}
+
+	return operatorSetIds, uint64(len(operatorSetIds)) == count.Uint64(), nil
+}
+
+// getCreatedOperatorSetIds returns the IDs of the operator sets of the AVS from the OperatorSetCreated events
+// of the AllocationManager between fromBlock and toBlock
+func getCreatedOperatorSetIds(
+	ctx context.Context,
+	allocationManager *allocationmanager.ContractAllocationManager,
+	avsAddress gethcommon.Address,
+	fromBlock uint64,
+	toBlock uint64,
+) ([]uint32, error) {
+	operatorSetIds := make([]uint32, 0)
+	err := common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		iterator, err := allocationManager.FilterOperatorSetCreated(opts)
+		if err != nil {
+			return err
+		}
+		defer iterator.Close()
+
+		for iterator.Next() {
+			// The event has no indexed fields, so we filter by AVS here
+			if iterator.Event.OperatorSet.Avs == avsAddress {
+				operatorSetIds = append(operatorSetIds, iterator.Event.OperatorSet.Id)
+			}
+		}
+		return iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return operatorSetIds, nil
+}
+
+func mergeOperatorSetIds(a []uint32, b []uint32) []uint32 {
+	seen := make(map[uint32]bool)
+	merged := make([]uint32, 0, len(a)+len(b))
+	for _, id := range append(a, b...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		merged = append(merged, id)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+	return merged
+}
+
+// getOperatorSetInfo reads the strategies, members and slashable stake of the operator set at the given
+// block. If operatorAddress is set, the registration status and slashable stake of the operator are included.
+func getOperatorSetInfo(
+	ctx context.Context,
+	reader allocationManagerReader,
+	operatorSet allocationmanager.OperatorSet,
+	operatorAddress *gethcommon.Address,
+	registeredSets map[allocationmanager.OperatorSet]bool,
+	blockNumber uint32,
+	includeMembers bool,
+) (*OperatorSetInfo, error) {
+	opts := &bind.CallOpts{Context: ctx}
+
+	strategies, err := reader.GetStrategiesInOperatorSet(opts, operatorSet)
+	if err != nil {
+		return nil, err
+	}
+	memberCount, err := reader.GetMemberCount(opts, operatorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &OperatorSetInfo{
+		AvsAddress:    operatorSet.Avs.Hex(),
+		OperatorSetId: operatorSet.Id,
+		MemberCount:   memberCount.Uint64(),
+		Strategies:    make([]StrategyStake, 0, len(strategies)),
+	}
+	if operatorAddress != nil {
+		registered := registeredSets[operatorSet]
+		info.Registered = &registered
+	}
+
+	var members []gethcommon.Address
+	if includeMembers || memberCount.Sign() > 0 {
+		members, err = reader.GetMembers(opts, operatorSet)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if includeMembers {
+		info.Members = make([]string, 0, len(members))
+		for _, member := range members {
+			info.Members = append(info.Members, member.Hex())
+		}
+	}
+
+	totalStake := make([]*big.Int, len(strategies))
+	for i := range strategies {
+		totalStake[i] = big.NewInt(0)
+	}
+	if len(members) > 0 && len(strategies) > 0 {
+		stakes, err := reader.GetMinimumSlashableStake(opts, operatorSet, members, strategies, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		for _, memberStakes := range stakes {
+			for i, stake := range memberStakes {
+				totalStake[i].Add(totalStake[i], stake)
+			}
+		}
+	}
+
+	var operatorStake [][]*big.Int
+	if operatorAddress != nil && len(strategies) > 0 {
+		operatorStake, err = reader.GetMinimumSlashableStake(
+			opts,
+			operatorSet,
+			[]gethcommon.Address{*operatorAddress},
+			strategies,
+			blockNumber,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, strategy := range strategies {
+		stake := StrategyStake{
+			StrategyAddress:     strategy.Hex(),
+			TotalSlashableStake: totalStake[i],
+		}
+		if len(operatorStake) > 0 {
+			stake.OperatorSlashableStake = operatorStake[0][i]
+		}
+		info.Strategies = append(info.Strategies, stake)
+	}
+
+	return info, nil
+}
+
+func getRegisteredSets(
+	ctx context.Context,
+	reader allocationManagerReader,
+	operatorAddress *gethcommon.Address,
+) (map[allocationmanager.OperatorSet]bool, error) {
+	registeredSets := make(map[allocationmanager.OperatorSet]bool)
+	if operatorAddress == nil {
+		return registeredSets, nil
+	}
+	sets, err := reader.GetRegisteredSets(&bind.CallOpts{Context: ctx}, *operatorAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		registeredSets[set] = true
+	}
+	return registeredSets, nil
+}
+
+func writeOutput(operatorSets OperatorSets, outputType string, output string, logger logging.Logger) error {
+	if outputType == utils.JsonOutputType {
+		operatorSetsJson, err := json.MarshalIndent(operatorSets, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(output) {
+			err = common.WriteToFile(operatorSetsJson, output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Operator sets written to file: %s", output)
+		} else {
+			fmt.Println(string(operatorSetsJson))
+		}
+		return nil
+	}
+
+	if !common.IsEmptyString(output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+	operatorSets.PrintPretty()
+	return nil
+}
+
+func (s OperatorSets) PrintPretty() {
+	headers := []string{
+		"AVS Address",
+		"Operator Set ID",
+		"Members",
+		"Registered",
+		"Strategy Address",
+		"Slashable Stake (shares)",
+		"Operator Slashable Stake (shares)",
+	}
+	widths := []int{44, 16, 8, 11, 44, 26, 34}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+
+	// Print header
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+
+	// Print separator
+	for _, width := range widths {
+		fmt.Print("|", strings.Repeat("-", width+1))
+	}
+	fmt.Println("|")
+
+	for _, operatorSet := range s {
+		registered := "-"
+		if operatorSet.Registered != nil {
+			registered = fmt.Sprintf("%t", *operatorSet.Registered)
+		}
+		rows := operatorSet.Strategies
+		if len(rows) == 0 {
+			rows = []StrategyStake{{}}
+		}
+		// Only print the operator set details on the first strategy row of each operator set
+		for i, strategy := range rows {
+			avsAddress, operatorSetId, members, registeredColumn := "", "", "", ""
+			if i == 0 {
+				avsAddress = operatorSet.AvsAddress
+				operatorSetId = fmt.Sprintf("%d", operatorSet.OperatorSetId)
+				members = fmt.Sprintf("%d", operatorSet.MemberCount)
+				registeredColumn = registered
+			}
+			strategyAddress, totalStake, operatorStake := "-", "-", "-"
+			if !common.IsEmptyString(strategy.StrategyAddress) {
+				strategyAddress = strategy.StrategyAddress
+				totalStake = strategy.TotalSlashableStake.String()
+			}
+			if strategy.OperatorSlashableStake != nil {
+				operatorStake = strategy.OperatorSlashableStake.String()
+			}
+			fmt.Printf(
+				"| %-*s| %-*s| %-*s| %-*s| %-*s| %-*s| %-*s|\n",
+				widths[0], avsAddress,
+				widths[1], operatorSetId,
+				widths[2], members,
+				widths[3], registeredColumn,
+				widths[4], strategyAddress,
+				widths[5], totalStake,
+				widths[6], operatorStake,
+			)
+		}
+	}
+
+	// print dashes
+	for _, width := range widths {
+		fmt.Print("+" + strings.Repeat("-", width+1))
+	}
+	fmt.Println("+")
+}
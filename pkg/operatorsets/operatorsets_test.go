@@ -0,0 +1,251 @@
+package operatorsets
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAllocationManagerReader struct {
+	operatorSets   map[allocationmanager.OperatorSet][]gethcommon.Address
+	members        map[allocationmanager.OperatorSet][]gethcommon.Address
+	registeredSets []allocationmanager.OperatorSet
+	stake          map[gethcommon.Address]*big.Int
+}
+
+func (f *fakeAllocationManagerReader) GetOperatorSetCount(
+	opts *bind.CallOpts,
+	avs gethcommon.Address,
+) (*big.Int, error) {
+	count := int64(0)
+	for operatorSet := range f.operatorSets {
+		if operatorSet.Avs == avs {
+			count++
+		}
+	}
+	return big.NewInt(count), nil
+}
+
+func (f *fakeAllocationManagerReader) IsOperatorSet(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+) (bool, error) {
+	_, ok := f.operatorSets[operatorSet]
+	return ok, nil
+}
+
+func (f *fakeAllocationManagerReader) GetStrategiesInOperatorSet(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.operatorSets[operatorSet], nil
+}
+
+func (f *fakeAllocationManagerReader) GetMemberCount(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+) (*big.Int, error) {
+	return big.NewInt(int64(len(f.members[operatorSet]))), nil
+}
+
+func (f *fakeAllocationManagerReader) GetMembers(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+) ([]gethcommon.Address, error) {
+	return f.members[operatorSet], nil
+}
+
+func (f *fakeAllocationManagerReader) GetRegisteredSets(
+	opts *bind.CallOpts,
+	operator gethcommon.Address,
+) ([]allocationmanager.OperatorSet, error) {
+	return f.registeredSets, nil
+}
+
+func (f *fakeAllocationManagerReader) GetMinimumSlashableStake(
+	opts *bind.CallOpts,
+	operatorSet allocationmanager.OperatorSet,
+	operators []gethcommon.Address,
+	strategies []gethcommon.Address,
+	futureBlock uint32,
+) ([][]*big.Int, error) {
+	stakes := make([][]*big.Int, len(operators))
+	for i, operator := range operators {
+		stakes[i] = make([]*big.Int, len(strategies))
+		for j := range strategies {
+			stakes[i][j] = new(big.Int).Set(f.stake[operator])
+		}
+	}
+	return stakes, nil
+}
+
+func TestDiscoverOperatorSetIds(t *testing.T) {
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	otherAvsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+
+	tests := []struct {
+		name             string
+		operatorSetIds   []uint32
+		expectedIds      []uint32
+		expectedComplete bool
+	}{
+		{
+			name:             "no operator sets",
+			operatorSetIds:   []uint32{},
+			expectedIds:      []uint32{},
+			expectedComplete: true,
+		},
+		{
+			name:             "sequential operator sets starting at zero",
+			operatorSetIds:   []uint32{0, 1, 2},
+			expectedIds:      []uint32{0, 1, 2},
+			expectedComplete: true,
+		},
+		{
+			name:             "operator sets with small gaps",
+			operatorSetIds:   []uint32{1, 5, 20},
+			expectedIds:      []uint32{1, 5, 20},
+			expectedComplete: true,
+		},
+		{
+			name:             "operator set beyond the maximum gap",
+			operatorSetIds:   []uint32{1, 1000},
+			expectedIds:      []uint32{1},
+			expectedComplete: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeAllocationManagerReader{
+				operatorSets: map[allocationmanager.OperatorSet][]gethcommon.Address{
+					{Avs: otherAvsAddress, Id: 3}: {},
+				},
+			}
+			for _, id := range tt.operatorSetIds {
+				reader.operatorSets[allocationmanager.OperatorSet{Avs: avsAddress, Id: id}] = nil
+			}
+
+			ids, complete, err := discoverOperatorSetIds(context.Background(), reader, avsAddress)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIds, ids)
+			assert.Equal(t, tt.expectedComplete, complete)
+		})
+	}
+}
+
+func TestMergeOperatorSetIds(t *testing.T) {
+	assert.Equal(t, []uint32{0, 1, 3, 1000}, mergeOperatorSetIds([]uint32{0, 3}, []uint32{1000, 1, 3}))
+}
+
+func TestGetOperatorSetInfo(t *testing.T) {
+	avsAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	strategyAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	operatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	otherOperatorAddress := gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+	operatorSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 0}
+	emptyOperatorSet := allocationmanager.OperatorSet{Avs: avsAddress, Id: 1}
+
+	reader := &fakeAllocationManagerReader{
+		operatorSets: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			operatorSet:      {strategyAddress},
+			emptyOperatorSet: {strategyAddress},
+		},
+		members: map[allocationmanager.OperatorSet][]gethcommon.Address{
+			operatorSet: {operatorAddress, otherOperatorAddress},
+		},
+		registeredSets: []allocationmanager.OperatorSet{operatorSet},
+		stake: map[gethcommon.Address]*big.Int{
+			operatorAddress:      big.NewInt(100),
+			otherOperatorAddress: big.NewInt(50),
+		},
+	}
+	registeredSets, err := getRegisteredSets(context.Background(), reader, &operatorAddress)
+	assert.NoError(t, err)
+
+	registered := true
+	notRegistered := false
+	tests := []struct {
+		name            string
+		operatorSet     allocationmanager.OperatorSet
+		operatorAddress *gethcommon.Address
+		includeMembers  bool
+		expected        *OperatorSetInfo
+	}{
+		{
+			name:            "registered operator",
+			operatorSet:     operatorSet,
+			operatorAddress: &operatorAddress,
+			includeMembers:  true,
+			expected: &OperatorSetInfo{
+				AvsAddress:    avsAddress.Hex(),
+				OperatorSetId: 0,
+				MemberCount:   2,
+				Registered:    &registered,
+				Strategies: []StrategyStake{
+					{
+						StrategyAddress:        strategyAddress.Hex(),
+						TotalSlashableStake:    big.NewInt(150),
+						OperatorSlashableStake: big.NewInt(100),
+					},
+				},
+				Members: []string{operatorAddress.Hex(), otherOperatorAddress.Hex()},
+			},
+		},
+		{
+			name:        "no operator provided",
+			operatorSet: operatorSet,
+			expected: &OperatorSetInfo{
+				AvsAddress:    avsAddress.Hex(),
+				OperatorSetId: 0,
+				MemberCount:   2,
+				Strategies: []StrategyStake{
+					{StrategyAddress: strategyAddress.Hex(), TotalSlashableStake: big.NewInt(150)},
+				},
+			},
+		},
+		{
+			name:            "operator set without members",
+			operatorSet:     emptyOperatorSet,
+			operatorAddress: &operatorAddress,
+			expected: &OperatorSetInfo{
+				AvsAddress:    avsAddress.Hex(),
+				OperatorSetId: 1,
+				MemberCount:   0,
+				Registered:    &notRegistered,
+				Strategies: []StrategyStake{
+					{
+						StrategyAddress:        strategyAddress.Hex(),
+						TotalSlashableStake:    big.NewInt(0),
+						OperatorSlashableStake: big.NewInt(100),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := getOperatorSetInfo(
+				context.Background(),
+				reader,
+				tt.operatorSet,
+				tt.operatorAddress,
+				registeredSets,
+				100,
+				tt.includeMembers,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, info)
+		})
+	}
+}
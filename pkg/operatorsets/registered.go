@@ -0,0 +1,159 @@
+package operatorsets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func RegisteredCmd(p utils.Prompter) *cli.Command {
+	registeredCmd := &cli.Command{
+		Name:      "registered",
+		Usage:     "List the operator sets an operator is registered for",
+		UsageText: "registered [flags]",
+		Description: `
+Lists every operator set the operator is registered for across all AVSs, with its strategies,
+number of members and the slashable stake of the operator.
+		`,
+		Flags: getRegisteredFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return listRegisteredOperatorSets(cCtx)
+		},
+	}
+
+	return registeredCmd
+}
+
+func listRegisteredOperatorSets(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateRegisteredConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate registered operator sets config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	allocationManager, err := getAllocationManager(ethClient, config.delegationManagerAddress, logger)
+	if err != nil {
+		return err
+	}
+
+	blockNumber, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block number", err)
+	}
+
+	sets, err := allocationManager.GetRegisteredSets(&bind.CallOpts{Context: ctx}, config.operatorAddress)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].Avs != sets[j].Avs {
+			return sets[i].Avs.Hex() < sets[j].Avs.Hex()
+		}
+		return sets[i].Id < sets[j].Id
+	})
+
+	registeredSets := make(map[allocationmanager.OperatorSet]bool)
+	for _, set := range sets {
+		registeredSets[set] = true
+	}
+
+	operatorSets := make(OperatorSets, 0, len(sets))
+	for _, set := range sets {
+		info, err := getOperatorSetInfo(
+			ctx,
+			allocationManager,
+			set,
+			&config.operatorAddress,
+			registeredSets,
+			uint32(blockNumber),
+			false,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError(
+				fmt.Sprintf("failed to get operator set %d of AVS %s", set.Id, set.Avs.Hex()),
+				err,
+			)
+		}
+		operatorSets = append(operatorSets, *info)
+	}
+
+	if config.outputType != utils.JsonOutputType {
+		fmt.Println()
+		fmt.Printf(
+			"Operator %s is registered for %d operator sets\n",
+			config.operatorAddress.Hex(),
+			len(operatorSets),
+		)
+	}
+	return writeOutput(operatorSets, config.outputType, config.output, logger)
+}
+
+func readAndValidateRegisteredConfig(cCtx *cli.Context, logger logging.Logger) (*registeredConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	operatorAddress, err := getOptionalOperatorAddress(cCtx)
+	if err != nil {
+		return nil, err
+	}
+	if operatorAddress == nil {
+		logger.Error("--operator-address flag must be set")
+		return nil, fmt.Errorf("Empty operator address provided")
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	delegationManagerAddress, err := getDelegationManagerAddress(cCtx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registeredConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		operatorAddress:          *operatorAddress,
+		delegationManagerAddress: delegationManagerAddress,
+	}, nil
+}
+
+func getRegisteredFlags() []cli.Flag {
+	registeredFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(registeredFlags))
+	return registeredFlags
+}
@@ -0,0 +1,163 @@
+package operatorsets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	allocationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AllocationManager"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+func ShowCmd(p utils.Prompter) *cli.Command {
+	showCmd := &cli.Command{
+		Name:      "show",
+		Usage:     "Show the details of an operator set",
+		UsageText: "show [flags]",
+		Description: `
+Shows the strategies, members and minimum slashable stake of an operator set.
+If --operator-address is provided, it also shows whether the operator is registered for the operator set
+and the slashable stake of the operator.
+		`,
+		Flags: getShowFlags(),
+		After: telemetry.AfterRunAction(),
+		Action: func(cCtx *cli.Context) error {
+			return showOperatorSet(cCtx)
+		},
+	}
+
+	return showCmd
+}
+
+func showOperatorSet(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateShowConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate show operator set config", err)
+	}
+	cCtx.App.Metadata["network"] = config.chainID.String()
+
+	ethClient, err := ethclient.Dial(config.rpcUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+
+	allocationManager, err := getAllocationManager(ethClient, config.delegationManagerAddress, logger)
+	if err != nil {
+		return err
+	}
+
+	operatorSet := allocationmanager.OperatorSet{Avs: config.avsAddress, Id: config.operatorSetId}
+	exists, err := allocationManager.IsOperatorSet(&bind.CallOpts{Context: ctx}, operatorSet)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to check if operator set exists", err)
+	}
+	if !exists {
+		return fmt.Errorf("operator set %d does not exist for AVS %s", config.operatorSetId, config.avsAddress.Hex())
+	}
+
+	blockNumber, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block number", err)
+	}
+
+	registeredSets, err := getRegisteredSets(ctx, allocationManager, config.operatorAddress)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get registered operator sets", err)
+	}
+
+	info, err := getOperatorSetInfo(
+		ctx,
+		allocationManager,
+		operatorSet,
+		config.operatorAddress,
+		registeredSets,
+		uint32(blockNumber),
+		true,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get operator set", err)
+	}
+
+	err = writeOutput(OperatorSets{*info}, config.outputType, config.output, logger)
+	if err != nil || config.outputType == utils.JsonOutputType {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("Members of operator set %d (%d)\n", info.OperatorSetId, info.MemberCount)
+	for _, member := range info.Members {
+		fmt.Printf("  %s\n", member)
+	}
+	return nil
+}
+
+func readAndValidateShowConfig(cCtx *cli.Context, logger logging.Logger) (*showConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+
+	avsAddress, err := getAvsAddress(cCtx, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cCtx.IsSet(flags.OperatorSetIdFlag.Name) {
+		logger.Error("--operator-set-id flag must be set")
+		return nil, fmt.Errorf("Empty operator set ID provided")
+	}
+	operatorSetId := cCtx.Uint64(flags.OperatorSetIdFlag.Name)
+
+	operatorAddress, err := getOptionalOperatorAddress(cCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	delegationManagerAddress, err := getDelegationManagerAddress(cCtx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &showConfig{
+		network:                  network,
+		rpcUrl:                   rpcUrl,
+		chainID:                  chainID,
+		output:                   output,
+		outputType:               outputType,
+		avsAddress:               avsAddress,
+		operatorSetId:            uint32(operatorSetId),
+		operatorAddress:          operatorAddress,
+		delegationManagerAddress: delegationManagerAddress,
+	}, nil
+}
+
+func getShowFlags() []cli.Flag {
+	showFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&AVSAddressFlag,
+		&flags.OperatorSetIdFlag,
+		&flags.OperatorAddressFlag,
+		&flags.DelegationManagerAddressFlag,
+	}
+	sort.Sort(cli.FlagsByName(showFlags))
+	return showFlags
+}
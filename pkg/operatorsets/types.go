@@ -0,0 +1,60 @@
+package operatorsets
+
+import (
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// OperatorSetInfo is the onchain state of an operator set as read from the AllocationManager.
+// Registered and the operator slashable stake are only set when an operator address is provided.
+type OperatorSetInfo struct {
+	AvsAddress    string          `json:"avs_address"`
+	OperatorSetId uint32          `json:"operator_set_id"`
+	MemberCount   uint64          `json:"member_count"`
+	Registered    *bool           `json:"registered,omitempty"`
+	Strategies    []StrategyStake `json:"strategies"`
+	Members       []string        `json:"members,omitempty"`
+}
+
+// StrategyStake is the minimum slashable stake of a strategy in an operator set at the current block
+type StrategyStake struct {
+	StrategyAddress        string   `json:"strategy_address"`
+	TotalSlashableStake    *big.Int `json:"total_slashable_stake"`
+	OperatorSlashableStake *big.Int `json:"operator_slashable_stake,omitempty"`
+}
+
+type OperatorSets []OperatorSetInfo
+
+type listConfig struct {
+	network                  string
+	rpcUrl                   string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	avsAddress               gethcommon.Address
+	operatorAddress          *gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+}
+
+type showConfig struct {
+	network                  string
+	rpcUrl                   string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	avsAddress               gethcommon.Address
+	operatorSetId            uint32
+	operatorAddress          *gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+}
+
+type registeredConfig struct {
+	network                  string
+	rpcUrl                   string
+	chainID                  *big.Int
+	output                   string
+	outputType               string
+	operatorAddress          gethcommon.Address
+	delegationManagerAddress gethcommon.Address
+}
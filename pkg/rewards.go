@@ -13,7 +13,13 @@ func RewardsCmd(p utils.Prompter) *cli.Command {
 		Subcommands: []*cli.Command{
 			rewards.NewClaimCmd(p),
 			rewards.NewSetClaimerCmd(p),
+			rewards.GetClaimerCmd(p),
 			rewards.ShowCmd(p),
+			rewards.VerifyProofCmd(p),
+			rewards.ExportCmd(p),
+			rewards.AutoClaimCmd(p),
+			rewards.RootsCmd(p),
+			rewards.SubmitCmd(p),
 		},
 	}
 
@@ -0,0 +1,556 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/clients/sidecar"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	autoClaimResultClaimed         = "claimed"
+	autoClaimResultBelowThreshold  = "below_threshold"
+	autoClaimResultNothingToClaim  = "nothing_to_claim"
+	autoClaimResultGasPriceTooHigh = "gas_price_too_high"
+	autoClaimResultFailed          = "failed"
+)
+
+// autoClaimer checks the claimable rewards of an earner and claims them. The EL writer is created on the first
+// claim and reused, so that the signer is only set up once for a long-lived process.
+type autoClaimer struct {
+	config        *AutoClaimConfig
+	logger        logging.Logger
+	prompter      utils.Prompter
+	ethClient     *ethclient.Client
+	elReader      *elcontracts.ChainReader
+	rootReader    distributionRootReader
+	sidecarClient rewardsV1.RewardsGatewayClient
+	eLWriter      claimWriter
+	status        *autoClaimStatus
+}
+
+func AutoClaimCmd(p utils.Prompter) *cli.Command {
+	autoClaimCmd := &cli.Command{
+		Name:      "auto-claim",
+		Usage:     "Claim rewards automatically when they are above thresholds",
+		UsageText: "auto-claim",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to claim the rewards of an earner without supervision
+
+The latest active distribution root is checked every poll interval. When the claimable amount of a token
+reaches its threshold, the token is claimed with the same pipeline and signers as the claim command.
+Tokens without a threshold, such as dust or spam tokens, are ignored unless --claim-unlisted-tokens is set.
+Checks are skipped for a root which was already claimed or below the thresholds, and claims are postponed
+while the suggested gas price is above the ceiling.
+
+Tokens which the token policy file doesn't allow are never claimed. Tokens with a recipient of their own in
+--token-recipients are claimed in a separate transaction to that recipient.
+
+The outcome of each check is logged and written to the status file. Use --once to check a single time and
+exit, for example from cron. Passwords of keystores can be piped to the command, since the signer is set
+up only once.
+`,
+		Flags: getAutoClaimFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return autoClaimAction(cCtx, p)
+		},
+	}
+	return autoClaimCmd
+}
+
+func getAutoClaimFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressFlag,
+		&EnvironmentFlag,
+		&RecipientAddressFlag,
+		&TokenAddressesFlag,
+		&ClaimerAddressFlag,
+		&ClaimThresholdsFlag,
+		&ClaimUnlistedTokensFlag,
+		&MaxGasPriceFlag,
+		&PollIntervalFlag,
+		&OnceFlag,
+		&StatusFileFlag,
+		&TokenRecipientsFlag,
+		&TokenPolicyFileFlag,
+		&RewardsCoordinatorAddressFlag,
+		&SidecarUrlFlag,
+	}
+
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
+
+func autoClaimAction(cCtx *cli.Context, p utils.Prompter) error {
+	ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateAutoClaimConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate auto-claim config", err)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(
+		config.RewardsCoordinatorAddress,
+		ethClient,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create rewards coordinator binding", err)
+	}
+	sidecarClient, err := sidecar.NewSidecarRewardsClient(config.SidecarHttpRpcURL)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new sidecar client", err)
+	}
+
+	status, err := readAutoClaimStatus(config.StatusFile)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read status file", err)
+	}
+
+	claimer := &autoClaimer{
+		config:        config,
+		logger:        logger,
+		prompter:      p,
+		ethClient:     ethClient,
+		elReader:      elReader,
+		rootReader:    rewardsCoordinator,
+		sidecarClient: sidecarClient,
+		status:        status,
+	}
+	for {
+		claimer.check(ctx)
+		if err := writeAutoClaimStatus(claimer.status, config.StatusFile); err != nil {
+			return eigenSdkUtils.WrapError("failed to write status file", err)
+		}
+		if config.Once {
+			if claimer.status.Result == autoClaimResultFailed {
+				return errors.New(claimer.status.Error)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping auto-claim")
+			return nil
+		case <-time.After(config.PollInterval):
+		}
+	}
+}
+
+// check claims the rewards above the thresholds on the latest active root and records the outcome in the status
+func (a *autoClaimer) check(ctx context.Context) {
+	status := &autoClaimStatus{
+		UpdatedAt:     time.Now().UTC().Format(time.RFC3339),
+		EarnerAddress: a.config.EarnerAddress.Hex(),
+	}
+	if a.status != nil && a.status.EarnerAddress == status.EarnerAddress {
+		status.LastClaimTxHash = a.status.LastClaimTxHash
+		status.LastClaimRootIndex = a.status.LastClaimRootIndex
+		status.LastClaimAt = a.status.LastClaimAt
+	}
+	defer func() {
+		a.status = status
+		a.logger.Info(
+			"Auto-claim check completed",
+			"earner", status.EarnerAddress,
+			"rootIndex", status.RootIndex,
+			"result", status.Result,
+			"gasPrice", status.GasPrice,
+			"txHash", status.TxHash,
+			"error", status.Error,
+		)
+	}()
+	fail := func(msg string, err error) {
+		status.Result = autoClaimResultFailed
+		status.Error = eigenSdkUtils.WrapError(msg, err).Error()
+	}
+
+	_, rootIndex, _, err := getClaimDistributionRoot(ctx, LatestActiveTimestamp, a.logger, a.sidecarClient)
+	if err != nil {
+		fail("failed to get claim distribution root", err)
+		return
+	}
+	status.RootIndex = rootIndex
+
+	// Claimable amounts only change with a new root, so a root is checked again only after a failure or
+	// while the gas price is too high
+	if a.status != nil && a.status.EarnerAddress == status.EarnerAddress && a.status.RootIndex == rootIndex &&
+		isAutoClaimSettled(a.status.Result) {
+		a.logger.Debugf("Distribution root %d was already processed", rootIndex)
+		status.Result = a.status.Result
+		status.Tokens = a.status.Tokens
+		status.TxHash = a.status.TxHash
+		return
+	}
+
+	err = validateClaimer(ctx, a.elReader, a.config.EarnerAddress, a.config.ClaimerAddress)
+	if err != nil {
+		fail("failed to validate claimer", err)
+		return
+	}
+
+	proofGenerator := &sidecarProofGenerator{sidecarClient: a.sidecarClient, rootIndex: rootIndex, logger: a.logger}
+	proof, err := generateClaimPayload(
+		ctx,
+		a.elReader,
+		a.rootReader,
+		a.logger,
+		a.config.EarnerAddress,
+		a.config.TokenAddresses,
+		a.config.TokenPolicy,
+		proofGenerator,
+	)
+	if errors.Is(err, errNoClaimableTokens) {
+		status.Result = autoClaimResultNothingToClaim
+		return
+	}
+	if err != nil {
+		fail("failed to generate claim", err)
+		return
+	}
+
+	claimableAmounts, err := getClaimableAmounts(ctx, a.elReader, proof)
+	if err != nil {
+		fail("failed to get claimable amounts", err)
+		return
+	}
+	var tokensToClaim map[gethcommon.Address]bool
+	status.Tokens, tokensToClaim = selectTokensToClaim(
+		claimableAmounts,
+		a.config.ClaimThresholds,
+		a.config.ClaimUnlistedTokens,
+	)
+	if len(tokensToClaim) == 0 {
+		status.Result = autoClaimResultBelowThreshold
+		return
+	}
+
+	gasPrice, err := a.ethClient.SuggestGasPrice(ctx)
+	if err != nil {
+		fail("failed to get gas price", err)
+		return
+	}
+	status.GasPrice = gasPrice.String()
+	if a.config.MaxGasPrice.Sign() > 0 && gasPrice.Cmp(a.config.MaxGasPrice) > 0 {
+		status.Result = autoClaimResultGasPriceTooHigh
+		return
+	}
+
+	if a.eLWriter == nil {
+		a.eLWriter, err = common.GetELWriter(
+			a.config.ClaimerAddress,
+			a.config.SignerConfig,
+			a.ethClient,
+			elcontracts.Config{
+				RewardsCoordinatorAddress: a.config.RewardsCoordinatorAddress,
+			},
+			a.prompter,
+			a.config.ChainID,
+			a.logger,
+		)
+		if err != nil {
+			fail("failed to get EL writer", err)
+			return
+		}
+	}
+
+	proof = filterTokenLeaves(proof, func(leaf *rewardsV1.TokenLeaf) bool {
+		return tokensToClaim[gethcommon.HexToAddress(leaf.Token)]
+	})
+	// Tokens with a recipient of their own are claimed in a separate transaction per recipient. Tokens
+	// claimed before a failed transaction are left out of the claim of the next check.
+	txHashes := make([]string, 0)
+	for _, recipientProof := range splitProofByRecipient(proof, a.config.TokenRecipients, a.config.RecipientAddress) {
+		setTokenRecipients(status.Tokens, recipientProof)
+		elClaims := []rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{
+			convertSidecarProofToContractProof(recipientProof.proof),
+		}
+		txHash, err := submitClaims(ctx, a.eLWriter, a.logger, a.config.ChainID, elClaims, recipientProof.recipient)
+		if txHash != (gethcommon.Hash{}) {
+			txHashes = append(txHashes, txHash.Hex())
+			status.TxHash = strings.Join(txHashes, ",")
+		}
+		if err != nil {
+			fail(fmt.Sprintf("failed to claim to recipient %s", recipientProof.recipient.Hex()), err)
+			return
+		}
+	}
+	status.Result = autoClaimResultClaimed
+	status.LastClaimTxHash = status.TxHash
+	status.LastClaimRootIndex = rootIndex
+	status.LastClaimAt = status.UpdatedAt
+}
+
+// setTokenRecipients records the recipient of the tokens of the proof in their status
+func setTokenRecipients(tokens []autoClaimTokenStatus, recipientProof recipientProof) {
+	for _, leaf := range recipientProof.proof.TokenLeaves {
+		for i := range tokens {
+			if gethcommon.HexToAddress(tokens[i].TokenAddress) == gethcommon.HexToAddress(leaf.Token) {
+				tokens[i].RecipientAddress = recipientProof.recipient.Hex()
+			}
+		}
+	}
+}
+
+// isAutoClaimSettled returns whether nothing is left to do on the root of a check with the result
+func isAutoClaimSettled(result string) bool {
+	return result == autoClaimResultClaimed ||
+		result == autoClaimResultBelowThreshold ||
+		result == autoClaimResultNothingToClaim
+}
+
+// selectTokensToClaim returns the status of each claimable token, sorted by address, and the tokens whose
+// claimable amount reaches their threshold. Tokens without a threshold are only claimed, as soon as anything
+// is claimable, when claimUnlisted is set.
+func selectTokensToClaim(
+	claimableAmounts map[gethcommon.Address]*big.Int,
+	thresholds map[gethcommon.Address]*big.Int,
+	claimUnlisted bool,
+) ([]autoClaimTokenStatus, map[gethcommon.Address]bool) {
+	tokens := make([]gethcommon.Address, 0, len(claimableAmounts))
+	for token := range claimableAmounts {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return bytes.Compare(tokens[i].Bytes(), tokens[j].Bytes()) < 0
+	})
+
+	statuses := make([]autoClaimTokenStatus, 0, len(tokens))
+	tokensToClaim := make(map[gethcommon.Address]bool)
+	for _, token := range tokens {
+		amount := claimableAmounts[token]
+		status := autoClaimTokenStatus{TokenAddress: token.Hex(), ClaimableAmount: amount.String()}
+		threshold, ok := thresholds[token]
+		if !ok && claimUnlisted {
+			threshold, ok = big.NewInt(0), true
+		}
+		if ok {
+			status.Threshold = threshold.String()
+			status.Claimed = amount.Sign() > 0 && amount.Cmp(threshold) >= 0
+		}
+		if status.Claimed {
+			tokensToClaim[token] = true
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, tokensToClaim
+}
+
+// parseClaimThresholds parses a comma separated list of <token address>:<amount>
+func parseClaimThresholds(value string) (map[gethcommon.Address]*big.Int, error) {
+	thresholds := make(map[gethcommon.Address]*big.Int)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		token, amount, found := strings.Cut(item, ":")
+		if !found || !gethcommon.IsHexAddress(strings.TrimSpace(token)) {
+			return nil, fmt.Errorf("invalid claim threshold %q, expected <token address>:<amount>", item)
+		}
+		threshold, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+		if !ok || threshold.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount of claim threshold %q", item)
+		}
+		thresholds[gethcommon.HexToAddress(strings.TrimSpace(token))] = threshold
+	}
+	return thresholds, nil
+}
+
+// gweiToWei converts a gas price in gwei to wei
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
+
+// readAutoClaimStatus reads the status of the previous check. It returns nil if there is none.
+func readAutoClaimStatus(filePath string) (*autoClaimStatus, error) {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var status autoClaimStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func writeAutoClaimStatus(status *autoClaimStatus, filePath string) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.WriteToFile(data, filePath)
+}
+
+func readAndValidateAutoClaimConfig(cCtx *cli.Context, logger logging.Logger) (*AutoClaimConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	earnerAddress := gethcommon.HexToAddress(cCtx.String(EarnerAddressFlag.Name))
+	tokenAddresses := getValidHexAddresses(strings.Split(cCtx.String(TokenAddressesFlag.Name), ","))
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	maxGasPrice := cCtx.Float64(MaxGasPriceFlag.Name)
+	pollInterval := cCtx.Duration(PollIntervalFlag.Name)
+	once := cCtx.Bool(OnceFlag.Name)
+	statusFile := cCtx.String(StatusFileFlag.Name)
+	claimUnlistedTokens := cCtx.Bool(ClaimUnlistedTokensFlag.Name)
+
+	if earnerAddress == utils.ZeroAddress {
+		return nil, errors.New("earner address is required")
+	}
+	if maxGasPrice < 0 {
+		return nil, errors.New("max gas price can't be negative")
+	}
+	if !once && pollInterval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+	if common.IsEmptyString(statusFile) {
+		return nil, errors.New("status file is required")
+	}
+
+	claimThresholds, err := parseClaimThresholds(cCtx.String(ClaimThresholdsFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if len(claimThresholds) == 0 && !claimUnlistedTokens {
+		return nil, errors.New("no token to claim, use --claim-thresholds or --claim-unlisted-tokens")
+	}
+	if len(tokenAddresses) > 0 {
+		for token := range claimThresholds {
+			if !slices.Contains(tokenAddresses, token) {
+				return nil, fmt.Errorf("claim threshold of token %s which is not in the token addresses", token.Hex())
+			}
+		}
+	}
+
+	tokenPolicy, err := readTokenPolicyFile(cCtx.String(TokenPolicyFileFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	tokenRecipients, err := parseTokenRecipients(cCtx.String(TokenRecipientsFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTokenRecipients(tokenRecipients, tokenPolicy); err != nil {
+		return nil, err
+	}
+
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	recipientAddress := gethcommon.HexToAddress(cCtx.String(RecipientAddressFlag.Name))
+	if recipientAddress == utils.ZeroAddress {
+		recipientAddress = earnerAddress
+	}
+	logger.Infof("Using rewards recipient address: %s", recipientAddress.String())
+
+	claimerAddress := gethcommon.HexToAddress(cCtx.String(ClaimerAddressFlag.Name))
+	if claimerAddress == utils.ZeroAddress {
+		claimerAddress = earnerAddress
+	}
+	logger.Infof("Using rewards claimer address: %s", claimerAddress.String())
+
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	if common.IsEmptyString(environment) {
+		environment = common.GetEnvFromNetwork(network)
+	}
+	logger.Debugf("Using network %s and environment: %s", network, environment)
+
+	// Unlike the claim command, a signer is always required since claims are broadcasted
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get signer config", err)
+	}
+
+	sidecarUrl := cCtx.String(SidecarUrlFlag.Name)
+	if common.IsEmptyString(sidecarUrl) {
+		sidecarUrl = getSidecarUrl(network)
+		if common.IsEmptyString(sidecarUrl) {
+			return nil, errors.New("sidecar URL not provided")
+		}
+	}
+	logger.Debugf("Using Sidecar URL: %s", sidecarUrl)
+
+	return &AutoClaimConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		EarnerAddress:             earnerAddress,
+		RecipientAddress:          recipientAddress,
+		ClaimerAddress:            claimerAddress,
+		TokenAddresses:            tokenAddresses,
+		TokenRecipients:           tokenRecipients,
+		TokenPolicy:               tokenPolicy,
+		ClaimThresholds:           claimThresholds,
+		ClaimUnlistedTokens:       claimUnlistedTokens,
+		MaxGasPrice:               gweiToWei(maxGasPrice),
+		PollInterval:              pollInterval,
+		Once:                      once,
+		StatusFile:                statusFile,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		ChainID:                   chainID,
+		Environment:               environment,
+		SignerConfig:              signerConfig,
+		SidecarHttpRpcURL:         sidecarUrl,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package rewards
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClaimThresholds(t *testing.T) {
+	tokenA := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	tokenB := gethcommon.HexToAddress("0xb000000000000000000000000000000000000002")
+
+	thresholds, err := parseClaimThresholds(tokenA.Hex() + ":1000, " + tokenB.Hex() + ":0")
+	assert.NoError(t, err)
+	assert.Equal(t, map[gethcommon.Address]*big.Int{tokenA: big.NewInt(1000), tokenB: big.NewInt(0)}, thresholds)
+
+	thresholds, err = parseClaimThresholds("")
+	assert.NoError(t, err)
+	assert.Empty(t, thresholds)
+
+	for _, value := range []string{tokenA.Hex(), "0x1234:10", tokenA.Hex() + ":-1", tokenA.Hex() + ":1e18"} {
+		_, err := parseClaimThresholds(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSelectTokensToClaim(t *testing.T) {
+	tokenA := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	tokenB := gethcommon.HexToAddress("0xb000000000000000000000000000000000000002")
+	tokenC := gethcommon.HexToAddress("0xc000000000000000000000000000000000000003")
+	claimableAmounts := map[gethcommon.Address]*big.Int{
+		tokenC: big.NewInt(0),
+		tokenB: big.NewInt(500),
+		tokenA: big.NewInt(1000),
+	}
+	thresholds := map[gethcommon.Address]*big.Int{
+		tokenA: big.NewInt(1000),
+		tokenB: big.NewInt(501),
+	}
+
+	statuses, tokensToClaim := selectTokensToClaim(claimableAmounts, thresholds, false)
+	assert.Equal(t, map[gethcommon.Address]bool{tokenA: true}, tokensToClaim)
+	assert.Equal(t, []autoClaimTokenStatus{
+		{TokenAddress: tokenA.Hex(), ClaimableAmount: "1000", Threshold: "1000", Claimed: true},
+		{TokenAddress: tokenB.Hex(), ClaimableAmount: "500", Threshold: "501", Claimed: false},
+		{TokenAddress: tokenC.Hex(), ClaimableAmount: "0", Claimed: false},
+	}, statuses)
+
+	// Tokens without a threshold are ignored unless unlisted tokens are claimed
+	_, tokensToClaim = selectTokensToClaim(claimableAmounts, map[gethcommon.Address]*big.Int{tokenA: big.NewInt(1)}, false)
+	assert.Equal(t, map[gethcommon.Address]bool{tokenA: true}, tokensToClaim)
+
+	_, tokensToClaim = selectTokensToClaim(claimableAmounts, nil, true)
+	assert.Equal(t, map[gethcommon.Address]bool{tokenA: true, tokenB: true}, tokensToClaim)
+}
+
+func TestGweiToWei(t *testing.T) {
+	assert.Equal(t, big.NewInt(0), gweiToWei(0))
+	assert.Equal(t, big.NewInt(30_000_000_000), gweiToWei(30))
+	assert.Equal(t, big.NewInt(500_000_000), gweiToWei(0.5))
+}
+
+func TestAutoClaimStatus(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "status.json")
+
+	status, err := readAutoClaimStatus(filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+
+	written := &autoClaimStatus{
+		UpdatedAt:       "2025-01-01T00:00:00Z",
+		EarnerAddress:   "0x1000000000000000000000000000000000000001",
+		RootIndex:       3,
+		Result:          autoClaimResultBelowThreshold,
+		Tokens:          []autoClaimTokenStatus{{TokenAddress: "0xa", ClaimableAmount: "1", Threshold: "2"}},
+		LastClaimTxHash: "0x01",
+	}
+	assert.NoError(t, writeAutoClaimStatus(written, filePath))
+	status, err = readAutoClaimStatus(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, written, status)
+	assert.True(t, isAutoClaimSettled(status.Result))
+	assert.False(t, isAutoClaimSettled(autoClaimResultGasPriceTooHigh))
+}
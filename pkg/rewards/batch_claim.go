@@ -0,0 +1,467 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	batchClaimStatusClaimed   = "claimed"
+	batchClaimStatusGenerated = "generated"
+	batchClaimStatusSkipped   = "skipped"
+	batchClaimStatusFailed    = "failed"
+	// batchClaimStatusPartial is recorded for an earner whose claims to some of its recipients are pending
+	batchClaimStatusPartial = "partial"
+)
+
+type gasEstimator interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// batchClaimEntry is an entry of the batch claim file. Empty token addresses claim all tokens claimable by
+// the earner, and an empty recipient address defaults to the --recipient-address flag or the earner.
+type batchClaimEntry struct {
+	EarnerAddress    string   `yaml:"earner_address"`
+	TokenAddresses   []string `yaml:"token_addresses"`
+	RecipientAddress string   `yaml:"recipient_address"`
+	TokenRecipients  []string `yaml:"token_recipients"`
+}
+
+// resolvedBatchClaimEntry is a validated entry of the batch claim file
+type resolvedBatchClaimEntry struct {
+	earner          gethcommon.Address
+	recipient       gethcommon.Address
+	tokens          []gethcommon.Address
+	tokenRecipients map[gethcommon.Address]gethcommon.Address
+}
+
+// batchClaimItem is a validated claim of the batch along with its estimated gas
+type batchClaimItem struct {
+	earner    gethcommon.Address
+	recipient gethcommon.Address
+	proof     *rewardsV1.Proof
+	gas       uint64
+}
+
+func batchClaim(
+	ctx context.Context,
+	logger logging.Logger,
+	ethClient *ethclient.Client,
+	elReader *elcontracts.ChainReader,
+	rootReader distributionRootReader,
+	config *ClaimConfig,
+	p utils.Prompter,
+	proofGenerator claimProofGenerator,
+	rootIndex uint32,
+) error {
+	entries, err := readBatchClaimFile(config.BatchClaimFile)
+	if err != nil {
+		return err
+	}
+
+	progress := &batchClaimProgress{RootIndex: rootIndex, Earners: make(map[string]*batchClaimStatus)}
+	if config.Broadcast {
+		previous, err := readBatchClaimProgress(config.BatchProgressFile)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to read batch progress file", err)
+		}
+		if previous != nil && previous.RootIndex == rootIndex {
+			logger.Infof("Resuming batch claim for root index %d from %s", rootIndex, config.BatchProgressFile)
+			progress = previous
+		}
+	}
+
+	report := make([]batchClaimReportRow, 0, len(entries))
+	addReport := func(earner, recipient, status, detail string) {
+		report = append(report, batchClaimReportRow{
+			EarnerAddress:    earner,
+			RecipientAddress: recipient,
+			Status:           status,
+			Detail:           detail,
+		})
+	}
+
+	seen := make(map[gethcommon.Address]bool, len(entries))
+	items := make([]batchClaimItem, 0, len(entries))
+	statuses := make(map[gethcommon.Address]*batchClaimStatus)
+	for _, entry := range entries {
+		resolved, err := resolveBatchClaimEntry(
+			entry,
+			config.RecipientAddress,
+			config.TokenRecipients,
+			config.TokenPolicy,
+		)
+		if err != nil {
+			addReport(entry.EarnerAddress, entry.RecipientAddress, batchClaimStatusFailed, err.Error())
+			continue
+		}
+		earnerAddr, recipientAddr := resolved.earner, resolved.recipient
+		if seen[earnerAddr] {
+			addReport(earnerAddr.Hex(), recipientAddr.Hex(), batchClaimStatusSkipped, "duplicate earner in batch file")
+			continue
+		}
+		seen[earnerAddr] = true
+
+		if status, ok := progress.Earners[earnerAddr.Hex()]; ok && status.Status == batchClaimStatusClaimed {
+			addReport(earnerAddr.Hex(), status.RecipientAddress, batchClaimStatusClaimed, status.TxHash)
+			continue
+		}
+
+		if err := validateClaimer(ctx, elReader, earnerAddr, config.ClaimerAddress); err != nil {
+			addReport(earnerAddr.Hex(), recipientAddr.Hex(), batchClaimStatusFailed, err.Error())
+			continue
+		}
+
+		proof, err := generateClaimPayload(
+			ctx,
+			elReader,
+			rootReader,
+			logger,
+			earnerAddr,
+			resolved.tokens,
+			config.TokenPolicy,
+			proofGenerator,
+		)
+		if errors.Is(err, errNoClaimableTokens) {
+			logger.Infof("Skipping earner %s with nothing to claim", earnerAddr.Hex())
+			addReport(earnerAddr.Hex(), recipientAddr.Hex(), batchClaimStatusSkipped, "no claimable rewards")
+			continue
+		}
+		if err != nil {
+			logger.Warnf("Failed to process claim for earner %s: %v", earnerAddr.String(), err)
+			addReport(earnerAddr.Hex(), recipientAddr.Hex(), batchClaimStatusFailed, err.Error())
+			continue
+		}
+
+		// Tokens with a recipient of their own are claimed separately, in the chunks of their recipient
+		for _, recipientProof := range splitProofByRecipient(proof, resolved.tokenRecipients, recipientAddr) {
+			recipient := recipientProof.recipient
+			gas, err := estimateClaimGas(ctx, ethClient, config, recipientProof.proof, recipient)
+			if err != nil {
+				logger.Warnf("Failed to estimate gas of claim for earner %s: %v", earnerAddr.String(), err)
+				addReport(earnerAddr.Hex(), recipient.Hex(), batchClaimStatusFailed, err.Error())
+				// The earner must not be recorded as claimed when its claims to other recipients succeed
+				statuses[earnerAddr] = mergeBatchClaimStatus(statuses[earnerAddr], &batchClaimStatus{
+					Status:           batchClaimStatusFailed,
+					RecipientAddress: recipient.Hex(),
+					Error:            err.Error(),
+				}, false)
+				progress.Earners[earnerAddr.Hex()] = statuses[earnerAddr]
+				continue
+			}
+			items = append(items, batchClaimItem{
+				earner:    earnerAddr,
+				recipient: recipient,
+				proof:     recipientProof.proof,
+				gas:       gas,
+			})
+		}
+	}
+
+	// An earner has an item per recipient, and is only claimed once all of its items are
+	remaining := make(map[gethcommon.Address]int)
+	for _, item := range items {
+		remaining[item.earner]++
+	}
+
+	chunks := chunkClaims(items, config.BatchGasLimit)
+	if len(chunks) > 1 {
+		logger.Infof("Splitting %d claims into %d transactions", len(items), len(chunks))
+	}
+	for i, chunk := range chunks {
+		proofs := make([]*rewardsV1.Proof, 0, len(chunk))
+		for _, item := range chunk {
+			proofs = append(proofs, item.proof)
+		}
+
+		recipient := chunk[0].recipient
+		output := getChunkOutputFile(config.Output, i, len(chunks))
+		txHash, err := broadcastClaims(config, ethClient, logger, p, ctx, proofs, recipient, output)
+
+		status, detail := batchClaimStatusGenerated, output
+		if config.Broadcast {
+			status, detail = batchClaimStatusClaimed, txHash.Hex()
+		}
+		if err != nil {
+			logger.Warnf("Failed to claim transaction %d of %d: %v", i+1, len(chunks), err)
+			status, detail = batchClaimStatusFailed, err.Error()
+		}
+		for _, item := range chunk {
+			addReport(item.earner.Hex(), recipient.Hex(), status, detail)
+			itemStatus := &batchClaimStatus{Status: status, RecipientAddress: recipient.Hex()}
+			if txHash != (gethcommon.Hash{}) {
+				itemStatus.TxHash = txHash.Hex()
+			}
+			if err != nil {
+				itemStatus.Error = err.Error()
+			}
+			remaining[item.earner]--
+			statuses[item.earner] = mergeBatchClaimStatus(statuses[item.earner], itemStatus, remaining[item.earner] == 0)
+			progress.Earners[item.earner.Hex()] = statuses[item.earner]
+		}
+
+		// Progress is written after every transaction so that a failed batch can be resumed
+		if config.Broadcast {
+			if err := writeBatchClaimProgress(progress, config.BatchProgressFile); err != nil {
+				return eigenSdkUtils.WrapError("failed to write batch progress file", err)
+			}
+		}
+	}
+
+	if !config.IsSilent {
+		printBatchClaimReport(report)
+	}
+
+	failed := 0
+	for _, row := range report {
+		if row.Status == batchClaimStatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		if config.Broadcast {
+			return fmt.Errorf(
+				"%d of %d batch claims failed. Run the command again to retry them, progress is saved in %s",
+				failed,
+				len(report),
+				config.BatchProgressFile,
+			)
+		}
+		return fmt.Errorf("%d of %d batch claims failed", failed, len(report))
+	}
+	return nil
+}
+
+func readBatchClaimFile(filePath string) ([]batchClaimEntry, error) {
+	yamlFile, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to read YAML config file", err)
+	}
+
+	var entries []batchClaimEntry
+	err = yaml.Unmarshal(yamlFile, &entries)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to parse YAML config", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("batch claim file has no claims")
+	}
+	return entries, nil
+}
+
+// resolveBatchClaimEntry validates the addresses of an entry and returns its earner, recipient, tokens and
+// token recipients. The recipient is the entry's own recipient, then the default recipient, then the earner.
+// The token recipients are the entry's own token recipients. An entry without any gets the default token
+// recipients, unless it has a recipient of its own, so that all of its tokens go to that recipient.
+func resolveBatchClaimEntry(
+	entry batchClaimEntry,
+	defaultRecipient gethcommon.Address,
+	defaultTokenRecipients map[gethcommon.Address]gethcommon.Address,
+	policy *tokenPolicy,
+) (*resolvedBatchClaimEntry, error) {
+	if !gethcommon.IsHexAddress(entry.EarnerAddress) {
+		return nil, fmt.Errorf("invalid earner address %q", entry.EarnerAddress)
+	}
+	resolved := &resolvedBatchClaimEntry{
+		earner:          gethcommon.HexToAddress(entry.EarnerAddress),
+		recipient:       defaultRecipient,
+		tokenRecipients: defaultTokenRecipients,
+	}
+
+	if !common.IsEmptyString(entry.RecipientAddress) {
+		if !gethcommon.IsHexAddress(entry.RecipientAddress) {
+			return nil, fmt.Errorf("invalid recipient address %q", entry.RecipientAddress)
+		}
+		resolved.recipient = gethcommon.HexToAddress(entry.RecipientAddress)
+		resolved.tokenRecipients = nil
+	}
+	if resolved.recipient == utils.ZeroAddress {
+		resolved.recipient = resolved.earner
+	}
+
+	if len(entry.TokenRecipients) > 0 {
+		tokenRecipients, err := parseTokenRecipients(strings.Join(entry.TokenRecipients, ","))
+		if err != nil {
+			return nil, err
+		}
+		if err := validateTokenRecipients(tokenRecipients, policy); err != nil {
+			return nil, err
+		}
+		resolved.tokenRecipients = tokenRecipients
+	}
+
+	// Empty token addresses list will create a claim for all tokens claimable
+	// by the earner address.
+	for _, addr := range entry.TokenAddresses {
+		if !gethcommon.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid token address %q", addr)
+		}
+		resolved.tokens = append(resolved.tokens, gethcommon.HexToAddress(addr))
+	}
+	return resolved, nil
+}
+
+// mergeBatchClaimStatus merges the status of a claim of the earner into the status of its previous claims of
+// the run, if any. The earner is failed if any claim failed, and only claimed once its last claim is.
+func mergeBatchClaimStatus(current *batchClaimStatus, next *batchClaimStatus, last bool) *batchClaimStatus {
+	merged := *next
+	if current != nil {
+		merged.RecipientAddress = current.RecipientAddress + "," + next.RecipientAddress
+		merged.TxHash = strings.Trim(current.TxHash+","+next.TxHash, ",")
+		if current.Status == batchClaimStatusFailed {
+			merged.Status = batchClaimStatusFailed
+			merged.Error = strings.Trim(current.Error+"; "+next.Error, "; ")
+		}
+	}
+	if merged.Status == batchClaimStatusClaimed && !last {
+		merged.Status = batchClaimStatusPartial
+	}
+	return &merged
+}
+
+// estimateClaimGas estimates the gas of a transaction with the single claim, sent by the claimer
+func estimateClaimGas(
+	ctx context.Context,
+	estimator gasEstimator,
+	config *ClaimConfig,
+	proof *rewardsV1.Proof,
+	recipient gethcommon.Address,
+) (uint64, error) {
+	rewardsCoordinatorAbi, err := rewardscoordinator.ContractRewardsCoordinatorMetaData.GetAbi()
+	if err != nil {
+		return 0, err
+	}
+	elClaims := []rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{
+		convertSidecarProofToContractProof(proof),
+	}
+	data, err := rewardsCoordinatorAbi.Pack("processClaims", elClaims, recipient)
+	if err != nil {
+		return 0, eigenSdkUtils.WrapError("failed to pack claim", err)
+	}
+	gas, err := estimator.EstimateGas(ctx, ethereum.CallMsg{
+		From: config.ClaimerAddress,
+		To:   &config.RewardsCoordinatorAddress,
+		Data: data,
+	})
+	if err != nil {
+		return 0, eigenSdkUtils.WrapError("failed to estimate claim gas", err)
+	}
+	return gas, nil
+}
+
+// chunkClaims groups the claims into transactions. A transaction has a single recipient, so claims are
+// grouped by recipient first, then packed in order until the sum of their estimated gas reaches gasLimit.
+// Each estimate includes the base cost of a transaction, so the sum overestimates the gas of a chunk.
+// A claim above gasLimit on its own gets its own transaction.
+func chunkClaims(items []batchClaimItem, gasLimit uint64) [][]batchClaimItem {
+	recipients := make([]gethcommon.Address, 0)
+	byRecipient := make(map[gethcommon.Address][]batchClaimItem)
+	for _, item := range items {
+		if _, ok := byRecipient[item.recipient]; !ok {
+			recipients = append(recipients, item.recipient)
+		}
+		byRecipient[item.recipient] = append(byRecipient[item.recipient], item)
+	}
+
+	chunks := make([][]batchClaimItem, 0)
+	for _, recipient := range recipients {
+		var chunk []batchClaimItem
+		var chunkGas uint64
+		for _, item := range byRecipient[recipient] {
+			if len(chunk) > 0 && chunkGas+item.gas > gasLimit {
+				chunks = append(chunks, chunk)
+				chunk, chunkGas = nil, 0
+			}
+			chunk = append(chunk, item)
+			chunkGas += item.gas
+		}
+		if len(chunk) > 0 {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// getChunkOutputFile suffixes the output file with the index of the chunk when there are several chunks
+func getChunkOutputFile(output string, index int, count int) string {
+	if common.IsEmptyString(output) || count == 1 {
+		return output
+	}
+	ext := filepath.Ext(output)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(output, ext), index+1, ext)
+}
+
+// readBatchClaimProgress reads the progress of a previous batch claim. It returns nil if there is none.
+func readBatchClaimProgress(filePath string) (*batchClaimProgress, error) {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var progress batchClaimProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, err
+	}
+	if progress.Earners == nil {
+		progress.Earners = make(map[string]*batchClaimStatus)
+	}
+	return &progress, nil
+}
+
+func writeBatchClaimProgress(progress *batchClaimProgress, filePath string) error {
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	return common.WriteToFile(data, filePath)
+}
+
+func printBatchClaimReport(rows []batchClaimReportRow) {
+	headers := []string{"Earner Address", "Recipient Address", "Status", "Detail"}
+	widths := []int{44, 44, 10, 68}
+
+	printSeparator := func(sep string, end string) {
+		for _, width := range widths {
+			fmt.Print(sep + strings.Repeat("-", width+1))
+		}
+		fmt.Println(end)
+	}
+
+	fmt.Println()
+	printSeparator("+", "+")
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+	printSeparator("|", "|")
+	for _, row := range rows {
+		values := []string{row.EarnerAddress, row.RecipientAddress, row.Status, row.Detail}
+		for i, value := range values {
+			fmt.Printf("| %-*s", widths[i], value)
+		}
+		fmt.Println("|")
+	}
+	printSeparator("+", "+")
+}
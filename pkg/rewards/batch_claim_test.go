@@ -0,0 +1,253 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeGasEstimator struct {
+	gas uint64
+	err error
+	msg ethereum.CallMsg
+}
+
+func (f *fakeGasEstimator) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	f.msg = msg
+	return f.gas, f.err
+}
+
+func TestResolveBatchClaimEntry(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	recipient := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	defaultRecipient := gethcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	tokenRecipient := gethcommon.HexToAddress("0x4000000000000000000000000000000000000004")
+	token := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	defaultTokenRecipients := map[gethcommon.Address]gethcommon.Address{token: defaultRecipient}
+
+	tests := []struct {
+		name                    string
+		entry                   batchClaimEntry
+		defaultRecipient        gethcommon.Address
+		policy                  *tokenPolicy
+		expectedRecipient       gethcommon.Address
+		expectedTokens          []gethcommon.Address
+		expectedTokenRecipients map[gethcommon.Address]gethcommon.Address
+		expectErr               bool
+	}{
+		{
+			name:              "recipient of the entry",
+			entry:             batchClaimEntry{EarnerAddress: earner.Hex(), RecipientAddress: recipient.Hex()},
+			defaultRecipient:  defaultRecipient,
+			expectedRecipient: recipient,
+		},
+		{
+			name:                    "default recipient",
+			entry:                   batchClaimEntry{EarnerAddress: earner.Hex(), TokenAddresses: []string{token.Hex()}},
+			defaultRecipient:        defaultRecipient,
+			expectedRecipient:       defaultRecipient,
+			expectedTokens:          []gethcommon.Address{token},
+			expectedTokenRecipients: defaultTokenRecipients,
+		},
+		{
+			name:                    "earner as recipient",
+			entry:                   batchClaimEntry{EarnerAddress: earner.Hex()},
+			defaultRecipient:        utils.ZeroAddress,
+			expectedRecipient:       earner,
+			expectedTokenRecipients: defaultTokenRecipients,
+		},
+		{
+			name: "token recipients of the entry",
+			entry: batchClaimEntry{
+				EarnerAddress:    earner.Hex(),
+				RecipientAddress: recipient.Hex(),
+				TokenRecipients:  []string{token.Hex() + ":" + tokenRecipient.Hex()},
+			},
+			expectedRecipient:       recipient,
+			expectedTokenRecipients: map[gethcommon.Address]gethcommon.Address{token: tokenRecipient},
+		},
+		{
+			name: "token recipient denied by the policy",
+			entry: batchClaimEntry{
+				EarnerAddress:   earner.Hex(),
+				TokenRecipients: []string{token.Hex() + ":" + tokenRecipient.Hex()},
+			},
+			policy:    &tokenPolicy{deny: map[gethcommon.Address]bool{token: true}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid token recipient",
+			entry:     batchClaimEntry{EarnerAddress: earner.Hex(), TokenRecipients: []string{token.Hex()}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid earner",
+			entry:     batchClaimEntry{EarnerAddress: "0x1234"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid recipient",
+			entry:     batchClaimEntry{EarnerAddress: earner.Hex(), RecipientAddress: "recipient"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid token",
+			entry:     batchClaimEntry{EarnerAddress: earner.Hex(), TokenAddresses: []string{"0xa000"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := resolveBatchClaimEntry(tt.entry, tt.defaultRecipient, defaultTokenRecipients, tt.policy)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, earner, resolved.earner)
+			assert.Equal(t, tt.expectedRecipient, resolved.recipient)
+			assert.Equal(t, tt.expectedTokens, resolved.tokens)
+			assert.Equal(t, tt.expectedTokenRecipients, resolved.tokenRecipients)
+		})
+	}
+}
+
+func TestChunkClaims(t *testing.T) {
+	recipientA := gethcommon.HexToAddress("0xa")
+	recipientB := gethcommon.HexToAddress("0xb")
+	item := func(earner string, recipient gethcommon.Address, gas uint64) batchClaimItem {
+		return batchClaimItem{earner: gethcommon.HexToAddress(earner), recipient: recipient, gas: gas}
+	}
+	earners := func(chunks [][]batchClaimItem) [][]gethcommon.Address {
+		out := make([][]gethcommon.Address, 0, len(chunks))
+		for _, chunk := range chunks {
+			addresses := make([]gethcommon.Address, 0, len(chunk))
+			for _, item := range chunk {
+				addresses = append(addresses, item.earner)
+			}
+			out = append(out, addresses)
+		}
+		return out
+	}
+
+	items := []batchClaimItem{
+		item("0x1", recipientA, 400),
+		item("0x2", recipientB, 300),
+		item("0x3", recipientA, 500),
+		item("0x4", recipientA, 200),
+		item("0x5", recipientB, 1500),
+	}
+	chunks := chunkClaims(items, 1000)
+	assert.Equal(t, [][]gethcommon.Address{
+		{gethcommon.HexToAddress("0x1"), gethcommon.HexToAddress("0x3")},
+		{gethcommon.HexToAddress("0x4")},
+		{gethcommon.HexToAddress("0x2")},
+		{gethcommon.HexToAddress("0x5")},
+	}, earners(chunks))
+
+	assert.Empty(t, chunkClaims(nil, 1000))
+	assert.Len(t, chunkClaims(items, 10_000), 2)
+}
+
+func TestGetChunkOutputFile(t *testing.T) {
+	assert.Equal(t, "claims.json", getChunkOutputFile("claims.json", 0, 1))
+	assert.Equal(t, "out/claims-2.json", getChunkOutputFile("out/claims.json", 1, 3))
+	assert.Equal(t, "calldata-1", getChunkOutputFile("calldata", 0, 2))
+	assert.Equal(t, "", getChunkOutputFile("", 1, 2))
+}
+
+func TestBatchClaimProgress(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "batch.yaml.progress.json")
+
+	progress, err := readBatchClaimProgress(filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, progress)
+
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	written := &batchClaimProgress{
+		RootIndex: 7,
+		Earners: map[string]*batchClaimStatus{
+			earner.Hex(): {Status: batchClaimStatusClaimed, RecipientAddress: earner.Hex(), TxHash: "0x01"},
+		},
+	}
+	assert.NoError(t, writeBatchClaimProgress(written, filePath))
+
+	progress, err = readBatchClaimProgress(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, written, progress)
+}
+
+func TestMergeBatchClaimStatus(t *testing.T) {
+	treasury := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001").Hex()
+	operations := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002").Hex()
+
+	claimed := &batchClaimStatus{Status: batchClaimStatusClaimed, RecipientAddress: treasury, TxHash: "0x01"}
+	assert.Equal(t, claimed, mergeBatchClaimStatus(nil, claimed, true))
+
+	status := mergeBatchClaimStatus(nil, claimed, false)
+	assert.Equal(t, batchClaimStatusPartial, status.Status)
+
+	status = mergeBatchClaimStatus(status, &batchClaimStatus{
+		Status:           batchClaimStatusClaimed,
+		RecipientAddress: operations,
+		TxHash:           "0x02",
+	}, true)
+	assert.Equal(t, &batchClaimStatus{
+		Status:           batchClaimStatusClaimed,
+		RecipientAddress: treasury + "," + operations,
+		TxHash:           "0x01,0x02",
+	}, status)
+
+	failed := mergeBatchClaimStatus(nil, &batchClaimStatus{
+		Status:           batchClaimStatusFailed,
+		RecipientAddress: treasury,
+		Error:            "reverted",
+	}, false)
+	status = mergeBatchClaimStatus(failed, &batchClaimStatus{
+		Status:           batchClaimStatusClaimed,
+		RecipientAddress: operations,
+		TxHash:           "0x02",
+	}, true)
+	assert.Equal(t, batchClaimStatusFailed, status.Status)
+	assert.Equal(t, "reverted", status.Error)
+	assert.Equal(t, "0x02", status.TxHash)
+}
+
+func TestEstimateClaimGas(t *testing.T) {
+	config := &ClaimConfig{
+		ClaimerAddress:            gethcommon.HexToAddress("0x1000000000000000000000000000000000000001"),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress("0x2000000000000000000000000000000000000002"),
+	}
+	proof := &rewardsV1.Proof{
+		Root:       make([]byte, 32),
+		EarnerLeaf: &rewardsV1.EarnerLeaf{Earner: config.ClaimerAddress.Hex(), EarnerTokenRoot: make([]byte, 32)},
+		TokenLeaves: []*rewardsV1.TokenLeaf{
+			{Token: "0xa000000000000000000000000000000000000001", CumulativeEarnings: big.NewInt(100).String()},
+		},
+		TokenIndices:    []uint32{0},
+		TokenTreeProofs: [][]byte{{}},
+	}
+
+	estimator := &fakeGasEstimator{gas: 120_000}
+	gas, err := estimateClaimGas(context.Background(), estimator, config, proof, config.ClaimerAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120_000), gas)
+	assert.Equal(t, config.ClaimerAddress, estimator.msg.From)
+	assert.Equal(t, config.RewardsCoordinatorAddress, *estimator.msg.To)
+	assert.NotEmpty(t, estimator.msg.Data)
+
+	estimator = &fakeGasEstimator{err: errors.New("execution reverted")}
+	_, err = estimateClaimGas(context.Background(), estimator, config, proof, config.ClaimerAddress)
+	assert.ErrorContains(t, err, "execution reverted")
+}
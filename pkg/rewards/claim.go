@@ -6,12 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v2"
-
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/clients/sidecar"
 	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
 	utils2 "github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/utils"
@@ -47,6 +44,42 @@ type elChainReader interface {
 	GetCumulativeClaimed(ctx context.Context, earnerAddress, tokenAddress gethcommon.Address) (*big.Int, error)
 }
 
+// errNoClaimableTokens is returned when the earner has nothing to claim for the requested tokens
+var errNoClaimableTokens = errors.New("no claimable tokens found for earner")
+
+// claimWriter sends claim transactions, as implemented by elcontracts.ChainWriter
+type claimWriter interface {
+	ProcessClaim(
+		ctx context.Context,
+		claim rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim,
+		recipientAddress gethcommon.Address,
+		waitForReceipt bool,
+	) (*types.Receipt, error)
+	ProcessClaims(
+		ctx context.Context,
+		claims []rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim,
+		recipientAddress gethcommon.Address,
+		waitForReceipt bool,
+	) (*types.Receipt, error)
+}
+
+// claimProofGenerator generates the claim proof of an earner for the given tokens. All claimable tokens of
+// the earner are included if no tokens are given.
+type claimProofGenerator interface {
+	GenerateClaimProof(
+		ctx context.Context,
+		earnerAddress gethcommon.Address,
+		tokenAddresses []gethcommon.Address,
+	) (*rewardsV1.Proof, error)
+}
+
+// sidecarProofGenerator generates claim proofs with the sidecar for the root at rootIndex
+type sidecarProofGenerator struct {
+	sidecarClient rewardsV1.RewardsGatewayClient
+	rootIndex     uint32
+	logger        logging.Logger
+}
+
 type ClaimCmd struct {
 	prompter utils.Prompter
 }
@@ -80,6 +113,11 @@ func getClaimFlags() []cli.Flag {
 		&flags.VerboseFlag,
 		&flags.SilentFlag,
 		&flags.BatchClaimFile,
+		&BatchGasLimitFlag,
+		&BatchProgressFileFlag,
+		&DistributionFileFlag,
+		&TokenRecipientsFlag,
+		&TokenPolicyFileFlag,
 	}
 }
 
@@ -102,69 +140,6 @@ func convertSidecarProofToContractProof(
 	}
 }
 
-func batchClaim(
-	ctx context.Context,
-	logger logging.Logger,
-	ethClient *ethclient.Client,
-	elReader *elcontracts.ChainReader,
-	config *ClaimConfig,
-	p utils.Prompter,
-	rootIndex uint32,
-	sidecarClient rewardsV1.RewardsGatewayClient,
-	blockHeight uint64,
-) error {
-
-	yamlFile, err := os.ReadFile(config.BatchClaimFile)
-	if err != nil {
-		return eigenSdkUtils.WrapError("failed to read YAML config file", err)
-	}
-
-	var claimConfigs []struct {
-		EarnerAddress  string   `yaml:"earner_address"`
-		TokenAddresses []string `yaml:"token_addresses"`
-	}
-
-	err = yaml.Unmarshal(yamlFile, &claimConfigs)
-	if err != nil {
-		return eigenSdkUtils.WrapError("failed to parse YAML config", err)
-	}
-
-	var proofs []*rewardsV1.Proof
-
-	for _, claimConfig := range claimConfigs {
-		earnerAddr := gethcommon.HexToAddress(claimConfig.EarnerAddress)
-
-		var tokenAddrs []gethcommon.Address
-
-		// Empty token addresses list will create a claim for all tokens claimable
-		// by the earner address.
-		if len(claimConfig.TokenAddresses) != 0 {
-			for _, addr := range claimConfig.TokenAddresses {
-				tokenAddrs = append(tokenAddrs, gethcommon.HexToAddress(addr))
-			}
-		}
-
-		proof, err := generateClaimPayload(
-			ctx,
-			rootIndex,
-			elReader,
-			logger,
-			earnerAddr,
-			tokenAddrs,
-			sidecarClient,
-		)
-
-		if err != nil {
-			logger.Warnf("Failed to process claim for earner %s: %v", earnerAddr.String(), err)
-			continue
-		}
-
-		proofs = append(proofs, proof)
-	}
-
-	return broadcastClaims(config, ethClient, logger, p, ctx, proofs)
-}
-
 func getClaimableRewardsForEarner(
 	ctx context.Context,
 	earnerAddress gethcommon.Address,
@@ -197,21 +172,14 @@ func getClaimableRewardsForEarner(
 	return claimableTokensMap, nil
 }
 
-func generateClaimPayload(
+func (g *sidecarProofGenerator) GenerateClaimProof(
 	ctx context.Context,
-	rootIndex uint32,
-	elReader *elcontracts.ChainReader,
-	logger logging.Logger,
 	earnerAddress gethcommon.Address,
 	tokenAddresses []gethcommon.Address,
-	sidecarClient rewardsV1.RewardsGatewayClient,
-) (
-	*rewardsV1.Proof,
-	error,
-) {
+) (*rewardsV1.Proof, error) {
 	tokens := make([]string, 0)
 	if len(tokenAddresses) == 0 {
-		tokenMap, err := getClaimableRewardsForEarner(ctx, earnerAddress, sidecarClient)
+		tokenMap, err := getClaimableRewardsForEarner(ctx, earnerAddress, g.sidecarClient)
 		if err != nil {
 			return nil, eigenSdkUtils.WrapError("failed to get claimable rewards for earner", err)
 		}
@@ -224,20 +192,59 @@ func generateClaimPayload(
 		}
 	}
 	if len(tokens) == 0 {
-		return nil, errors.New("no claimable tokens found for earner")
+		return nil, errNoClaimableTokens
 	}
-	logger.Infof("Fetching claim proof from sidecar for earner '%s'", earnerAddress)
-	proof, err := sidecarClient.GenerateClaimProof(ctx, &rewardsV1.GenerateClaimProofRequest{
+	g.logger.Infof("Fetching claim proof from sidecar for earner '%s'", earnerAddress)
+	proof, err := g.sidecarClient.GenerateClaimProof(ctx, &rewardsV1.GenerateClaimProofRequest{
 		EarnerAddress: earnerAddress.String(),
 		Tokens:        tokens,
-		RootIndex:     wrapperspb.Int64(int64(rootIndex)),
+		RootIndex:     wrapperspb.Int64(int64(g.rootIndex)),
 	})
 	if err != nil {
 		return nil, eigenSdkUtils.WrapError("failed to get claim proof from sidecar", err)
 	}
+	return proof.Proof, nil
+}
+
+func generateClaimPayload(
+	ctx context.Context,
+	elReader *elcontracts.ChainReader,
+	rootReader distributionRootReader,
+	logger logging.Logger,
+	earnerAddress gethcommon.Address,
+	tokenAddresses []gethcommon.Address,
+	policy *tokenPolicy,
+	proofGenerator claimProofGenerator,
+) (
+	*rewardsV1.Proof,
+	error,
+) {
+	proof, err := proofGenerator.GenerateClaimProof(ctx, earnerAddress, tokenAddresses)
+	if err != nil {
+		return nil, err
+	}
+	proof = policy.filterProof(proof, logger)
+	proof, err = removeClaimedTokenLeaves(ctx, elReader, proof)
+	if err != nil {
+		return nil, err
+	}
 
 	logger.Infof("Validating claim proof for earner %s...", earnerAddress)
-	elClaim := convertSidecarProofToContractProof(proof.Proof)
+	elClaim := convertSidecarProofToContractProof(proof)
+	// Verify the proof locally against the on-chain root so that a bad proof is rejected before any
+	// calldata is produced
+	var proofRoot *[32]byte
+	if len(proof.Root) == 32 {
+		proofRoot = new([32]byte)
+		copy(proofRoot[:], proof.Root)
+	}
+	err = verifyClaimAgainstDistributionRoot(ctx, rootReader, elClaim, proofRoot)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError(
+			fmt.Sprintf("claim proof for earner %s failed verification", earnerAddress),
+			err,
+		)
+	}
 	ok, err := elReader.CheckClaim(ctx, elClaim)
 	if err != nil {
 		logger.Infof("Error encountered validating claim proof for earner %s: %v", earnerAddress, err)
@@ -248,7 +255,7 @@ func generateClaimPayload(
 	}
 	logger.Infof("Claim proof for earner %s validated successfully", earnerAddress)
 
-	return proof.Proof, nil
+	return proof, nil
 }
 
 func (c ClaimCmd) Execute(cCtx *cli.Context) error {
@@ -260,11 +267,6 @@ func (c ClaimCmd) Execute(cCtx *cli.Context) error {
 		return eigenSdkUtils.WrapError("failed to read and validate claim config", err)
 	}
 
-	sidecarClient, err := sidecar.NewSidecarRewardsClient(config.SidecarHttpRpcURL)
-	if err != nil {
-		return eigenSdkUtils.WrapError("failed to create new sidecar client", err)
-	}
-
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
 	ethClient, err := ethclient.Dial(config.RPCUrl)
@@ -282,36 +284,160 @@ func (c ClaimCmd) Execute(cCtx *cli.Context) error {
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
 	}
-
-	_, rootIndex, blockHeight, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, logger, sidecarClient)
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(
+		config.RewardsCoordinatorAddress,
+		ethClient,
+	)
 	if err != nil {
-		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
+		return eigenSdkUtils.WrapError("failed to create rewards coordinator binding", err)
+	}
+
+	var proofGenerator claimProofGenerator
+	var rootIndex uint32
+	if !common.IsEmptyString(config.DistributionFile) {
+		generator, err := newDistributionProofGenerator(
+			ctx,
+			config.DistributionFile,
+			elReader,
+			rewardsCoordinator,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to load distribution file", err)
+		}
+		proofGenerator, rootIndex = generator, generator.rootIndex
+	} else {
+		sidecarClient, err := sidecar.NewSidecarRewardsClient(config.SidecarHttpRpcURL)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to create new sidecar client", err)
+		}
+		_, rootIndex, _, err = getClaimDistributionRoot(ctx, config.ClaimTimestamp, logger, sidecarClient)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
+		}
+		proofGenerator = &sidecarProofGenerator{sidecarClient: sidecarClient, rootIndex: rootIndex, logger: logger}
 	}
 
 	if config.BatchClaimFile != "" {
-		return batchClaim(ctx, logger, ethClient, elReader, config, c.prompter, rootIndex, sidecarClient, blockHeight)
+		return batchClaim(
+			ctx,
+			logger,
+			ethClient,
+			elReader,
+			rewardsCoordinator,
+			config,
+			c.prompter,
+			proofGenerator,
+			rootIndex,
+		)
+	}
+
+	if err := validateClaimer(ctx, elReader, config.EarnerAddress, config.ClaimerAddress); err != nil {
+		return err
 	}
 
 	proof, err := generateClaimPayload(
 		ctx,
-		rootIndex,
 		elReader,
+		rewardsCoordinator,
 		logger,
 		config.EarnerAddress,
 		config.TokenAddresses,
-		sidecarClient,
+		config.TokenPolicy,
+		proofGenerator,
 	)
 
 	if err != nil {
 		return err
 	}
 
-	proofs := []*rewardsV1.Proof{proof}
-	err = broadcastClaims(config, ethClient, logger, c.prompter, ctx, proofs)
+	// Tokens with a recipient of their own are claimed in a separate transaction per recipient
+	recipientProofs := splitProofByRecipient(proof, config.TokenRecipients, config.RecipientAddress)
+	for i, recipientProof := range recipientProofs {
+		if len(recipientProofs) > 1 {
+			logger.Infof(
+				"Claim %d of %d: %d token(s) to recipient %s",
+				i+1,
+				len(recipientProofs),
+				len(recipientProof.proof.TokenLeaves),
+				recipientProof.recipient,
+			)
+		}
+		output := getChunkOutputFile(config.Output, i, len(recipientProofs))
+		proofs := []*rewardsV1.Proof{recipientProof.proof}
+		_, err = broadcastClaims(config, ethClient, logger, c.prompter, ctx, proofs, recipientProof.recipient, output)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeClaimedTokenLeaves removes the token leaves which have been fully claimed from the proof, since the
+// contract rejects a claim with any of them. The proof of each token leaf is independent of the others.
+func removeClaimedTokenLeaves(
+	ctx context.Context,
+	elReader ELReader,
+	proof *rewardsV1.Proof,
+) (*rewardsV1.Proof, error) {
+	claimableAmounts, err := getClaimableAmounts(ctx, elReader, proof)
+	if err != nil {
+		return nil, err
+	}
+	claimable := filterTokenLeaves(proof, func(leaf *rewardsV1.TokenLeaf) bool {
+		return claimableAmounts[gethcommon.HexToAddress(leaf.Token)].Sign() > 0
+	})
+	if len(claimable.TokenLeaves) == 0 {
+		return nil, errNoClaimableTokens
+	}
+	return claimable, nil
+}
+
+// getClaimableAmounts returns the amount of each token of the proof which has not been claimed yet
+func getClaimableAmounts(
+	ctx context.Context,
+	elReader ELReader,
+	proof *rewardsV1.Proof,
+) (map[gethcommon.Address]*big.Int, error) {
+	earnerAddress := gethcommon.HexToAddress(proof.EarnerLeaf.Earner)
+	claimableAmounts := make(map[gethcommon.Address]*big.Int, len(proof.TokenLeaves))
+	for _, leaf := range proof.TokenLeaves {
+		cumulativeEarnings, ok := new(big.Int).SetString(leaf.CumulativeEarnings, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid cumulative earnings %q for token %s", leaf.CumulativeEarnings, leaf.Token)
+		}
+		tokenAddress := gethcommon.HexToAddress(leaf.Token)
+		claimed, err := getCummulativeClaimedRewards(ctx, elReader, earnerAddress, tokenAddress)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to get cumulative claimed rewards", err)
+		}
+		claimableAmounts[tokenAddress] = cumulativeEarnings.Sub(cumulativeEarnings, claimed)
+	}
+	return claimableAmounts, nil
+}
 
-	return err
+// filterTokenLeaves returns a copy of the proof with only the token leaves for which keep returns true
+func filterTokenLeaves(proof *rewardsV1.Proof, keep func(leaf *rewardsV1.TokenLeaf) bool) *rewardsV1.Proof {
+	filtered := &rewardsV1.Proof{
+		Root:            proof.Root,
+		RootIndex:       proof.RootIndex,
+		EarnerIndex:     proof.EarnerIndex,
+		EarnerTreeProof: proof.EarnerTreeProof,
+		EarnerLeaf:      proof.EarnerLeaf,
+	}
+	for i, leaf := range proof.TokenLeaves {
+		if !keep(leaf) {
+			continue
+		}
+		filtered.TokenIndices = append(filtered.TokenIndices, proof.TokenIndices[i])
+		filtered.TokenTreeProofs = append(filtered.TokenTreeProofs, proof.TokenTreeProofs[i])
+		filtered.TokenLeaves = append(filtered.TokenLeaves, leaf)
+	}
+	return filtered
 }
 
+// broadcastClaims broadcasts a transaction claiming the proofs to the recipient, or outputs it if the
+// broadcast flag is not set. The hash of the broadcasted transaction is returned.
 func broadcastClaims(
 	config *ClaimConfig,
 	ethClient *ethclient.Client,
@@ -319,9 +445,11 @@ func broadcastClaims(
 	p utils.Prompter,
 	ctx context.Context,
 	proofs []*rewardsV1.Proof,
-) error {
+	recipientAddress gethcommon.Address,
+	output string,
+) (gethcommon.Hash, error) {
 	if len(proofs) == 0 {
-		return fmt.Errorf("at least one claim is required")
+		return gethcommon.Hash{}, fmt.Errorf("at least one claim is required")
 	}
 	// just-in-time convert proofs to the contract format.
 	elClaims := make([]rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim, 0)
@@ -344,31 +472,17 @@ func broadcastClaims(
 		)
 
 		if err != nil {
-			return eigenSdkUtils.WrapError("failed to get EL writer", err)
-		}
-
-		logger.Infof("Broadcasting claim transaction...")
-
-		var receipt *types.Receipt
-		if len(proofs) > 1 {
-			receipt, err = eLWriter.ProcessClaims(ctx, elClaims, config.RecipientAddress, true)
-		} else {
-			receipt, err = eLWriter.ProcessClaim(ctx, elClaims[0], config.RecipientAddress, true)
-		}
-
-		if err != nil {
-			return eigenSdkUtils.WrapError("failed to process claim", err)
+			return gethcommon.Hash{}, eigenSdkUtils.WrapError("failed to get EL writer", err)
 		}
 
-		logger.Infof("Claim transaction submitted successfully")
-		common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+		return submitClaims(ctx, eLWriter, logger, config.ChainID, elClaims, recipientAddress)
 	} else {
 		noSendTxOpts := common.GetNoSendTxOpts(config.ClaimerAddress)
 		_, _, contractBindings, err := elcontracts.BuildClients(elcontracts.Config{
 			RewardsCoordinatorAddress: config.RewardsCoordinatorAddress,
 		}, ethClient, nil, logger, nil)
 		if err != nil {
-			return err
+			return gethcommon.Hash{}, err
 		}
 
 		// If caller is a smart contract, we can't estimate gas using geth
@@ -381,23 +495,23 @@ func broadcastClaims(
 		}
 		var unsignedTx *types.Transaction
 		if len(elClaims) > 1 {
-			unsignedTx, err = contractBindings.RewardsCoordinator.ProcessClaims(noSendTxOpts, elClaims, config.RecipientAddress)
+			unsignedTx, err = contractBindings.RewardsCoordinator.ProcessClaims(noSendTxOpts, elClaims, recipientAddress)
 		} else {
-			unsignedTx, err = contractBindings.RewardsCoordinator.ProcessClaim(noSendTxOpts, elClaims[0], config.RecipientAddress)
+			unsignedTx, err = contractBindings.RewardsCoordinator.ProcessClaim(noSendTxOpts, elClaims[0], recipientAddress)
 		}
 		if err != nil {
-			return eigenSdkUtils.WrapError("failed to create unsigned tx", err)
+			return gethcommon.Hash{}, eigenSdkUtils.WrapError("failed to create unsigned tx", err)
 		}
 
 		if config.OutputType == utils.CallDataOutputType {
 			calldataHex := gethcommon.Bytes2Hex(unsignedTx.Data())
 
-			if !common.IsEmptyString(config.Output) {
-				err = common.WriteToFile([]byte(calldataHex), config.Output)
+			if !common.IsEmptyString(output) {
+				err = common.WriteToFile([]byte(calldataHex), output)
 				if err != nil {
-					return err
+					return gethcommon.Hash{}, err
 				}
-				logger.Infof("Call data written to file: %s", config.Output)
+				logger.Infof("Call data written to file: %s", output)
 			} else {
 				fmt.Println(calldataHex)
 			}
@@ -406,14 +520,14 @@ func broadcastClaims(
 				solidityClaim := formatProofForSolidity(claim)
 				jsonData, err := json.MarshalIndent(solidityClaim, "", "  ")
 				if err != nil {
-					return err
+					return gethcommon.Hash{}, err
 				}
-				if !common.IsEmptyString(config.Output) {
-					err = common.WriteToFile(jsonData, config.Output)
+				if !common.IsEmptyString(output) {
+					err = common.WriteToFile(jsonData, output)
 					if err != nil {
-						return err
+						return gethcommon.Hash{}, err
 					}
-					logger.Infof("Claim written to file: %s", config.Output)
+					logger.Infof("Claim written to file: %s", output)
 				} else {
 					fmt.Println(string(jsonData))
 					fmt.Println()
@@ -421,7 +535,7 @@ func broadcastClaims(
 				}
 			}
 		} else {
-			if !common.IsEmptyString(config.Output) {
+			if !common.IsEmptyString(output) {
 				fmt.Println("output file not supported for pretty output type")
 				fmt.Println()
 			}
@@ -446,7 +560,38 @@ func broadcastClaims(
 		}
 	}
 
-	return nil
+	return gethcommon.Hash{}, nil
+}
+
+// submitClaims sends a transaction claiming the claims to the recipient and waits for its receipt
+func submitClaims(
+	ctx context.Context,
+	eLWriter claimWriter,
+	logger logging.Logger,
+	chainID *big.Int,
+	elClaims []rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim,
+	recipientAddress gethcommon.Address,
+) (gethcommon.Hash, error) {
+	logger.Infof("Broadcasting claim transaction...")
+
+	var receipt *types.Receipt
+	var err error
+	if len(elClaims) > 1 {
+		receipt, err = eLWriter.ProcessClaims(ctx, elClaims, recipientAddress, true)
+	} else {
+		receipt, err = eLWriter.ProcessClaim(ctx, elClaims[0], recipientAddress, true)
+	}
+
+	if err != nil {
+		return gethcommon.Hash{}, eigenSdkUtils.WrapError("failed to process claim", err)
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return receipt.TxHash, fmt.Errorf("claim transaction %s reverted", receipt.TxHash.Hex())
+	}
+
+	logger.Infof("Claim transaction submitted successfully")
+	common.PrintTransactionInfo(receipt.TxHash.String(), chainID)
+	return receipt.TxHash, nil
 }
 
 // filterClaimableTokens to filter out tokens that have been fully claimed
@@ -577,8 +722,20 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
 	isSilent := cCtx.Bool(flags.SilentFlag.Name)
 	batchClaimFile := cCtx.String(flags.BatchClaimFile.Name)
+	distributionFile := cCtx.String(DistributionFileFlag.Name)
+
+	tokenPolicy, err := readTokenPolicyFile(cCtx.String(TokenPolicyFileFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	tokenRecipients, err := parseTokenRecipients(cCtx.String(TokenRecipientsFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTokenRecipients(tokenRecipients, tokenPolicy); err != nil {
+		return nil, err
+	}
 
-	var err error
 	if common.IsEmptyString(rewardsCoordinatorAddress) {
 		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(utils.NetworkNameToChainId(network))
 		if err != nil {
@@ -591,16 +748,36 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 	logger.Debugf("Using claim timestamp from user: %s", claimTimestamp)
 
 	recipientAddress := gethcommon.HexToAddress(cCtx.String(RecipientAddressFlag.Name))
-	if recipientAddress == utils.ZeroAddress {
-		logger.Infof(
-			"Recipient address not provided, using earner address (%s) as recipient address",
-			earnerAddress.String(),
-		)
-		recipientAddress = earnerAddress
+	if recipientAddress == utils.ZeroAddress && !common.IsEmptyString(batchClaimFile) {
+		// Each claim of the batch defaults to its own recipient or earner address
+		logger.Infof("Recipient address not provided, using the recipient or earner address of each batch claim")
+	} else {
+		if recipientAddress == utils.ZeroAddress {
+			logger.Infof(
+				"Recipient address not provided, using earner address (%s) as recipient address",
+				earnerAddress.String(),
+			)
+			recipientAddress = earnerAddress
+		}
+		logger.Infof("Using rewards recipient address: %s", recipientAddress.String())
+	}
+
+	batchGasLimit := cCtx.Uint64(BatchGasLimitFlag.Name)
+	batchProgressFile := cCtx.String(BatchProgressFileFlag.Name)
+	if !common.IsEmptyString(batchClaimFile) {
+		if batchGasLimit == 0 {
+			return nil, errors.New("batch gas limit must be greater than 0")
+		}
+		if common.IsEmptyString(batchProgressFile) {
+			batchProgressFile = batchClaimFile + ".progress.json"
+		}
 	}
-	logger.Infof("Using rewards recipient address: %s", recipientAddress.String())
 
 	claimerAddress := gethcommon.HexToAddress(cCtx.String(ClaimerAddressFlag.Name))
+	if claimerAddress == utils.ZeroAddress && !common.IsEmptyString(batchClaimFile) {
+		// The claims of a batch are sent together, so they all need the same claimer
+		return nil, errors.New("claimer address is required for batch claims, use --claimer-address")
+	}
 	if claimerAddress == utils.ZeroAddress {
 		logger.Infof(
 			"Claimer address not provided, using earner address (%s) as claimer address",
@@ -626,15 +803,18 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		logger.Debugf("Failed to get signer config: %s", err)
 	}
 
+	// The sidecar is not used when the proofs are generated from a distribution file
 	sidecarUrl := cCtx.String(SidecarUrlFlag.Name)
-	if common.IsEmptyString(sidecarUrl) {
-		sidecarUrl = getSidecarUrl(network)
-
+	if common.IsEmptyString(distributionFile) {
 		if common.IsEmptyString(sidecarUrl) {
-			return nil, errors.New("sidecar URL not provided")
+			sidecarUrl = getSidecarUrl(network)
+
+			if common.IsEmptyString(sidecarUrl) {
+				return nil, errors.New("sidecar URL not provided. Use --distribution-file to claim without the sidecar")
+			}
 		}
+		logger.Debugf("Using Sidecar URL: %s", sidecarUrl)
 	}
-	logger.Debugf("Using Sidecar URL: %s", sidecarUrl)
 
 	return &ClaimConfig{
 		Network:                   network,
@@ -644,6 +824,8 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		OutputType:                outputType,
 		Broadcast:                 broadcast,
 		TokenAddresses:            validTokenAddresses,
+		TokenRecipients:           tokenRecipients,
+		TokenPolicy:               tokenPolicy,
 		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
 		ChainID:                   chainID,
 		Environment:               environment,
@@ -653,6 +835,9 @@ func readAndValidateClaimConfig(cCtx *cli.Context, logger logging.Logger) (*Clai
 		ClaimerAddress:            claimerAddress,
 		IsSilent:                  isSilent,
 		BatchClaimFile:            batchClaimFile,
+		BatchGasLimit:             batchGasLimit,
+		BatchProgressFile:         batchProgressFile,
+		DistributionFile:          distributionFile,
 		SidecarHttpRpcURL:         sidecarUrl,
 	}, nil
 }
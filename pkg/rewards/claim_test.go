@@ -224,6 +224,23 @@ func TestReadAndValidateConfig_NoClaimerProvided(t *testing.T) {
 	assert.Equal(t, common.HexToAddress(earnerAddress), config.ClaimerAddress)
 }
 
+func TestReadAndValidateConfig_BatchClaimWithoutClaimer(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String(flags.ETHRpcUrlFlag.Name, "rpc", "")
+	fs.String(flags.BatchClaimFile.Name, "batch-claims.yaml", "")
+	fs.String(RewardsCoordinatorAddressFlag.Name, "0x1234", "")
+	fs.String(ClaimTimestampFlag.Name, "latest", "")
+	fs.String(SidecarUrlFlag.Name, "sidecar", "")
+	fs.Uint64(BatchGasLimitFlag.Name, 1_000_000, "")
+	cliCtx := cli.NewContext(nil, fs, nil)
+
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	_, err := readAndValidateClaimConfig(cliCtx, logger)
+
+	assert.ErrorContains(t, err, "--claimer-address")
+}
+
 func TestReadAndValidateConfig_ClaimerProvided(t *testing.T) {
 	claimerAddress := testutils.GenerateRandomEthereumAddressString()
 	fs := flag.NewFlagSet("test", flag.ContinueOnError)
@@ -481,3 +498,33 @@ func TestFilterClaimableTokens(t *testing.T) {
 func newBigInt(value int64) *distribution.BigInt {
 	return &distribution.BigInt{Int: big.NewInt(value)}
 }
+
+func TestRemoveClaimedTokenLeaves(t *testing.T) {
+	earner := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	tokenA := common.HexToAddress("0xa000000000000000000000000000000000000001")
+	tokenB := common.HexToAddress("0xb000000000000000000000000000000000000002")
+	proof := &rewards.Proof{
+		EarnerLeaf: &rewards.EarnerLeaf{Earner: earner.Hex()},
+		TokenLeaves: []*rewards.TokenLeaf{
+			{Token: tokenA.Hex(), CumulativeEarnings: "100"},
+			{Token: tokenB.Hex(), CumulativeEarnings: "200"},
+		},
+		TokenIndices:    []uint32{0, 1},
+		TokenTreeProofs: [][]byte{{0x0a}, {0x0b}},
+	}
+
+	elReader := &fakeELReader{
+		earnerTokenClaimedMap: map[common.Address]map[common.Address]*big.Int{
+			earner: {tokenA: big.NewInt(100), tokenB: big.NewInt(150)},
+		},
+	}
+	claimable, err := removeClaimedTokenLeaves(context.Background(), elReader, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1}, claimable.TokenIndices)
+	assert.Equal(t, [][]byte{{0x0b}}, claimable.TokenTreeProofs)
+	assert.Equal(t, tokenB.Hex(), claimable.TokenLeaves[0].Token)
+
+	elReader.earnerTokenClaimedMap[earner][tokenB] = big.NewInt(200)
+	_, err = removeClaimedTokenLeaves(context.Background(), elReader, proof)
+	assert.ErrorIs(t, err, errNoClaimableTokens)
+}
@@ -0,0 +1,251 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/wealdtech/go-merkletree/v2"
+)
+
+// distributionProofGenerator generates claim proofs from a local distribution snapshot. The snapshot is
+// merklized once and its root must be posted on-chain.
+type distributionProofGenerator struct {
+	distribution *distribution.Distribution
+	accountTree  *merkletree.MerkleTree
+	tokenTrees   map[gethcommon.Address]*merkletree.MerkleTree
+	root         [32]byte
+	rootIndex    uint32
+	elReader     elChainReader
+	logger       logging.Logger
+}
+
+func newDistributionProofGenerator(
+	ctx context.Context,
+	filePath string,
+	elReader elChainReader,
+	rootReader distributionRootReader,
+	logger logging.Logger,
+) (*distributionProofGenerator, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	distro, err := parseDistribution(data)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to parse distribution", err)
+	}
+	return newDistributionProofGeneratorFromDistribution(ctx, distro, elReader, rootReader, logger)
+}
+
+func newDistributionProofGeneratorFromDistribution(
+	ctx context.Context,
+	distro *distribution.Distribution,
+	elReader elChainReader,
+	rootReader distributionRootReader,
+	logger logging.Logger,
+) (*distributionProofGenerator, error) {
+	accountTree, tokenTrees, err := distro.Merklize()
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to merklize distribution", err)
+	}
+	var root [32]byte
+	copy(root[:], accountTree.Root())
+	logger.Infof("Distribution root: %s", hexutil.Encode(root[:]))
+
+	rootIndex, err := elReader.GetRootIndexFromHash(ctx, root)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError(
+			fmt.Sprintf("distribution root %s is not posted on-chain", hexutil.Encode(root[:])),
+			err,
+		)
+	}
+	distributionRoot, err := rootReader.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		new(big.Int).SetUint64(uint64(rootIndex)),
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError(fmt.Sprintf("failed to get distribution root at index %d", rootIndex), err)
+	}
+	if distributionRoot.Root != root {
+		return nil, fmt.Errorf(
+			"on-chain root at index %d is %s, expected %s",
+			rootIndex,
+			hexutil.Encode(distributionRoot.Root[:]),
+			hexutil.Encode(root[:]),
+		)
+	}
+	if distributionRoot.Disabled {
+		return nil, fmt.Errorf("distribution root at index %d is disabled", rootIndex)
+	}
+	activatedAt := time.Unix(int64(distributionRoot.ActivatedAt), 0).UTC()
+	if activatedAt.After(time.Now()) {
+		return nil, fmt.Errorf(
+			"distribution root at index %d can't be claimed before it is activated at %s",
+			rootIndex,
+			activatedAt.Format(time.RFC3339),
+		)
+	}
+	logger.Infof(
+		"Distribution root found on-chain at index %d for rewards calculated until %s",
+		rootIndex,
+		time.Unix(int64(distributionRoot.RewardsCalculationEndTimestamp), 0).UTC().Format(time.DateOnly),
+	)
+
+	return &distributionProofGenerator{
+		distribution: distro,
+		accountTree:  accountTree,
+		tokenTrees:   tokenTrees,
+		root:         root,
+		rootIndex:    rootIndex,
+		elReader:     elReader,
+		logger:       logger,
+	}, nil
+}
+
+func (g *distributionProofGenerator) GenerateClaimProof(
+	ctx context.Context,
+	earnerAddress gethcommon.Address,
+	tokenAddresses []gethcommon.Address,
+) (*rewardsV1.Proof, error) {
+	earnerTokens, found := g.distribution.GetTokensForEarner(earnerAddress)
+	if !found {
+		return nil, fmt.Errorf("earner %s not found in distribution", earnerAddress.Hex())
+	}
+	tokensMap := getTokensToClaim(earnerTokens, tokenAddresses)
+	tokens, err := filterClaimableTokens(ctx, g.elReader, earnerAddress, tokensMap)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to filter claimable tokens", err)
+	}
+	if len(tokens) == 0 {
+		return nil, errNoClaimableTokens
+	}
+	// Token leaves must be in the order of the token tree, which is sorted by address
+	sort.Slice(tokens, func(i, j int) bool {
+		return bytes.Compare(tokens[i].Bytes(), tokens[j].Bytes()) < 0
+	})
+
+	g.logger.Infof("Generating claim proof from distribution for earner '%s'", earnerAddress)
+	claim, err := claimgen.GetProofForEarner(
+		g.distribution,
+		g.rootIndex,
+		g.accountTree,
+		g.tokenTrees,
+		earnerAddress,
+		tokens,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to generate claim proof", err)
+	}
+
+	tokenLeaves := make([]*rewardsV1.TokenLeaf, 0, len(claim.TokenLeaves))
+	for _, leaf := range claim.TokenLeaves {
+		tokenLeaves = append(tokenLeaves, &rewardsV1.TokenLeaf{
+			Token:              leaf.Token.Hex(),
+			CumulativeEarnings: leaf.CumulativeEarnings.String(),
+		})
+	}
+	return &rewardsV1.Proof{
+		Root:            g.root[:],
+		RootIndex:       claim.RootIndex,
+		EarnerIndex:     claim.EarnerIndex,
+		EarnerTreeProof: claim.EarnerTreeProof,
+		EarnerLeaf: &rewardsV1.EarnerLeaf{
+			Earner:          claim.EarnerLeaf.Earner.Hex(),
+			EarnerTokenRoot: claim.EarnerLeaf.EarnerTokenRoot[:],
+		},
+		TokenIndices:    claim.TokenIndices,
+		TokenTreeProofs: claim.TokenTreeProofs,
+		TokenLeaves:     tokenLeaves,
+	}, nil
+}
+
+// parseDistribution reads a distribution snapshot. Two formats are supported:
+//   - JSON lines with the cumulative amount of an earner and token per line, as published by the rewards
+//     proof store: {"earner": "0x...", "token": "0x...", "cumulative_amount": "1000"}
+//   - a JSON object mapping each earner to its tokens and cumulative amounts: {"0x...": {"0x...": 1000}}
+//
+// Earners and tokens are sorted, so the order in the file does not matter.
+func parseDistribution(data []byte) (*distribution.Distribution, error) {
+	lines := make([]*distribution.EarnerLine, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for {
+		var value map[string]json.RawMessage
+		err := decoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := value["earner"]; ok {
+			var line distribution.EarnerLine
+			if err := remarshal(value, &line); err != nil {
+				return nil, err
+			}
+			lines = append(lines, &line)
+			continue
+		}
+
+		for earner, tokensJson := range value {
+			var tokens map[string]json.Number
+			if err := json.Unmarshal(tokensJson, &tokens); err != nil {
+				return nil, eigenSdkUtils.WrapError(fmt.Sprintf("invalid tokens of earner %s", earner), err)
+			}
+			for token, amount := range tokens {
+				lines = append(lines, &distribution.EarnerLine{
+					Earner:           earner,
+					Token:            token,
+					CumulativeAmount: amount.String(),
+				})
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("distribution is empty")
+	}
+
+	for _, line := range lines {
+		if !gethcommon.IsHexAddress(line.Earner) || !gethcommon.IsHexAddress(line.Token) {
+			return nil, fmt.Errorf("invalid earner %s or token %s", line.Earner, line.Token)
+		}
+		// LoadLines sorts the lines as strings, which is the address order only for the same case
+		line.Earner = strings.ToLower(gethcommon.HexToAddress(line.Earner).Hex())
+		line.Token = strings.ToLower(gethcommon.HexToAddress(line.Token).Hex())
+	}
+
+	distro := distribution.NewDistribution()
+	if err := distro.LoadLines(lines); err != nil {
+		return nil, err
+	}
+	return distro, nil
+}
+
+func remarshal(value interface{}, out interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
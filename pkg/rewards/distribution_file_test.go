@@ -0,0 +1,158 @@
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDistributionFile = "testdata/distribution.jsonl"
+
+const testDistributionObject = `{
+  "0x3000000000000000000000000000000000000003": {"0xA000000000000000000000000000000000000001": "3000"},
+  "0x1000000000000000000000000000000000000001": {"0xb000000000000000000000000000000000000002": 1001},
+  "0x2000000000000000000000000000000000000002": {
+    "0xa000000000000000000000000000000000000001": 2000,
+    "0xB000000000000000000000000000000000000002": "2001"
+  }
+}`
+
+func TestParseDistribution(t *testing.T) {
+	data, err := os.ReadFile(testDistributionFile)
+	assert.NoError(t, err)
+	linesDistro, err := parseDistribution(data)
+	assert.NoError(t, err)
+	objectDistro, err := parseDistribution([]byte(testDistributionObject))
+	assert.NoError(t, err)
+
+	linesTree, _, err := linesDistro.Merklize()
+	assert.NoError(t, err)
+	objectTree, _, err := objectDistro.Merklize()
+	assert.NoError(t, err)
+	assert.Equal(t, linesTree.Root(), objectTree.Root())
+
+	invalidDistributions := map[string]string{
+		"empty":           " \n",
+		"invalid json":    "{",
+		"invalid earner":  `{"earner":"0x1234","token":"0xa000000000000000000000000000000000000001"}`,
+		"invalid token":   `{"0x1000000000000000000000000000000000000001": {"0xa000": "1"}}`,
+		"invalid objects": `{"0x1000000000000000000000000000000000000001": ["0xa000"]}`,
+	}
+	for name, data := range invalidDistributions {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseDistribution([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDistributionProofGenerator(t *testing.T) {
+	filePath := testDistributionFile
+	data, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	distro, err := parseDistribution(data)
+	assert.NoError(t, err)
+	accountTree, _, err := distro.Merklize()
+	assert.NoError(t, err)
+	var root [32]byte
+	copy(root[:], accountTree.Root())
+
+	earner := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	tokenA := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	tokenB := gethcommon.HexToAddress("0xb000000000000000000000000000000000000002")
+	activeRoots := []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+		{Root: [32]byte{0x01}},
+		{Root: root, ActivatedAt: uint32(time.Now().Add(-time.Hour).Unix())},
+	}
+	logger := logging.NewTextSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	t.Run("claim all tokens", func(t *testing.T) {
+		elReader := &fakeELReader{
+			roots: activeRoots,
+			// tokenA is already fully claimed
+			earnerTokenClaimedMap: map[gethcommon.Address]map[gethcommon.Address]*big.Int{
+				earner: {tokenA: big.NewInt(2000)},
+			},
+		}
+		generator, err := newDistributionProofGenerator(
+			context.Background(),
+			filePath,
+			elReader,
+			&fakeDistributionRootReader{roots: activeRoots},
+			logger,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), generator.rootIndex)
+
+		proof, err := generator.GenerateClaimProof(context.Background(), earner, nil)
+		assert.NoError(t, err)
+		assert.Len(t, proof.TokenLeaves, 1)
+		assert.Equal(t, tokenB.Hex(), proof.TokenLeaves[0].Token)
+		assert.Equal(t, "2001", proof.TokenLeaves[0].CumulativeEarnings)
+
+		solidityClaim := formatProofForSolidity(proof)
+		claim, claimRoot, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		assert.Equal(t, root, claimRoot)
+		assert.NoError(t, verifyClaimProof(claim, root))
+	})
+
+	t.Run("claim selected tokens", func(t *testing.T) {
+		elReader := &fakeELReader{roots: activeRoots}
+		generator, err := newDistributionProofGenerator(
+			context.Background(),
+			filePath,
+			elReader,
+			&fakeDistributionRootReader{roots: activeRoots},
+			logger,
+		)
+		assert.NoError(t, err)
+
+		proof, err := generator.GenerateClaimProof(context.Background(), earner, []gethcommon.Address{tokenB, tokenA})
+		assert.NoError(t, err)
+		assert.Len(t, proof.TokenLeaves, 2)
+		claim, _, err := convertSolidityClaimToContractClaim(formatProofForSolidity(proof))
+		assert.NoError(t, err)
+		assert.NoError(t, verifyClaimProof(claim, root))
+
+		_, err = generator.GenerateClaimProof(context.Background(), utils.ZeroAddress, nil)
+		assert.ErrorContains(t, err, "not found in distribution")
+	})
+
+	t.Run("root not activated", func(t *testing.T) {
+		pendingRoots := []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+			{Root: root, ActivatedAt: uint32(time.Now().Add(time.Hour).Unix())},
+		}
+		_, err := newDistributionProofGenerator(
+			context.Background(),
+			filePath,
+			&fakeELReader{roots: pendingRoots},
+			&fakeDistributionRootReader{roots: pendingRoots},
+			logger,
+		)
+		assert.ErrorContains(t, err, "activated")
+	})
+
+	t.Run("root not posted", func(t *testing.T) {
+		otherRoots := []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{{Root: [32]byte{0x01}}}
+		_, err := newDistributionProofGenerator(
+			context.Background(),
+			filePath,
+			&fakeELReader{roots: otherRoots},
+			&fakeDistributionRootReader{roots: otherRoots},
+			logger,
+		)
+		assert.ErrorContains(t, err, "on-chain root at index 0")
+	})
+}
@@ -0,0 +1,440 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/clients/sidecar"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gocarina/gocsv"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	exportRecordTypeReward = "reward"
+	exportRecordTypeClaim  = "claim"
+)
+
+// rewardsClaimedEvent is a RewardsClaimed event with the date of its block
+type rewardsClaimedEvent struct {
+	*rewardscoordinator.ContractRewardsCoordinatorRewardsClaimed
+	date string
+}
+
+func ExportCmd(p utils.Prompter) *cli.Command {
+	exportCmd := &cli.Command{
+		Name:      "export",
+		Usage:     "Export rewards and claims of earners for accounting",
+		UsageText: "export",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to export the rewards earned by earners on each snapshot date and their claims
+
+Rewards are read from the sidecar for the latest distribution root and exported with one row per earner,
+token, AVS, rewards submission type and snapshot date. Claims are read from the RewardsClaimed events of
+the rewards coordinator in the blocks of the date range. Amounts are exported in the smallest unit of the
+token and in whole tokens.
+
+If a price file is given, fiat values are computed with the price of the token on the date of the row,
+or with the price of the token without a date if there is none for the date. The price file is a CSV
+file with the columns date, token_address and price, where price is the value of a whole token:
+
+date,token_address,price
+2025-01-01,0xec53bF9167f50cDEB3Ae105f56099aaaB9061F83,3.12
+,0xec53bF9167f50cDEB3Ae105f56099aaaB9061F83,3.00
+`,
+		Flags: getExportFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return exportAction(cCtx)
+		},
+	}
+	return exportCmd
+}
+
+func getExportFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressesFlag,
+		&EnvironmentFlag,
+		&FromDateFlag,
+		&ToDateFlag,
+		&ExportFormatFlag,
+		&PriceFileFlag,
+		&RewardsCoordinatorAddressFlag,
+		&SidecarUrlFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func exportAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateExportConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate export config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	var prices map[gethcommon.Address]map[string]*big.Rat
+	if !common.IsEmptyString(config.PriceFile) {
+		prices, err = readPriceFile(config.PriceFile)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to read price file", err)
+		}
+	}
+
+	sidecarClient, err := sidecar.NewSidecarRewardsClient(config.SidecarHttpRpcURL)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new sidecar client", err)
+	}
+	_, rootIndex, _, err := getClaimDistributionRoot(ctx, LatestTimestamp, logger, sidecarClient)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
+	}
+	response, err := sidecarClient.GetRewardsByAvsForDistributionRoot(
+		ctx,
+		&rewardsV1.GetRewardsByAvsForDistributionRootRequest{RootIndex: uint64(rootIndex)},
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get rewards by AVS for distribution root", err)
+	}
+	rewards := make(map[gethcommon.Address][]rewardsBreakdownRow)
+	for _, earner := range config.EarnerAddresses {
+		rewards[earner], err = buildRewardsBreakdown(response.Rewards, earner, nil, config.FromDate, config.ToDate, nil)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to build rewards", err)
+		}
+	}
+
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(
+		config.RewardsCoordinatorAddress,
+		ethClient,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create rewards coordinator binding", err)
+	}
+	claims, err := getRewardsClaimedEvents(ctx, logger, ethClient, rewardsCoordinator, config)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get RewardsClaimed events", err)
+	}
+
+	tokens := make(map[gethcommon.Address]tokenInfo)
+	getTokenInfo := func(token gethcommon.Address) tokenInfo {
+		if info, ok := tokens[token]; ok {
+			return info
+		}
+		symbol, decimals := erc20.GetTokenSymbolAndDecimals(token, ethClient)
+		tokens[token] = tokenInfo{symbol: symbol, decimals: decimals}
+		return tokens[token]
+	}
+	rows := buildExportRows(config.EarnerAddresses, rewards, claims, getTokenInfo, prices)
+
+	var out []byte
+	if config.Format == "json" {
+		out, err = json.MarshalIndent(rows, "", "  ")
+	} else {
+		var csv string
+		csv, err = gocsv.MarshalString(&rows)
+		out = []byte(csv)
+	}
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to marshal rewards export", err)
+	}
+	if !common.IsEmptyString(config.Output) {
+		err = common.WriteToFile(out, config.Output)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Rewards export written to file: %s", config.Output)
+		return nil
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// getRewardsClaimedEvents returns the RewardsClaimed events of the earners in the blocks from the start of
+// the from date to the end of the to date
+func getRewardsClaimedEvents(
+	ctx context.Context,
+	logger logging.Logger,
+	ethClient *ethclient.Client,
+	rewardsCoordinator *rewardscoordinator.ContractRewardsCoordinator,
+	config *ExportConfig,
+) ([]rewardsClaimedEvent, error) {
+	latestBlock, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fromTime, _ := time.Parse(time.DateOnly, config.FromDate)
+	toTime, _ := time.Parse(time.DateOnly, config.ToDate)
+	fromBlock, err := common.FindBlockByTimestamp(ctx, ethClient, latestBlock, uint64(fromTime.Unix()))
+	if err != nil {
+		return nil, err
+	}
+	// The last block of the range is the one before the first block of the next day
+	toBlock, err := common.FindBlockByTimestamp(ctx, ethClient, latestBlock, uint64(toTime.AddDate(0, 0, 1).Unix()))
+	if err != nil {
+		return nil, err
+	}
+	if toBlock <= fromBlock {
+		return nil, nil
+	}
+	toBlock--
+	logger.Debugf("Reading RewardsClaimed events from block %d to %d", fromBlock, toBlock)
+
+	events := make([]rewardsClaimedEvent, 0)
+	dates := make(map[uint64]string)
+	err = common.FilterLogsInRanges(ctx, fromBlock, toBlock, func(opts *bind.FilterOpts) error {
+		iterator, err := rewardsCoordinator.FilterRewardsClaimed(opts, config.EarnerAddresses, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer iterator.Close()
+		for iterator.Next() {
+			blockNumber := iterator.Event.Raw.BlockNumber
+			if _, ok := dates[blockNumber]; !ok {
+				header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+				if err != nil {
+					return err
+				}
+				dates[blockNumber] = time.Unix(int64(header.Time), 0).UTC().Format(time.DateOnly)
+			}
+			events = append(events, rewardsClaimedEvent{
+				ContractRewardsCoordinatorRewardsClaimed: iterator.Event,
+				date:                                     dates[blockNumber],
+			})
+		}
+		return iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// buildExportRows returns the reward rows followed by the claim rows of each earner, in date order
+func buildExportRows(
+	earnerAddresses []gethcommon.Address,
+	rewards map[gethcommon.Address][]rewardsBreakdownRow,
+	claims []rewardsClaimedEvent,
+	getTokenInfo func(gethcommon.Address) tokenInfo,
+	prices map[gethcommon.Address]map[string]*big.Rat,
+) []rewardsExportRow {
+	rows := make([]rewardsExportRow, 0)
+	for _, earner := range earnerAddresses {
+		earnerRewards := rewards[earner]
+		// The breakdown is sorted with the most recent snapshot first
+		for i := len(earnerRewards) - 1; i >= 0; i-- {
+			reward := earnerRewards[i]
+			info := getTokenInfo(reward.token)
+			rows = append(rows, rewardsExportRow{
+				RecordType:      exportRecordTypeReward,
+				Date:            reward.snapshot,
+				EarnerAddress:   earner.Hex(),
+				AvsAddress:      reward.avs.Hex(),
+				RewardType:      reward.rewardType,
+				TokenAddress:    reward.token.Hex(),
+				TokenSymbol:     info.symbol,
+				Amount:          reward.amount.String(),
+				FormattedAmount: erc20.FormatAmount(reward.amount, info.decimals),
+				FiatValue:       getFiatValue(prices, reward.token, reward.snapshot, reward.amount, info.decimals),
+			})
+		}
+
+		for _, claim := range claims {
+			if claim.Earner != earner {
+				continue
+			}
+			info := getTokenInfo(claim.Token)
+			rows = append(rows, rewardsExportRow{
+				RecordType:       exportRecordTypeClaim,
+				Date:             claim.date,
+				EarnerAddress:    earner.Hex(),
+				TokenAddress:     claim.Token.Hex(),
+				TokenSymbol:      info.symbol,
+				Amount:           claim.ClaimedAmount.String(),
+				FormattedAmount:  erc20.FormatAmount(claim.ClaimedAmount, info.decimals),
+				FiatValue:        getFiatValue(prices, claim.Token, claim.date, claim.ClaimedAmount, info.decimals),
+				RecipientAddress: claim.Recipient.Hex(),
+				TxHash:           claim.Raw.TxHash.Hex(),
+				BlockNumber:      common.Uint64ToString(claim.Raw.BlockNumber),
+			})
+		}
+	}
+	return rows
+}
+
+// getFiatValue returns the fiat value of the amount with 2 decimals, or an empty string if the token has no
+// price on the date
+func getFiatValue(
+	prices map[gethcommon.Address]map[string]*big.Rat,
+	token gethcommon.Address,
+	date string,
+	amount *big.Int,
+	decimals uint8,
+) string {
+	tokenPrices, ok := prices[token]
+	if !ok {
+		return ""
+	}
+	price, ok := tokenPrices[date]
+	if !ok {
+		price, ok = tokenPrices[""]
+		if !ok {
+			return ""
+		}
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Rat).SetFrac(amount, unit)
+	return value.Mul(value, price).FloatString(2)
+}
+
+// readPriceFile returns the prices of the price file by token and date
+func readPriceFile(filePath string) (map[gethcommon.Address]map[string]*big.Rat, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []tokenPrice
+	if err := gocsv.UnmarshalFile(file, &rows); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[gethcommon.Address]map[string]*big.Rat)
+	for i, row := range rows {
+		if !gethcommon.IsHexAddress(row.TokenAddress) {
+			return nil, fmt.Errorf("row %d: invalid token address %q", i+1, row.TokenAddress)
+		}
+		date := strings.TrimSpace(row.Date)
+		if date != "" {
+			if _, err := time.Parse(time.DateOnly, date); err != nil {
+				return nil, fmt.Errorf("row %d: invalid date %q, expected YYYY-MM-DD", i+1, row.Date)
+			}
+		}
+		price, ok := new(big.Rat).SetString(strings.TrimSpace(row.Price))
+		if !ok || price.Sign() < 0 {
+			return nil, fmt.Errorf("row %d: invalid price %q", i+1, row.Price)
+		}
+
+		token := gethcommon.HexToAddress(row.TokenAddress)
+		if _, ok := prices[token]; !ok {
+			prices[token] = make(map[string]*big.Rat)
+		}
+		if _, ok := prices[token][date]; ok {
+			return nil, fmt.Errorf("row %d: duplicate price of token %s on %q", i+1, token.Hex(), date)
+		}
+		prices[token][date] = price
+	}
+	return prices, nil
+}
+
+func readAndValidateExportConfig(cCtx *cli.Context, logger logging.Logger) (*ExportConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	priceFile := cCtx.String(PriceFileFlag.Name)
+	env := cCtx.String(EnvironmentFlag.Name)
+	if env == "" {
+		env = common.GetEnvFromNetwork(network)
+	}
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Network: %s, Env: %s, Chain ID: %s", network, env, chainID)
+
+	var earnerAddresses []gethcommon.Address
+	for _, earner := range strings.Split(cCtx.String(EarnerAddressesFlag.Name), ",") {
+		earner = strings.TrimSpace(earner)
+		if !gethcommon.IsHexAddress(earner) {
+			return nil, fmt.Errorf("invalid earner address: %q", earner)
+		}
+		earnerAddresses = append(earnerAddresses, gethcommon.HexToAddress(earner))
+	}
+
+	fromDate := cCtx.String(FromDateFlag.Name)
+	from, err := time.Parse(time.DateOnly, fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", fromDate)
+	}
+	toDate := cCtx.String(ToDateFlag.Name)
+	if common.IsEmptyString(toDate) {
+		toDate = time.Now().UTC().Format(time.DateOnly)
+	}
+	to, err := time.Parse(time.DateOnly, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", toDate)
+	}
+	if to.Before(from) {
+		return nil, errors.New("to date must not be before from date")
+	}
+
+	format := cCtx.String(ExportFormatFlag.Name)
+	if format != "csv" && format != "json" {
+		return nil, errors.New("format must be 'csv' or 'json'")
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	sidecarUrl := cCtx.String(SidecarUrlFlag.Name)
+	if common.IsEmptyString(sidecarUrl) {
+		sidecarUrl = getSidecarUrl(network)
+
+		if common.IsEmptyString(sidecarUrl) {
+			return nil, errors.New("sidecar URL not provided")
+		}
+	}
+	logger.Debugf("Using Sidecar URL: %s", sidecarUrl)
+
+	return &ExportConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		ChainID:                   chainID,
+		Environment:               env,
+		EarnerAddresses:           earnerAddresses,
+		FromDate:                  fromDate,
+		ToDate:                    toDate,
+		Format:                    format,
+		Output:                    output,
+		PriceFile:                 priceFile,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		SidecarHttpRpcURL:         sidecarUrl,
+	}, nil
+}
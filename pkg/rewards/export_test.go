@@ -0,0 +1,115 @@
+package rewards
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPriceFile(t *testing.T) {
+	token := gethcommon.HexToAddress("0xc000000000000000000000000000000000000003")
+	prices, err := readPriceFile("testdata/prices.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(5, 2), prices[token]["2025-01-02"])
+	assert.Equal(t, big.NewRat(2, 1), prices[token][""])
+
+	const header = "date,token_address,price\n"
+	invalidPriceFiles := map[string]string{
+		"invalid token":   "2025-01-02,0x1234,1\n",
+		"invalid date":    "01/02/2025,0xc000000000000000000000000000000000000003,1\n",
+		"invalid price":   "2025-01-02,0xc000000000000000000000000000000000000003,abc\n",
+		"duplicate price": ",0xc000000000000000000000000000000000000003,1\n,0xc000000000000000000000000000000000000003,2\n",
+	}
+	for name, content := range invalidPriceFiles {
+		t.Run(name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "prices.csv")
+			assert.NoError(t, os.WriteFile(filePath, []byte(header+content), 0o644))
+			_, err := readPriceFile(filePath)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuildExportRows(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	otherEarner := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	avs := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	token := gethcommon.HexToAddress("0xc000000000000000000000000000000000000003")
+	prices, err := readPriceFile("testdata/prices.csv")
+	assert.NoError(t, err)
+
+	rewardsRows := map[gethcommon.Address][]rewardsBreakdownRow{
+		earner: {
+			{
+				rewardsBreakdownKey: rewardsBreakdownKey{
+					snapshot:   "2025-01-02",
+					avs:        avs,
+					rewardType: "avs_rewards",
+					token:      token,
+				},
+				amount: big.NewInt(1_500_000_000_000_000_000),
+			},
+			{
+				rewardsBreakdownKey: rewardsBreakdownKey{
+					snapshot:   "2025-01-01",
+					avs:        avs,
+					rewardType: "avs_rewards",
+					token:      token,
+				},
+				amount: big.NewInt(1_000_000_000_000_000_000),
+			},
+		},
+	}
+	claims := []rewardsClaimedEvent{
+		{
+			ContractRewardsCoordinatorRewardsClaimed: &rewardscoordinator.ContractRewardsCoordinatorRewardsClaimed{
+				Earner:        earner,
+				Recipient:     otherEarner,
+				Token:         token,
+				ClaimedAmount: big.NewInt(2_500_000_000_000_000_000),
+				Raw:           types.Log{BlockNumber: 42, TxHash: gethcommon.HexToHash("0x01")},
+			},
+			date: "2025-01-03",
+		},
+		{
+			ContractRewardsCoordinatorRewardsClaimed: &rewardscoordinator.ContractRewardsCoordinatorRewardsClaimed{
+				Earner:        otherEarner,
+				Token:         token,
+				ClaimedAmount: big.NewInt(1),
+			},
+			date: "2025-01-03",
+		},
+	}
+	getTokenInfo := func(gethcommon.Address) tokenInfo {
+		return tokenInfo{symbol: "TKN", decimals: 18}
+	}
+
+	rows := buildExportRows([]gethcommon.Address{earner}, rewardsRows, claims, getTokenInfo, prices)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, exportRecordTypeReward, rows[0].RecordType)
+	assert.Equal(t, "2025-01-01", rows[0].Date)
+	assert.Equal(t, "1", rows[0].FormattedAmount)
+	assert.Equal(t, "2.00", rows[0].FiatValue)
+
+	assert.Equal(t, "2025-01-02", rows[1].Date)
+	assert.Equal(t, "1.5", rows[1].FormattedAmount)
+	assert.Equal(t, "3.75", rows[1].FiatValue)
+
+	assert.Equal(t, exportRecordTypeClaim, rows[2].RecordType)
+	assert.Equal(t, "2500000000000000000", rows[2].Amount)
+	assert.Equal(t, "5.00", rows[2].FiatValue)
+	assert.Equal(t, otherEarner.Hex(), rows[2].RecipientAddress)
+	assert.Equal(t, "42", rows[2].BlockNumber)
+
+	rows = buildExportRows([]gethcommon.Address{earner}, rewardsRows, claims, getTokenInfo, nil)
+	assert.Empty(t, rows[0].FiatValue)
+}
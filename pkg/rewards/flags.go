@@ -1,6 +1,10 @@
 package rewards
 
-import "github.com/urfave/cli/v2"
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
 
 var (
 	TokenAddressesFlag = cli.StringFlag{
@@ -40,6 +44,13 @@ var (
 		EnvVars: []string{"SIDECAR_HTTP_RPC_URL"},
 	}
 
+	DistributionFileFlag = cli.StringFlag{
+		Name:    "distribution-file",
+		Aliases: []string{"df"},
+		Usage:   "Distribution snapshot to generate the claim from instead of the sidecar. The root of the snapshot must be posted on-chain",
+		EnvVars: []string{"REWARDS_DISTRIBUTION_FILE"},
+	}
+
 	EnvironmentFlag = cli.StringFlag{
 		Name:    "environment",
 		Aliases: []string{"env"},
@@ -66,7 +77,7 @@ var (
 	NumberOfDaysFlag = cli.IntFlag{
 		Name:    "number-of-days",
 		Aliases: []string{"nd"},
-		Usage:   "Number of past days to show rewards for. It should be negative. Only used for 'all' claim type",
+		Usage:   "Number of past days to show rewards for. It should be negative. Only used with --breakdown",
 		Value:   -21,
 		EnvVars: []string{"REWARDS_NUMBER_OF_DAYS"},
 		Action: func(context *cli.Context, i int) error {
@@ -80,10 +91,175 @@ var (
 	AVSAddressesFlag = cli.StringFlag{
 		Name:    "avs-addresses",
 		Aliases: []string{"a"},
-		Usage:   "Comma seperated addresses of the AVS",
+		Usage:   "Comma separated addresses of the AVSs to show rewards for. Only used with --breakdown",
 		EnvVars: []string{"AVS_ADDRESSES"},
 	}
 
+	BreakdownFlag = cli.BoolFlag{
+		Name:    "breakdown",
+		Aliases: []string{"bd"},
+		Usage:   "Show earned rewards broken down by AVS, operator set, rewards submission type and snapshot date",
+		EnvVars: []string{"REWARDS_BREAKDOWN"},
+	}
+
+	EarnerAddressesFlag = cli.StringFlag{
+		Name:     "earner-addresses",
+		Aliases:  []string{"eas"},
+		Usage:    "Comma separated addresses of the earners",
+		Required: true,
+		EnvVars:  []string{"REWARDS_EARNER_ADDRESSES"},
+	}
+
+	FromDateFlag = cli.StringFlag{
+		Name:     "from",
+		Usage:    "First snapshot date to export, in YYYY-MM-DD format",
+		Required: true,
+		EnvVars:  []string{"REWARDS_FROM_DATE"},
+	}
+
+	ToDateFlag = cli.StringFlag{
+		Name:    "to",
+		Usage:   "Last snapshot date to export or show with --breakdown, in YYYY-MM-DD format. Defaults to today",
+		EnvVars: []string{"REWARDS_TO_DATE"},
+	}
+
+	ExportFormatFlag = cli.StringFlag{
+		Name:    "format",
+		Aliases: []string{"f"},
+		Usage:   "Format of the export. One of 'csv' or 'json'",
+		Value:   "csv",
+		EnvVars: []string{"REWARDS_EXPORT_FORMAT"},
+	}
+
+	PriceFileFlag = cli.StringFlag{
+		Name:    "price-file",
+		Aliases: []string{"pf"},
+		Usage:   "CSV file with the columns date, token_address and price used to compute fiat values",
+		EnvVars: []string{"REWARDS_PRICE_FILE"},
+	}
+
+	BatchGasLimitFlag = cli.Uint64Flag{
+		Name:    "batch-gas-limit",
+		Aliases: []string{"bgl"},
+		Usage:   "Maximum estimated gas of a batch claim transaction. Claims above it are split into several transactions",
+		Value:   10_000_000,
+		EnvVars: []string{"REWARDS_BATCH_GAS_LIMIT"},
+	}
+
+	BatchProgressFileFlag = cli.StringFlag{
+		Name:    "batch-progress-file",
+		Aliases: []string{"bpf"},
+		Usage:   "File recording the earners claimed by a batch claim, so that a failed batch can be resumed. Defaults to <batch-claim-file>.progress.json",
+		EnvVars: []string{"REWARDS_BATCH_PROGRESS_FILE"},
+	}
+
+	ClaimThresholdsFlag = cli.StringFlag{
+		Name:    "claim-thresholds",
+		Aliases: []string{"cth"},
+		Usage:   "Minimum claimable amount of each token to claim it, in the smallest unit of the token. Comma separated list of <token address>:<amount>. Tokens without a threshold are not claimed, unless --claim-unlisted-tokens is set",
+		EnvVars: []string{"REWARDS_CLAIM_THRESHOLDS"},
+	}
+
+	ClaimUnlistedTokensFlag = cli.BoolFlag{
+		Name:    "claim-unlisted-tokens",
+		Aliases: []string{"cut"},
+		Usage:   "Also claim the tokens without a threshold, whenever they are claimable",
+		EnvVars: []string{"REWARDS_CLAIM_UNLISTED_TOKENS"},
+	}
+
+	MaxGasPriceFlag = cli.Float64Flag{
+		Name:    "max-gas-price",
+		Aliases: []string{"mgp"},
+		Usage:   "Gas price ceiling in gwei. Claims are postponed while the suggested gas price is above it. 0 disables the ceiling",
+		EnvVars: []string{"REWARDS_MAX_GAS_PRICE"},
+	}
+
+	PollIntervalFlag = cli.DurationFlag{
+		Name:    "poll-interval",
+		Aliases: []string{"pi"},
+		Usage:   "Interval between checks for claimable rewards",
+		Value:   time.Hour,
+		EnvVars: []string{"REWARDS_POLL_INTERVAL"},
+	}
+
+	OnceFlag = cli.BoolFlag{
+		Name:    "once",
+		Usage:   "Check and claim once, then exit. Use it to run auto-claim from cron",
+		EnvVars: []string{"REWARDS_AUTO_CLAIM_ONCE"},
+	}
+
+	StatusFileFlag = cli.StringFlag{
+		Name:    "status-file",
+		Aliases: []string{"sf"},
+		Usage:   "File the outcome of the last check is written to",
+		Value:   "auto-claim-status.json",
+		EnvVars: []string{"REWARDS_AUTO_CLAIM_STATUS_FILE"},
+	}
+
+	TokenRecipientsFlag = cli.StringFlag{
+		Name:    "token-recipients",
+		Aliases: []string{"tr"},
+		Usage:   "Recipient of each token, overriding the recipient address. Comma separated list of <token address>:<recipient address>. Tokens of each recipient are claimed in a separate transaction",
+		EnvVars: []string{"REWARDS_TOKEN_RECIPIENTS"},
+	}
+
+	TokenPolicyFileFlag = cli.StringFlag{
+		Name:    "token-policy-file",
+		Aliases: []string{"tpf"},
+		Usage:   "YAML file with the 'allow' and 'deny' lists of token addresses. Denied tokens are never claimed and, if the allow list is not empty, only allowed tokens are claimed",
+		EnvVars: []string{"REWARDS_TOKEN_POLICY_FILE"},
+	}
+
+	PermissionControllerAddressFlag = cli.StringFlag{
+		Name:    "permission-controller-address",
+		Aliases: []string{"pca"},
+		Usage:   "Specify the address of the permission controller. If not provided, the address will be used based on provided network",
+		EnvVars: []string{"PERMISSION_CONTROLLER_ADDRESS"},
+	}
+
+	RootsLimitFlag = cli.Uint64Flag{
+		Name:    "limit",
+		Aliases: []string{"l"},
+		Usage:   "Number of most recent distribution roots to list. 0 lists all roots",
+		EnvVars: []string{"REWARDS_ROOTS_LIMIT"},
+	}
+
+	RootsPollIntervalFlag = cli.DurationFlag{
+		Name:    "poll-interval",
+		Aliases: []string{"pi"},
+		Usage:   "Interval between reads of the distribution roots",
+		Value:   time.Minute,
+		EnvVars: []string{"REWARDS_ROOTS_POLL_INTERVAL"},
+	}
+
+	WebhookUrlFlag = cli.StringFlag{
+		Name:    "webhook-url",
+		Aliases: []string{"wu"},
+		Usage:   "URL events are posted to as JSON. If not provided, events are printed as JSON lines",
+		EnvVars: []string{"REWARDS_WEBHOOK_URL"},
+	}
+
+	SubmissionFileFlag = cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "Path to the YAML or CSV file with the rewards submissions. CSV files must have a .csv extension",
+		Required: true,
+		EnvVars:  []string{"REWARDS_SUBMISSION_FILE"},
+	}
+
+	ApproveFlag = cli.BoolFlag{
+		Name:    "approve",
+		Usage:   "Approve the RewardsCoordinator, or the ServiceManager with --service-manager, to transfer the tokens of the submissions when the allowance of the caller is too low",
+		EnvVars: []string{"REWARDS_SUBMISSION_APPROVE"},
+	}
+
+	ServiceManagerFlag = cli.BoolFlag{
+		Name:    "service-manager",
+		Aliases: []string{"sm"},
+		Usage:   "Submit through the createAVSRewardsSubmission of the ServiceManager at the AVS address, sent by its rewards initiator",
+		EnvVars: []string{"REWARDS_SUBMISSION_SERVICE_MANAGER"},
+	}
+
 	ClaimTypeFlag = cli.StringFlag{
 		Name:    "claim-type",
 		Aliases: []string{"ct"},
@@ -0,0 +1,272 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+// setClaimerForSelector is the selector of setClaimerFor(address,address), which admins and appointees of
+// the earner call to set its claimer
+var setClaimerForSelector = [4]byte(crypto.Keccak256([]byte("setClaimerFor(address,address)"))[:4])
+
+type claimerForReader interface {
+	GetClaimerFor(ctx context.Context, earner gethcommon.Address) (gethcommon.Address, error)
+}
+
+type claimerReader interface {
+	claimerForReader
+	ListAdmins(ctx context.Context, accountAddress gethcommon.Address) ([]gethcommon.Address, error)
+	ListAppointees(
+		ctx context.Context,
+		accountAddress gethcommon.Address,
+		target gethcommon.Address,
+		selector [4]byte,
+	) ([]gethcommon.Address, error)
+}
+
+func GetClaimerCmd(p utils.Prompter) *cli.Command {
+	getClaimerCmd := &cli.Command{
+		Name:      "get-claimer",
+		Usage:     "Get the claimer address of the earner",
+		UsageText: "get-claimer",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Command to show who can claim the rewards of an earner
+
+The rewards coordinator only accepts claims sent by the claimer of the earner, which is the earner itself
+if no claimer is set. The admins of the earner and the appointees allowed to call setClaimerFor on the
+rewards coordinator are listed too, since they can change the claimer. An earner without admins is its
+own admin.
+`,
+		Flags: getGetClaimerFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return getClaimerAction(cCtx)
+		},
+	}
+	return getClaimerCmd
+}
+
+func getGetClaimerFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&EarnerAddressFlag,
+		&EnvironmentFlag,
+		&PermissionControllerAddressFlag,
+		&RewardsCoordinatorAddressFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func getClaimerAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateGetClaimerConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate get claimer config", err)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	elReader, err := elcontracts.NewReaderFromConfig(
+		elcontracts.Config{
+			RewardsCoordinatorAddress:   config.RewardsCoordinatorAddress,
+			PermissionControllerAddress: config.PermissionControllerAddress,
+		},
+		ethClient,
+		logger,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new reader from config", err)
+	}
+
+	info, err := getClaimerInfo(ctx, elReader, config.RewardsCoordinatorAddress, config.EarnerAddress)
+	if err != nil {
+		return err
+	}
+
+	if config.OutputType == utils.JsonOutputType {
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.Output) {
+			return common.WriteToFile(out, config.Output)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("Earner:  %s\n", info.EarnerAddress)
+	if info.ClaimerSet {
+		fmt.Printf("Claimer: %s\n", info.ClaimerAddress)
+	} else {
+		fmt.Printf("Claimer: %s (no claimer set, the earner claims)\n", info.ClaimerAddress)
+	}
+	fmt.Println()
+	fmt.Println("Admins of the earner, who can set the claimer:")
+	printAddresses(info.Admins)
+	fmt.Println("Appointees of the earner allowed to set the claimer:")
+	printAddresses(info.SetClaimerAppointees)
+	return nil
+}
+
+// getClaimerInfo returns the claimer of the earner along with the accounts which can change it
+func getClaimerInfo(
+	ctx context.Context,
+	reader claimerReader,
+	rewardsCoordinatorAddress gethcommon.Address,
+	earnerAddress gethcommon.Address,
+) (*claimerInfoJson, error) {
+	claimer, err := reader.GetClaimerFor(ctx, earnerAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get claimer", err)
+	}
+	admins, err := reader.ListAdmins(ctx, earnerAddress)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to list admins", err)
+	}
+	if len(admins) == 0 {
+		admins = []gethcommon.Address{earnerAddress}
+	}
+	appointees, err := reader.ListAppointees(ctx, earnerAddress, rewardsCoordinatorAddress, setClaimerForSelector)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to list appointees", err)
+	}
+
+	info := &claimerInfoJson{
+		EarnerAddress:        earnerAddress.Hex(),
+		ClaimerAddress:       earnerAddress.Hex(),
+		ClaimerSet:           claimer != utils.ZeroAddress,
+		Admins:               toHexAddresses(admins),
+		SetClaimerAppointees: toHexAddresses(appointees),
+	}
+	if info.ClaimerSet {
+		info.ClaimerAddress = claimer.Hex()
+	}
+	return info, nil
+}
+
+// validateClaimer checks that claims of the earner can be sent by the claimer. The rewards coordinator only
+// accepts claims from the claimer set for the earner, or from the earner if none is set.
+func validateClaimer(
+	ctx context.Context,
+	reader claimerForReader,
+	earnerAddress gethcommon.Address,
+	claimerAddress gethcommon.Address,
+) error {
+	authorized, err := reader.GetClaimerFor(ctx, earnerAddress)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get claimer", err)
+	}
+	if authorized == utils.ZeroAddress {
+		authorized = earnerAddress
+	}
+	if claimerAddress != authorized {
+		return fmt.Errorf(
+			"%s can't claim rewards of earner %s, only %s can. Use --claimer-address %s or change the claimer "+
+				"with 'eigenlayer rewards set-claimer'",
+			claimerAddress.Hex(),
+			earnerAddress.Hex(),
+			authorized.Hex(),
+			authorized.Hex(),
+		)
+	}
+	return nil
+}
+
+func toHexAddresses(addresses []gethcommon.Address) []string {
+	out := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		out = append(out, address.Hex())
+	}
+	return out
+}
+
+func printAddresses(addresses []string) {
+	if len(addresses) == 0 {
+		fmt.Println("  None")
+	}
+	for _, address := range addresses {
+		fmt.Printf("  %s\n", address)
+	}
+	fmt.Println()
+}
+
+func readAndValidateGetClaimerConfig(cCtx *cli.Context, logger logging.Logger) (*GetClaimerConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	earnerAddress := gethcommon.HexToAddress(cCtx.String(EarnerAddressFlag.Name))
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	permissionControllerAddress := cCtx.String(PermissionControllerAddressFlag.Name)
+	chainID := utils.NetworkNameToChainId(network)
+
+	if outputType != utils.PrettyOutputType && outputType != utils.JsonOutputType {
+		return nil, fmt.Errorf("unsupported output type for this command %s", outputType)
+	}
+
+	var err error
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	if common.IsEmptyString(permissionControllerAddress) {
+		permissionControllerAddress, err = common.GetPermissionControllerAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Permission Controller address: %s", permissionControllerAddress)
+
+	if common.IsEmptyString(environment) {
+		environment = common.GetEnvFromNetwork(network)
+	}
+	logger.Debugf("Using network %s and environment: %s", network, environment)
+
+	return &GetClaimerConfig{
+		Network:                     network,
+		RPCUrl:                      rpcUrl,
+		EarnerAddress:               earnerAddress,
+		RewardsCoordinatorAddress:   gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		PermissionControllerAddress: gethcommon.HexToAddress(permissionControllerAddress),
+		ChainID:                     chainID,
+		Environment:                 environment,
+		Output:                      output,
+		OutputType:                  outputType,
+	}, nil
+}
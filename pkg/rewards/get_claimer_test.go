@@ -0,0 +1,86 @@
+package rewards
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClaimerReader struct {
+	claimers   map[gethcommon.Address]gethcommon.Address
+	admins     []gethcommon.Address
+	appointees map[[4]byte][]gethcommon.Address
+}
+
+func (f *fakeClaimerReader) GetClaimerFor(ctx context.Context, earner gethcommon.Address) (gethcommon.Address, error) {
+	return f.claimers[earner], nil
+}
+
+func (f *fakeClaimerReader) ListAdmins(
+	ctx context.Context,
+	accountAddress gethcommon.Address,
+) ([]gethcommon.Address, error) {
+	return f.admins, nil
+}
+
+func (f *fakeClaimerReader) ListAppointees(
+	ctx context.Context,
+	accountAddress gethcommon.Address,
+	target gethcommon.Address,
+	selector [4]byte,
+) ([]gethcommon.Address, error) {
+	return f.appointees[selector], nil
+}
+
+func TestGetClaimerInfo(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	claimer := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	admin := gethcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	appointee := gethcommon.HexToAddress("0x4000000000000000000000000000000000000004")
+	rewardsCoordinator := gethcommon.HexToAddress("0x5000000000000000000000000000000000000005")
+
+	reader := &fakeClaimerReader{
+		claimers:   map[gethcommon.Address]gethcommon.Address{earner: claimer},
+		admins:     []gethcommon.Address{admin},
+		appointees: map[[4]byte][]gethcommon.Address{setClaimerForSelector: {appointee}},
+	}
+	info, err := getClaimerInfo(context.Background(), reader, rewardsCoordinator, earner)
+	assert.NoError(t, err)
+	assert.Equal(t, &claimerInfoJson{
+		EarnerAddress:        earner.Hex(),
+		ClaimerAddress:       claimer.Hex(),
+		ClaimerSet:           true,
+		Admins:               []string{admin.Hex()},
+		SetClaimerAppointees: []string{appointee.Hex()},
+	}, info)
+
+	// Without a claimer nor admins, the earner claims and is its own admin
+	info, err = getClaimerInfo(context.Background(), &fakeClaimerReader{}, rewardsCoordinator, earner)
+	assert.NoError(t, err)
+	assert.Equal(t, &claimerInfoJson{
+		EarnerAddress:        earner.Hex(),
+		ClaimerAddress:       earner.Hex(),
+		Admins:               []string{earner.Hex()},
+		SetClaimerAppointees: []string{},
+	}, info)
+}
+
+func TestValidateClaimer(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	claimer := gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	other := gethcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	noClaimer := &fakeClaimerReader{}
+	assert.NoError(t, validateClaimer(context.Background(), noClaimer, earner, earner))
+	assert.ErrorContains(t, validateClaimer(context.Background(), noClaimer, earner, other), "only "+earner.Hex())
+
+	withClaimer := &fakeClaimerReader{claimers: map[gethcommon.Address]gethcommon.Address{earner: claimer}}
+	assert.NoError(t, validateClaimer(context.Background(), withClaimer, earner, claimer))
+	assert.Error(t, validateClaimer(context.Background(), withClaimer, earner, earner))
+	assert.Error(t, validateClaimer(context.Background(), withClaimer, earner, utils.ZeroAddress))
+}
@@ -0,0 +1,538 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	rootStatusPending  = "pending"
+	rootStatusActive   = "active"
+	rootStatusDisabled = "disabled"
+
+	rootEventSubmitted = "root_submitted"
+	rootEventActivated = "root_activated"
+	rootEventDisabled  = "root_disabled"
+
+	webhookTimeout = 10 * time.Second
+)
+
+type distributionRootsReader interface {
+	distributionRootReader
+	GetDistributionRootsLength(opts *bind.CallOpts) (*big.Int, error)
+}
+
+// rootsWatcher tracks the distribution roots to detect new roots and the activation or disabling of pending
+// roots. A root can only be disabled before it is activated, so only pending roots are read again.
+type rootsWatcher struct {
+	reader  distributionRootsReader
+	length  uint64
+	pending map[uint32]rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot
+	now     func() time.Time
+}
+
+func RootsCmd(p utils.Prompter) *cli.Command {
+	rootsCmd := &cli.Command{
+		Name:  "roots",
+		Usage: "List and watch the distribution roots",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "list",
+				Usage:     "List the distribution roots",
+				UsageText: "list",
+				After:     telemetry.AfterRunAction(),
+				Description: `
+Command to list the distribution roots posted to the rewards coordinator, most recent first
+
+A root is pending until its activation timestamp, after which it can be claimed unless it was disabled.
+Roots can only be disabled while pending.
+`,
+				Flags: getRootsListFlags(),
+				Action: func(cCtx *cli.Context) error {
+					return listRootsAction(cCtx)
+				},
+			},
+			{
+				Name:      "watch",
+				Usage:     "Watch the distribution roots and emit an event when a root is submitted, activated or disabled",
+				UsageText: "watch",
+				After:     telemetry.AfterRunAction(),
+				Description: `
+Command to watch the distribution roots posted to the rewards coordinator
+
+Events are emitted when a root is submitted, when it activates and when it is disabled. Each event is
+printed as a JSON line, or posted as JSON to the webhook URL if one is given:
+
+{"event":"root_disabled","timestamp":"2025-01-01T00:00:00Z","rootIndex":42,"root":"0x...",...}
+
+Roots posted before the command starts don't emit a submitted event, but their activation and disabling
+are still reported. If the webhook fails, the event and the ones after it are posted again on the next poll.
+`,
+				Flags: getRootsWatchFlags(),
+				Action: func(cCtx *cli.Context) error {
+					return watchRootsAction(cCtx)
+				},
+			},
+		},
+	}
+	return rootsCmd
+}
+
+func getRootsListFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.VerboseFlag,
+		&EnvironmentFlag,
+		&RewardsCoordinatorAddressFlag,
+		&RootsLimitFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func getRootsWatchFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.VerboseFlag,
+		&EnvironmentFlag,
+		&RewardsCoordinatorAddressFlag,
+		&RootsPollIntervalFlag,
+		&WebhookUrlFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func listRootsAction(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateRootsConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate roots config", err)
+	}
+	if config.OutputType != utils.PrettyOutputType && config.OutputType != utils.JsonOutputType {
+		return fmt.Errorf("unsupported output type for this command %s", config.OutputType)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	reader, err := newDistributionRootsReader(config)
+	if err != nil {
+		return err
+	}
+	roots, err := listDistributionRoots(ctx, reader, config.Limit, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if config.OutputType == utils.JsonOutputType {
+		out, err := json.MarshalIndent(roots, "", "  ")
+		if err != nil {
+			return err
+		}
+		if !common.IsEmptyString(config.Output) {
+			return common.WriteToFile(out, config.Output)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	printDistributionRoots(roots)
+	return nil
+}
+
+func watchRootsAction(cCtx *cli.Context) error {
+	ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateRootsConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate roots config", err)
+	}
+
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	reader, err := newDistributionRootsReader(config)
+	if err != nil {
+		return err
+	}
+	watcher, err := newRootsWatcher(ctx, reader, time.Now)
+	if err != nil {
+		return err
+	}
+	logger.Debugf("Watching distribution roots from index %d", watcher.length)
+
+	emit := func(event distributionRootEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if common.IsEmptyString(config.WebhookURL) {
+			fmt.Println(string(data))
+			return nil
+		}
+		return postWebhook(ctx, config.WebhookURL, data)
+	}
+
+	// Events which failed to be emitted are kept, and emitted again before the events of the next poll
+	queued := make([]distributionRootEvent, 0)
+	for {
+		events, err := watcher.poll(ctx)
+		if err != nil {
+			// RPC errors are transient, so the roots are read again on the next poll
+			logger.Warnf("Failed to read distribution roots: %v", err)
+		}
+		queued, err = emitEvents(append(queued, events...), emit)
+		if err != nil {
+			logger.Errorf("Failed to emit events, %d events kept for the next poll: %v", len(queued), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(config.PollInterval):
+		}
+	}
+}
+
+func newDistributionRootsReader(config *RootsConfig) (distributionRootsReader, error) {
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(
+		config.RewardsCoordinatorAddress,
+		ethClient,
+	)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to create rewards coordinator binding", err)
+	}
+	return rewardsCoordinator, nil
+}
+
+// listDistributionRoots returns the latest limit distribution roots, most recent first. A limit of 0 returns
+// all roots.
+func listDistributionRoots(
+	ctx context.Context,
+	reader distributionRootsReader,
+	limit uint64,
+	now time.Time,
+) ([]distributionRootJson, error) {
+	length, err := reader.GetDistributionRootsLength(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get distribution roots length", err)
+	}
+
+	count := length.Uint64()
+	if limit > 0 {
+		count = min(count, limit)
+	}
+	roots := make([]distributionRootJson, 0, count)
+	for i := uint64(0); i < count; i++ {
+		index := uint32(length.Uint64() - 1 - i)
+		root, err := getDistributionRoot(ctx, reader, index)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, toDistributionRootJson(index, root, now))
+	}
+	return roots, nil
+}
+
+func getDistributionRoot(
+	ctx context.Context,
+	reader distributionRootReader,
+	index uint32,
+) (rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot, error) {
+	root, err := reader.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		new(big.Int).SetUint64(uint64(index)),
+	)
+	if err != nil {
+		return root, eigenSdkUtils.WrapError(fmt.Sprintf("failed to get distribution root at index %d", index), err)
+	}
+	return root, nil
+}
+
+func getDistributionRootStatus(root rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot, now time.Time) string {
+	if root.Disabled {
+		return rootStatusDisabled
+	}
+	if int64(root.ActivatedAt) > now.Unix() {
+		return rootStatusPending
+	}
+	return rootStatusActive
+}
+
+func toDistributionRootJson(
+	index uint32,
+	root rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot,
+	now time.Time,
+) distributionRootJson {
+	activatedAt := time.Unix(int64(root.ActivatedAt), 0).UTC()
+	rootJson := distributionRootJson{
+		RootIndex: index,
+		Root:      hexutil.Encode(root.Root[:]),
+		RewardsCalculationEnd: time.Unix(int64(root.RewardsCalculationEndTimestamp), 0).
+			UTC().
+			Format(time.DateOnly),
+		ActivatedAt: activatedAt.Format(time.RFC3339),
+		Disabled:    root.Disabled,
+		Status:      getDistributionRootStatus(root, now),
+	}
+	if rootJson.Status == rootStatusPending {
+		rootJson.ActivatesIn = activatedAt.Sub(now).Truncate(time.Second).String()
+	}
+	return rootJson
+}
+
+func printDistributionRoots(roots []distributionRootJson) {
+	headers := []string{"Index", "Root", "Calculated Until", "Activated At", "Status", "Activates In"}
+	widths := []int{6, 66, 16, 20, 8, 12}
+
+	printSeparator := func(sep string, end string) {
+		for _, width := range widths {
+			fmt.Print(sep + strings.Repeat("-", width+1))
+		}
+		fmt.Println(end)
+	}
+
+	printSeparator("+", "+")
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+	printSeparator("|", "|")
+	if len(roots) == 0 {
+		fmt.Println("No distribution roots found")
+	}
+	for _, root := range roots {
+		values := []string{
+			fmt.Sprint(root.RootIndex),
+			root.Root,
+			root.RewardsCalculationEnd,
+			root.ActivatedAt,
+			root.Status,
+			root.ActivatesIn,
+		}
+		for i, value := range values {
+			fmt.Printf("| %-*s", widths[i], value)
+		}
+		fmt.Println("|")
+	}
+	printSeparator("+", "+")
+}
+
+// newRootsWatcher returns a watcher of the roots posted after the current ones, which also tracks the
+// current pending roots
+func newRootsWatcher(
+	ctx context.Context,
+	reader distributionRootsReader,
+	now func() time.Time,
+) (*rootsWatcher, error) {
+	length, err := reader.GetDistributionRootsLength(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get distribution roots length", err)
+	}
+	watcher := &rootsWatcher{
+		reader:  reader,
+		length:  length.Uint64(),
+		pending: make(map[uint32]rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot),
+		now:     now,
+	}
+
+	// Roots are posted in order of activation, so the pending roots are the latest ones. Disabled roots can sit
+	// between pending roots, so we only stop at the first active root.
+	for index := watcher.length; index > 0; index-- {
+		root, err := getDistributionRoot(ctx, reader, uint32(index-1))
+		if err != nil {
+			return nil, err
+		}
+		status := getDistributionRootStatus(root, now())
+		if status == rootStatusDisabled {
+			continue
+		}
+		if status == rootStatusActive {
+			break
+		}
+		watcher.pending[uint32(index-1)] = root
+	}
+	return watcher, nil
+}
+
+// poll reads the new and pending roots and returns the events since the last poll, in order of root index
+func (w *rootsWatcher) poll(ctx context.Context) ([]distributionRootEvent, error) {
+	length, err := w.reader.GetDistributionRootsLength(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to get distribution roots length", err)
+	}
+
+	events := make([]distributionRootEvent, 0)
+	now := w.now()
+	newEvent := func(name string, index uint32, root rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot) {
+		events = append(events, distributionRootEvent{
+			Event:                name,
+			Timestamp:            now.UTC().Format(time.RFC3339),
+			distributionRootJson: toDistributionRootJson(index, root, now),
+		})
+	}
+
+	indices := make([]uint32, 0, len(w.pending))
+	for index := range w.pending {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, index := range indices {
+		root, err := getDistributionRoot(ctx, w.reader, index)
+		if err != nil {
+			return events, err
+		}
+		switch getDistributionRootStatus(root, now) {
+		case rootStatusDisabled:
+			newEvent(rootEventDisabled, index, root)
+			delete(w.pending, index)
+		case rootStatusActive:
+			newEvent(rootEventActivated, index, root)
+			delete(w.pending, index)
+		}
+	}
+
+	for ; w.length < length.Uint64(); w.length++ {
+		index := uint32(w.length)
+		root, err := getDistributionRoot(ctx, w.reader, index)
+		if err != nil {
+			return events, err
+		}
+		newEvent(rootEventSubmitted, index, root)
+		switch getDistributionRootStatus(root, now) {
+		case rootStatusPending:
+			w.pending[index] = root
+		case rootStatusDisabled:
+			newEvent(rootEventDisabled, index, root)
+		case rootStatusActive:
+			newEvent(rootEventActivated, index, root)
+		}
+	}
+	return events, nil
+}
+
+// emitEvents emits the events in order and stops at the first failure, so that the order of events is kept.
+// It returns the events which were not emitted.
+func emitEvents(
+	events []distributionRootEvent,
+	emit func(event distributionRootEvent) error,
+) ([]distributionRootEvent, error) {
+	for i, event := range events {
+		if err := emit(event); err != nil {
+			return events[i:], eigenSdkUtils.WrapError(
+				fmt.Sprintf("failed to emit %s event of root %d", event.Event, event.RootIndex),
+				err,
+			)
+		}
+	}
+	return events[:0], nil
+}
+
+func postWebhook(ctx context.Context, webhookURL string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", response.Status)
+	}
+	return nil
+}
+
+func readAndValidateRootsConfig(cCtx *cli.Context, logger logging.Logger) (*RootsConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	environment := cCtx.String(EnvironmentFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	limit := cCtx.Uint64(RootsLimitFlag.Name)
+	pollInterval := cCtx.Duration(RootsPollIntervalFlag.Name)
+	webhookURL := cCtx.String(WebhookUrlFlag.Name)
+	chainID := utils.NetworkNameToChainId(network)
+
+	if cCtx.IsSet(RootsPollIntervalFlag.Name) && pollInterval <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+	if !common.IsEmptyString(webhookURL) {
+		parsed, err := url.Parse(webhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", webhookURL)
+		}
+	}
+
+	var err error
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	if common.IsEmptyString(environment) {
+		environment = common.GetEnvFromNetwork(network)
+	}
+	logger.Debugf("Using network %s and environment: %s", network, environment)
+
+	return &RootsConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		ChainID:                   chainID,
+		Environment:               environment,
+		Output:                    output,
+		OutputType:                outputType,
+		Limit:                     limit,
+		PollInterval:              pollInterval,
+		WebhookURL:                webhookURL,
+	}, nil
+}
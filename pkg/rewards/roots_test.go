@@ -0,0 +1,185 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (f *fakeDistributionRootReader) GetDistributionRootsLength(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(f.roots))), nil
+}
+
+func TestListDistributionRoots(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	reader := &fakeDistributionRootReader{
+		roots: []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+			{Root: [32]byte{1}, ActivatedAt: uint32(now.Add(-48 * time.Hour).Unix())},
+			{Root: [32]byte{2}, ActivatedAt: uint32(now.Add(-24 * time.Hour).Unix()), Disabled: true},
+			{
+				Root:                           [32]byte{3},
+				RewardsCalculationEndTimestamp: uint32(now.Add(-36 * time.Hour).Unix()),
+				ActivatedAt:                    uint32(now.Add(90 * time.Minute).Unix()),
+			},
+		},
+	}
+
+	roots, err := listDistributionRoots(context.Background(), reader, 0, now)
+	assert.NoError(t, err)
+	assert.Len(t, roots, 3)
+	assert.Equal(t, distributionRootJson{
+		RootIndex:             2,
+		Root:                  "0x0300000000000000000000000000000000000000000000000000000000000000",
+		RewardsCalculationEnd: "2025-01-09",
+		ActivatedAt:           "2025-01-10T13:30:00Z",
+		Status:                rootStatusPending,
+		ActivatesIn:           "1h30m0s",
+	}, roots[0])
+	assert.Equal(t, rootStatusDisabled, roots[1].Status)
+	assert.Equal(t, rootStatusActive, roots[2].Status)
+	assert.Empty(t, roots[2].ActivatesIn)
+
+	roots, err = listDistributionRoots(context.Background(), reader, 2, now)
+	assert.NoError(t, err)
+	assert.Len(t, roots, 2)
+	assert.Equal(t, uint32(1), roots[1].RootIndex)
+}
+
+func TestRootsWatcher(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	reader := &fakeDistributionRootReader{
+		roots: []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+			{Root: [32]byte{1}, ActivatedAt: uint32(now.Add(-time.Hour).Unix())},
+			{Root: [32]byte{2}, ActivatedAt: uint32(now.Add(time.Hour).Unix())},
+		},
+	}
+	watcher, err := newRootsWatcher(context.Background(), reader, func() time.Time { return now })
+	assert.NoError(t, err)
+	assert.Len(t, watcher.pending, 1)
+
+	eventNames := func(events []distributionRootEvent) []string {
+		names := make([]string, 0, len(events))
+		for _, event := range events {
+			names = append(names, event.Event)
+		}
+		return names
+	}
+
+	events, err := watcher.poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// A new root is submitted and the pending root activates
+	reader.roots = append(reader.roots, rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+		Root:        [32]byte{3},
+		ActivatedAt: uint32(now.Add(3 * time.Hour).Unix()),
+	})
+	now = now.Add(2 * time.Hour)
+	events, err = watcher.poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rootEventActivated, rootEventSubmitted}, eventNames(events))
+	assert.Equal(t, uint32(1), events[0].RootIndex)
+	assert.Equal(t, uint32(2), events[1].RootIndex)
+	assert.Equal(t, "2025-01-10T14:00:00Z", events[1].Timestamp)
+
+	// The new root is disabled before it activates
+	reader.roots[2].Disabled = true
+	events, err = watcher.poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{rootEventDisabled}, eventNames(events))
+	assert.True(t, events[0].Disabled)
+
+	events, err = watcher.poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestRootsWatcherSkipsDisabledRoots(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	reader := &fakeDistributionRootReader{
+		roots: []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+			{Root: [32]byte{1}, ActivatedAt: uint32(now.Add(-time.Hour).Unix())},
+			{Root: [32]byte{2}, ActivatedAt: uint32(now.Add(time.Hour).Unix())},
+			{Root: [32]byte{3}, ActivatedAt: uint32(now.Add(2 * time.Hour).Unix()), Disabled: true},
+			{Root: [32]byte{4}, ActivatedAt: uint32(now.Add(3 * time.Hour).Unix())},
+		},
+	}
+	watcher, err := newRootsWatcher(context.Background(), reader, func() time.Time { return now })
+	assert.NoError(t, err)
+	assert.Len(t, watcher.pending, 2)
+	assert.Contains(t, watcher.pending, uint32(1))
+	assert.Contains(t, watcher.pending, uint32(3))
+
+	// The pending root behind the disabled root activates
+	now = now.Add(90 * time.Minute)
+	events, err := watcher.poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, rootEventActivated, events[0].Event)
+	assert.Equal(t, uint32(1), events[0].RootIndex)
+}
+
+func TestEmitEvents(t *testing.T) {
+	events := []distributionRootEvent{
+		{Event: rootEventSubmitted, distributionRootJson: distributionRootJson{RootIndex: 1}},
+		{Event: rootEventActivated, distributionRootJson: distributionRootJson{RootIndex: 1}},
+		{Event: rootEventSubmitted, distributionRootJson: distributionRootJson{RootIndex: 2}},
+	}
+
+	emitted := make([]distributionRootEvent, 0)
+	failing := true
+	emit := func(event distributionRootEvent) error {
+		if failing && event.Event == rootEventActivated {
+			return errors.New("webhook returned status 500")
+		}
+		emitted = append(emitted, event)
+		return nil
+	}
+
+	remaining, err := emitEvents(events, emit)
+	assert.ErrorContains(t, err, "failed to emit root_activated event of root 1")
+	assert.Equal(t, events[:1], emitted)
+	assert.Equal(t, events[1:], remaining)
+
+	failing = false
+	remaining, err = emitEvents(remaining, emit)
+	assert.NoError(t, err)
+	assert.Empty(t, remaining)
+	assert.Equal(t, events, emitted)
+}
+
+func TestPostWebhook(t *testing.T) {
+	var received distributionRootEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		if received.RootIndex == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	event := distributionRootEvent{Event: rootEventDisabled, distributionRootJson: distributionRootJson{RootIndex: 7}}
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+	assert.NoError(t, postWebhook(context.Background(), server.URL, data))
+	assert.Equal(t, event, received)
+
+	data, err = json.Marshal(distributionRootEvent{Event: rootEventDisabled})
+	assert.NoError(t, err)
+	assert.ErrorContains(t, postWebhook(context.Background(), server.URL, data), "500")
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/clients/sidecar"
 	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
@@ -55,6 +56,10 @@ Helpful flags
 - claim-timestamp: Timestamp of the claim distribution root to use. Can be 'latest' or 'latest_active'.
 	- 'latest' will show rewards for the latest root (can contain non-claimable rewards)
 	- 'latest_active' will show rewards for the latest active root (only claimable rewards)
+- breakdown: Show the rewards earned from number-of-days ago up to the to date broken down by AVS, operator
+  set, rewards submission type and snapshot date. Use avs-addresses to only show rewards from some AVSs.
+  The operator set of operator-directed rewards is read from the RewardsCoordinator events; other rewards
+  are attributed to the AVS only.
 		`,
 		After: telemetry.AfterRunAction(),
 		Flags: getShowFlags(),
@@ -78,6 +83,10 @@ func getShowFlags() []cli.Flag {
 		&ClaimTypeFlag,
 		&ClaimTimestampFlag,
 		&SidecarUrlFlag,
+		&BreakdownFlag,
+		&AVSAddressesFlag,
+		&NumberOfDaysFlag,
+		&ToDateFlag,
 	}
 
 	sort.Sort(cli.FlagsByName(baseFlags))
@@ -100,11 +109,15 @@ func ShowRewards(cCtx *cli.Context) error {
 
 	cCtx.App.Metadata["network"] = config.ChainID.String()
 
-	_, _, blockHeight, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, logger, sidecarClient)
+	_, rootIndex, blockHeight, err := getClaimDistributionRoot(ctx, config.ClaimTimestamp, logger, sidecarClient)
 	if err != nil {
 		return eigenSdkUtils.WrapError("failed to get claim distribution root", err)
 	}
 
+	if config.Breakdown {
+		return showRewardsBreakdown(ctx, config, sidecarClient, rootIndex, logger)
+	}
+
 	summarizedRewards, err := sidecarClient.GetSummarizedRewardsForEarner(
 		ctx,
 		&rewardsV1.GetSummarizedRewardsForEarnerRequest{
@@ -194,7 +207,7 @@ func handleRewardsOutput(
 ) error {
 	client, err := ethclient.Dial(cfg.RPCUrl)
 	if err != nil {
-		return err
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
 	}
 	allRewards := make(allRewardsJson, 0)
 	for address, amount := range rewards {
@@ -204,7 +217,7 @@ func handleRewardsOutput(
 			Amount:    amount.String(),
 		})
 	}
-	if cfg.OutputType == "json" {
+	if cfg.OutputType == utils.JsonOutputType {
 		out, err := json.MarshalIndent(allRewards, "", "  ")
 		if err != nil {
 			return err
@@ -299,6 +312,33 @@ func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfi
 	}
 	logger.Debugf("Claim Type: %s", claimType)
 
+	breakdown := cCtx.Bool(BreakdownFlag.Name)
+	if breakdown && claimType != All {
+		return nil, errors.New("breakdown is only available for claim type 'all'")
+	}
+	numberOfDays := cCtx.Int(NumberOfDaysFlag.Name)
+	startDate := time.Now().UTC().AddDate(0, 0, numberOfDays).Format(time.DateOnly)
+	endDate := cCtx.String(ToDateFlag.Name)
+	if !common.IsEmptyString(endDate) {
+		if _, err := time.Parse(time.DateOnly, endDate); err != nil {
+			return nil, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", endDate)
+		}
+		if endDate < startDate {
+			return nil, fmt.Errorf("to date %s must not be before the start date %s", endDate, startDate)
+		}
+	}
+	var avsAddresses []gethcommon.Address
+	avsAddressesString := cCtx.String(AVSAddressesFlag.Name)
+	if !common.IsEmptyString(avsAddressesString) {
+		for _, avs := range strings.Split(avsAddressesString, ",") {
+			avs = strings.TrimSpace(avs)
+			if !gethcommon.IsHexAddress(avs) {
+				return nil, fmt.Errorf("invalid AVS address: %s", avs)
+			}
+			avsAddresses = append(avsAddresses, gethcommon.HexToAddress(avs))
+		}
+	}
+
 	claimTimestamp := cCtx.String(ClaimTimestampFlag.Name)
 	if claimTimestamp != LatestTimestamp && claimTimestamp != LatestActiveTimestamp {
 		return nil, errors.New("claim timestamp must be 'latest' or 'latest_active'")
@@ -329,5 +369,10 @@ func readAndValidateConfig(cCtx *cli.Context, logger logging.Logger) (*ShowConfi
 		ClaimTimestamp:            claimTimestamp,
 		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
 		SidecarHttpRpcURL:         sidecarUrl,
+		NumberOfDays:              int64(numberOfDays),
+		StartDate:                 startDate,
+		EndDate:                   endDate,
+		Breakdown:                 breakdown,
+		AvsAddresses:              avsAddresses,
 	}, nil
 }
@@ -0,0 +1,423 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+type avsRewardsReader interface {
+	GetRewardsByAvsForDistributionRoot(
+		ctx context.Context,
+		in *rewardsV1.GetRewardsByAvsForDistributionRootRequest,
+	) (*rewardsV1.GetRewardsByAvsForDistributionRootResponse, error)
+}
+
+type tokenInfo struct {
+	symbol   string
+	decimals uint8
+}
+
+// rewardsBreakdownKey identifies a row of the breakdown. The sidecar returns one reward per rewards
+// submission, so rewards of the same AVS, operator set, type, token and snapshot are summed.
+type rewardsBreakdownKey struct {
+	snapshot       string
+	avs            gethcommon.Address
+	hasOperatorSet bool
+	operatorSetId  uint32
+	rewardType     string
+	token          gethcommon.Address
+}
+
+type rewardsBreakdownRow struct {
+	rewardsBreakdownKey
+	amount *big.Int
+}
+
+// getRewardTypeName returns the name of the rewards submission type of a reward
+func getRewardTypeName(rewardType rewardsV1.RewardType) string {
+	switch rewardType {
+	case rewardsV1.RewardType_REWARD_TYPE_AVS:
+		return "avs_rewards"
+	case rewardsV1.RewardType_REWARD_TYPE_FOR_ALL:
+		return "rewards_for_all"
+	case rewardsV1.RewardType_REWARD_TYPE_FOR_ALL_EARNERS:
+		return "programmatic_incentives"
+	default:
+		return strings.ToLower(strings.TrimPrefix(rewardType.String(), "REWARD_TYPE_"))
+	}
+}
+
+// getSnapshotDate returns the date of a snapshot, which the sidecar returns either as a date or a timestamp
+func getSnapshotDate(snapshot string) (string, error) {
+	if len(snapshot) < len(time.DateOnly) {
+		return "", fmt.Errorf("invalid snapshot %q", snapshot)
+	}
+	date, err := time.Parse(time.DateOnly, snapshot[:len(time.DateOnly)])
+	if err != nil {
+		return "", eigenSdkUtils.WrapError(fmt.Sprintf("invalid snapshot %q", snapshot), err)
+	}
+	return date.Format(time.DateOnly), nil
+}
+
+// buildRewardsBreakdown returns the rewards of the earner on snapshots from startDate to endDate, optionally
+// limited to the given AVSs. An empty endDate doesn't limit the range. Rewards whose submission hash is in
+// operatorSets are attributed to that operator set of the AVS. Rows are sorted by snapshot, most recent
+// first, then by AVS, operator set, type and token.
+func buildRewardsBreakdown(
+	rewards []*rewardsV1.AvsReward,
+	earnerAddress gethcommon.Address,
+	avsAddresses []gethcommon.Address,
+	startDate string,
+	endDate string,
+	operatorSets map[gethcommon.Hash]uint32,
+) ([]rewardsBreakdownRow, error) {
+	avsFilter := make(map[gethcommon.Address]bool, len(avsAddresses))
+	for _, avs := range avsAddresses {
+		avsFilter[avs] = true
+	}
+
+	amounts := make(map[rewardsBreakdownKey]*big.Int)
+	for _, reward := range rewards {
+		if !strings.EqualFold(reward.Earner, earnerAddress.Hex()) {
+			continue
+		}
+		avs := gethcommon.HexToAddress(reward.Avs)
+		if len(avsFilter) > 0 && !avsFilter[avs] {
+			continue
+		}
+		snapshot, err := getSnapshotDate(reward.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot < startDate || (endDate != "" && snapshot > endDate) {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(reward.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q for AVS %s on %s", reward.Amount, reward.Avs, snapshot)
+		}
+
+		key := rewardsBreakdownKey{
+			snapshot:   snapshot,
+			avs:        avs,
+			rewardType: getRewardTypeName(reward.RewardType),
+			token:      gethcommon.HexToAddress(reward.Token),
+		}
+		if operatorSetId, ok := operatorSets[gethcommon.HexToHash(reward.RewardHash)]; ok {
+			key.hasOperatorSet = true
+			key.operatorSetId = operatorSetId
+		}
+		if _, ok := amounts[key]; !ok {
+			amounts[key] = big.NewInt(0)
+		}
+		amounts[key].Add(amounts[key], amount)
+	}
+
+	rows := make([]rewardsBreakdownRow, 0, len(amounts))
+	for key, amount := range amounts {
+		rows = append(rows, rewardsBreakdownRow{rewardsBreakdownKey: key, amount: amount})
+	}
+	sortRewardsBreakdown(rows)
+	return rows, nil
+}
+
+// sortRewardsBreakdown sorts rows by snapshot, most recent first, then by AVS, operator set, type and token
+func sortRewardsBreakdown(rows []rewardsBreakdownRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.snapshot != b.snapshot {
+			return a.snapshot > b.snapshot
+		}
+		if a.avs != b.avs {
+			return bytes.Compare(a.avs.Bytes(), b.avs.Bytes()) < 0
+		}
+		if a.hasOperatorSet != b.hasOperatorSet {
+			return !a.hasOperatorSet
+		}
+		if a.operatorSetId != b.operatorSetId {
+			return a.operatorSetId < b.operatorSetId
+		}
+		if a.rewardType != b.rewardType {
+			return a.rewardType < b.rewardType
+		}
+		return bytes.Compare(a.token.Bytes(), b.token.Bytes()) < 0
+	})
+}
+
+// getAvsTotals sums the rows of the breakdown per AVS and token
+func getAvsTotals(rows []rewardsBreakdownRow) []rewardsBreakdownRow {
+	return getRewardsTotals(rows, func(row rewardsBreakdownRow) (rewardsBreakdownKey, bool) {
+		return rewardsBreakdownKey{avs: row.avs, token: row.token}, true
+	})
+}
+
+// getOperatorSetTotals sums the rows of the breakdown which are attributed to an operator set per operator
+// set and token
+func getOperatorSetTotals(rows []rewardsBreakdownRow) []rewardsBreakdownRow {
+	return getRewardsTotals(rows, func(row rewardsBreakdownRow) (rewardsBreakdownKey, bool) {
+		return rewardsBreakdownKey{
+			avs:            row.avs,
+			hasOperatorSet: true,
+			operatorSetId:  row.operatorSetId,
+			token:          row.token,
+		}, row.hasOperatorSet
+	})
+}
+
+// getRewardsTotals sums the amounts of the rows with the same key. Rows for which getKey returns false are
+// skipped.
+func getRewardsTotals(
+	rows []rewardsBreakdownRow,
+	getKey func(rewardsBreakdownRow) (rewardsBreakdownKey, bool),
+) []rewardsBreakdownRow {
+	totals := make(map[rewardsBreakdownKey]*big.Int)
+	for _, row := range rows {
+		key, ok := getKey(row)
+		if !ok {
+			continue
+		}
+		if _, ok := totals[key]; !ok {
+			totals[key] = big.NewInt(0)
+		}
+		totals[key].Add(totals[key], row.amount)
+	}
+
+	result := make([]rewardsBreakdownRow, 0, len(totals))
+	for key, amount := range totals {
+		result = append(result, rewardsBreakdownRow{rewardsBreakdownKey: key, amount: amount})
+	}
+	sortRewardsBreakdown(result)
+	return result
+}
+
+// getRewardOperatorSets returns the operator set of each operator-directed operator set rewards submission
+// created from startDate on. Operator-directed rewards are retroactive, so the submissions of rewards on
+// snapshots from startDate on are created after startDate.
+func getRewardOperatorSets(
+	ctx context.Context,
+	ethClient *ethclient.Client,
+	rewardsCoordinatorAddress gethcommon.Address,
+	startDate string,
+) (map[gethcommon.Hash]uint32, error) {
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(rewardsCoordinatorAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	latestBlock, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return nil, err
+	}
+	fromBlock, err := common.FindBlockByTimestamp(ctx, ethClient, latestBlock, uint64(startTime.Unix()))
+	if err != nil {
+		return nil, err
+	}
+
+	operatorSets := make(map[gethcommon.Hash]uint32)
+	err = common.FilterLogsInRanges(ctx, fromBlock, latestBlock, func(opts *bind.FilterOpts) error {
+		iterator, err := rewardsCoordinator.FilterOperatorDirectedOperatorSetRewardsSubmissionCreated(opts, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer iterator.Close()
+		for iterator.Next() {
+			event := iterator.Event
+			operatorSets[event.OperatorDirectedRewardsSubmissionHash] = event.OperatorSet.Id
+		}
+		return iterator.Error()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return operatorSets, nil
+}
+
+func showRewardsBreakdown(
+	ctx context.Context,
+	cfg *ShowConfig,
+	reader avsRewardsReader,
+	rootIndex uint32,
+	logger logging.Logger,
+) error {
+	response, err := reader.GetRewardsByAvsForDistributionRoot(
+		ctx,
+		&rewardsV1.GetRewardsByAvsForDistributionRootRequest{RootIndex: uint64(rootIndex)},
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get rewards by AVS for distribution root", err)
+	}
+
+	client, err := ethclient.Dial(cfg.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	// The sidecar does not report the operator set of a reward, so it is read from the submission events
+	operatorSets, err := getRewardOperatorSets(ctx, client, cfg.RewardsCoordinatorAddress, cfg.StartDate)
+	if err != nil {
+		logger.Warnf("Failed to read operator set rewards submissions, rewards are shown by AVS only: %s", err)
+	}
+
+	rows, err := buildRewardsBreakdown(
+		response.Rewards,
+		cfg.EarnerAddress,
+		cfg.AvsAddresses,
+		cfg.StartDate,
+		cfg.EndDate,
+		operatorSets,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to build rewards breakdown", err)
+	}
+
+	tokens := make(map[gethcommon.Address]tokenInfo)
+	getTokenInfo := func(token gethcommon.Address) tokenInfo {
+		if info, ok := tokens[token]; ok {
+			return info
+		}
+		symbol, decimals := erc20.GetTokenSymbolAndDecimals(token, client)
+		tokens[token] = tokenInfo{symbol: symbol, decimals: decimals}
+		return tokens[token]
+	}
+
+	output := rewardsBreakdownOutput{
+		EarnerAddress:     cfg.EarnerAddress.Hex(),
+		StartDate:         cfg.StartDate,
+		EndDate:           cfg.EndDate,
+		Rewards:           toRewardsBreakdownJson(rows, getTokenInfo),
+		AvsTotals:         toRewardsBreakdownJson(getAvsTotals(rows), getTokenInfo),
+		OperatorSetTotals: toRewardsBreakdownJson(getOperatorSetTotals(rows), getTokenInfo),
+	}
+
+	if cfg.OutputType == utils.JsonOutputType {
+		out, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		if cfg.Output != "" {
+			return common.WriteToFile(out, cfg.Output)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Println()
+	if cfg.EndDate != "" {
+		fmt.Printf("> Showing rewards earned by %s from %s to %s\n", output.EarnerAddress, cfg.StartDate, cfg.EndDate)
+	} else {
+		fmt.Printf("> Showing rewards earned by %s since %s\n", output.EarnerAddress, cfg.StartDate)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 30), "Rewards by AVS", strings.Repeat("-", 30))
+	printRewardsBreakdown(output.AvsTotals, false, false)
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 30), "Rewards by Operator Set", strings.Repeat("-", 30))
+	printRewardsBreakdown(output.OperatorSetTotals, true, false)
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 30), "Rewards by Snapshot", strings.Repeat("-", 30))
+	printRewardsBreakdown(output.Rewards, true, true)
+	return nil
+}
+
+func toRewardsBreakdownJson(
+	rows []rewardsBreakdownRow,
+	getTokenInfo func(gethcommon.Address) tokenInfo,
+) []rewardsBreakdownJson {
+	out := make([]rewardsBreakdownJson, 0, len(rows))
+	for _, row := range rows {
+		info := getTokenInfo(row.token)
+		rowJson := rewardsBreakdownJson{
+			Snapshot:        row.snapshot,
+			AvsAddress:      row.avs.Hex(),
+			RewardType:      row.rewardType,
+			TokenAddress:    row.token.Hex(),
+			TokenSymbol:     info.symbol,
+			Amount:          row.amount.String(),
+			FormattedAmount: erc20.FormatAmount(row.amount, info.decimals),
+		}
+		if row.hasOperatorSet {
+			operatorSetId := row.operatorSetId
+			rowJson.OperatorSetId = &operatorSetId
+		}
+		out = append(out, rowJson)
+	}
+	return out
+}
+
+func printRewardsBreakdown(rows []rewardsBreakdownJson, withOperatorSet bool, withSnapshot bool) {
+	headers := []string{"AVS Address", "Token", "Amount"}
+	widths := []int{44, 12, 30}
+	if withOperatorSet {
+		headers = []string{"AVS Address", "Operator Set", "Token", "Amount"}
+		widths = []int{44, 12, 12, 30}
+	}
+	if withSnapshot {
+		headers = []string{"Snapshot", "AVS Address", "Operator Set", "Type", "Token", "Amount"}
+		widths = []int{12, 44, 12, 24, 12, 30}
+	}
+
+	printSeparator := func(sep string, end string) {
+		for _, width := range widths {
+			fmt.Print(sep + strings.Repeat("-", width+1))
+		}
+		fmt.Println(end)
+	}
+
+	printSeparator("+", "+")
+	for i, header := range headers {
+		fmt.Printf("| %-*s", widths[i], header)
+	}
+	fmt.Println("|")
+	printSeparator("|", "|")
+
+	if len(rows) == 0 {
+		fmt.Println("No rewards found")
+	}
+	for _, row := range rows {
+		operatorSet := "-"
+		if row.OperatorSetId != nil {
+			operatorSet = strconv.FormatUint(uint64(*row.OperatorSetId), 10)
+		}
+		values := []string{row.AvsAddress, row.TokenSymbol, row.FormattedAmount}
+		if withOperatorSet {
+			values = []string{row.AvsAddress, operatorSet, row.TokenSymbol, row.FormattedAmount}
+		}
+		if withSnapshot {
+			values = []string{
+				row.Snapshot,
+				row.AvsAddress,
+				operatorSet,
+				row.RewardType,
+				row.TokenSymbol,
+				row.FormattedAmount,
+			}
+		}
+		for i, value := range values {
+			fmt.Printf("| %-*s", widths[i], value)
+		}
+		fmt.Println("|")
+	}
+	printSeparator("+", "+")
+}
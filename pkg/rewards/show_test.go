@@ -4,8 +4,14 @@ import (
 	"context"
 	"errors"
 	"math/big"
+	"strings"
+	"testing"
+
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // FakeELReader is a mock implementation of the ELReader interface
@@ -23,3 +29,96 @@ func (f *FakeELReader) GetCumulativeClaimed(
 	}
 	return f.claimedRewards[tokenAddress], nil
 }
+
+func TestBuildRewardsBreakdown(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	avsOne := gethcommon.HexToAddress("0xa000000000000000000000000000000000000001")
+	avsTwo := gethcommon.HexToAddress("0xb000000000000000000000000000000000000002")
+	token := gethcommon.HexToAddress("0xc000000000000000000000000000000000000003")
+	reward := func(
+		earner gethcommon.Address,
+		avs gethcommon.Address,
+		amount string,
+		snapshot string,
+		rewardType rewardsV1.RewardType,
+	) *rewardsV1.AvsReward {
+		return &rewardsV1.AvsReward{
+			Earner:     strings.ToLower(earner.Hex()),
+			Avs:        strings.ToLower(avs.Hex()),
+			Token:      strings.ToLower(token.Hex()),
+			Amount:     amount,
+			Snapshot:   snapshot,
+			RewardType: rewardType,
+		}
+	}
+	rewards := []*rewardsV1.AvsReward{
+		reward(earner, avsOne, "100", "2025-01-02", rewardsV1.RewardType_REWARD_TYPE_AVS),
+		// Rewards of two submissions of the same AVS and type are summed
+		reward(earner, avsOne, "50", "2025-01-02T00:00:00Z", rewardsV1.RewardType_REWARD_TYPE_AVS),
+		reward(earner, avsOne, "10", "2025-01-02", rewardsV1.RewardType_REWARD_TYPE_FOR_ALL_EARNERS),
+		reward(earner, avsTwo, "200", "2025-01-03", rewardsV1.RewardType_REWARD_TYPE_AVS),
+		reward(earner, avsTwo, "300", "2024-12-31", rewardsV1.RewardType_REWARD_TYPE_AVS),
+		reward(avsTwo, avsOne, "1000", "2025-01-02", rewardsV1.RewardType_REWARD_TYPE_AVS),
+	}
+
+	t.Run("all AVSs", func(t *testing.T) {
+		rows, err := buildRewardsBreakdown(rewards, earner, nil, "2025-01-01", "", nil)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "2025-01-03", rows[0].snapshot)
+		assert.Equal(t, avsTwo, rows[0].avs)
+		assert.Equal(t, "avs_rewards", rows[1].rewardType)
+		assert.Equal(t, big.NewInt(150), rows[1].amount)
+		assert.Equal(t, "programmatic_incentives", rows[2].rewardType)
+
+		totals := getAvsTotals(rows)
+		assert.Len(t, totals, 2)
+		assert.Equal(t, avsOne, totals[0].avs)
+		assert.Equal(t, big.NewInt(160), totals[0].amount)
+		assert.Equal(t, big.NewInt(200), totals[1].amount)
+	})
+
+	t.Run("filtered by AVS", func(t *testing.T) {
+		rows, err := buildRewardsBreakdown(rewards, earner, []gethcommon.Address{avsTwo}, "2024-12-01", "", nil)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, big.NewInt(500), getAvsTotals(rows)[0].amount)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		rows, err := buildRewardsBreakdown(rewards, earner, nil, "2024-12-31", "2025-01-02", nil)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "2025-01-02", rows[0].snapshot)
+		assert.Equal(t, "2024-12-31", rows[2].snapshot)
+	})
+
+	t.Run("operator sets", func(t *testing.T) {
+		operatorSetReward := reward(earner, avsOne, "40", "2025-01-02", rewardsV1.RewardType_REWARD_TYPE_AVS)
+		operatorSetReward.RewardHash = "0x01"
+		withOperatorSet := append([]*rewardsV1.AvsReward{operatorSetReward}, rewards...)
+		operatorSets := map[gethcommon.Hash]uint32{gethcommon.HexToHash("0x01"): 3}
+
+		rows, err := buildRewardsBreakdown(withOperatorSet, earner, nil, "2025-01-02", "2025-01-02", operatorSets)
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		// Rewards without an operator set come first
+		assert.False(t, rows[0].hasOperatorSet)
+		assert.Equal(t, big.NewInt(150), rows[0].amount)
+		assert.True(t, rows[2].hasOperatorSet)
+		assert.Equal(t, uint32(3), rows[2].operatorSetId)
+		assert.Equal(t, big.NewInt(40), rows[2].amount)
+
+		assert.Equal(t, big.NewInt(200), getAvsTotals(rows)[0].amount)
+		operatorSetTotals := getOperatorSetTotals(rows)
+		assert.Len(t, operatorSetTotals, 1)
+		assert.Equal(t, uint32(3), operatorSetTotals[0].operatorSetId)
+		assert.Equal(t, big.NewInt(40), operatorSetTotals[0].amount)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		invalid := []*rewardsV1.AvsReward{reward(earner, avsOne, "1", "2025", rewardsV1.RewardType_REWARD_TYPE_AVS)}
+		_, err := buildRewardsBreakdown(invalid, earner, nil, "2025-01-01", "", nil)
+		assert.Error(t, err)
+	})
+}
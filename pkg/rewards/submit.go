@@ -0,0 +1,1172 @@
+package rewards
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/erc20"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/elcontracts"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	servicemanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/ServiceManagerBase"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gocarina/gocsv"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	avsRewardsSubmissionType       = "avs-rewards"
+	operatorDirectedSubmissionType = "operator-directed"
+
+	approveTxType                = "approve"
+	createAVSRewardsTxType       = "createAVSRewardsSubmission"
+	createOperatorDirectedTxType = "createOperatorDirectedAVSRewardsSubmission"
+
+	// Gas limits of unsigned transactions whose gas can't be estimated. A submission transaction gets the
+	// submission gas limit for each of its submissions, plus the gas limits of their strategies and operators.
+	approveGasLimit                  = 150_000
+	rewardsSubmissionGasLimit        = 150_000
+	strategyMultiplierGasLimit       = 15_000
+	operatorRewardGasLimit           = 10_000
+	serviceManagerSubmissionGasLimit = 100_000
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+var (
+	// maxRewardsAmount is MAX_REWARDS_AMOUNT of the RewardsCoordinator, 1e38 - 1
+	maxRewardsAmount = new(big.Int).Sub(new(big.Int).Exp(big.NewInt(10), big.NewInt(38), nil), big.NewInt(1))
+
+	// maxMultiplier is the largest multiplier, which is an uint96 in the RewardsCoordinator
+	maxMultiplier = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+)
+
+// rewardsSubmissionParamsReader reads the constants the RewardsCoordinator validates submissions against
+type rewardsSubmissionParamsReader interface {
+	CALCULATIONINTERVALSECONDS(opts *bind.CallOpts) (uint32, error)
+	MAXREWARDSDURATION(opts *bind.CallOpts) (uint32, error)
+	MAXRETROACTIVELENGTH(opts *bind.CallOpts) (uint32, error)
+	MAXFUTURELENGTH(opts *bind.CallOpts) (uint32, error)
+	GENESISREWARDSTIMESTAMP(opts *bind.CallOpts) (uint32, error)
+}
+
+type rewardsSubmissionParams struct {
+	calculationInterval     uint32
+	maxRewardsDuration      uint32
+	maxRetroactiveLength    uint32
+	maxFutureLength         uint32
+	genesisRewardsTimestamp uint32
+}
+
+// rewardsSubmissionEntry is a rewards submission of the submission file. Amounts and multipliers are in the
+// smallest unit, the start timestamp is a unix timestamp or a YYYY-MM-DD date and the duration is a number of
+// seconds or a duration such as 7d or 168h.
+type rewardsSubmissionEntry struct {
+	Token           string                    `yaml:"token"`
+	Amount          string                    `yaml:"amount"`
+	StartTimestamp  string                    `yaml:"start_timestamp"`
+	Duration        string                    `yaml:"duration"`
+	Description     string                    `yaml:"description"`
+	Strategies      []strategyMultiplierEntry `yaml:"strategies"`
+	OperatorRewards []operatorRewardEntry     `yaml:"operator_rewards"`
+}
+
+type strategyMultiplierEntry struct {
+	Strategy   string `yaml:"strategy"`
+	Multiplier string `yaml:"multiplier"`
+}
+
+type operatorRewardEntry struct {
+	Operator string `yaml:"operator"`
+	Amount   string `yaml:"amount"`
+}
+
+// rewardsSubmissionCsvRow is a row of a CSV submission file. Rows with the same submission id are merged
+// into a single submission, each row adding a strategy, an operator reward or both.
+type rewardsSubmissionCsvRow struct {
+	Submission     string `csv:"submission"`
+	Token          string `csv:"token"`
+	Amount         string `csv:"amount"`
+	StartTimestamp string `csv:"start_timestamp"`
+	Duration       string `csv:"duration"`
+	Description    string `csv:"description"`
+	Strategy       string `csv:"strategy"`
+	Multiplier     string `csv:"multiplier"`
+	Operator       string `csv:"operator"`
+	OperatorAmount string `csv:"operator_amount"`
+}
+
+// rewardsSubmission is a parsed submission. The amount of an operator-directed submission is the sum of its
+// operator rewards.
+type rewardsSubmission struct {
+	strategies      []rewardscoordinator.IRewardsCoordinatorTypesStrategyAndMultiplier
+	token           gethcommon.Address
+	amount          *big.Int
+	operatorRewards []rewardscoordinator.IRewardsCoordinatorTypesOperatorReward
+	startTimestamp  uint32
+	duration        uint32
+	description     string
+}
+
+type tokenAllowance struct {
+	token     gethcommon.Address
+	symbol    string
+	decimals  uint8
+	required  *big.Int
+	allowance *big.Int
+	balance   *big.Int
+}
+
+// rewardsInitiatorReader reads the account allowed to submit rewards through a ServiceManager
+type rewardsInitiatorReader interface {
+	RewardsInitiator(opts *bind.CallOpts) (gethcommon.Address, error)
+}
+
+// submitRewardsTx builds one of the transactions of a rewards submission
+type submitRewardsTx struct {
+	txType   string
+	gasLimit uint64
+	build    func(opts *bind.TransactOpts) (*gethtypes.Transaction, error)
+}
+
+func SubmitCmd(p utils.Prompter) *cli.Command {
+	submitCmd := &cli.Command{
+		Name:  "submit",
+		Usage: "Submit rewards to the RewardsCoordinator as an AVS",
+		Subcommands: []*cli.Command{
+			submitAVSRewardsCmd(p),
+			submitOperatorDirectedCmd(p),
+		},
+	}
+	return submitCmd
+}
+
+func submitAVSRewardsCmd(p utils.Prompter) *cli.Command {
+	return &cli.Command{
+		Name:      avsRewardsSubmissionType,
+		Usage:     "Submit rewards to the stakers and operators of the AVS, weighted by strategy multipliers",
+		UsageText: "avs-rewards --avs-address <avs-address> --file <submissions.yaml> [flags]",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Builds a createAVSRewardsSubmission call from a YAML or CSV file. The RewardsCoordinator only accepts the
+call from the AVS, so the caller address is the AVS address.
+
+AVSs built on the ServiceManagerBase of the middleware submit through the createAVSRewardsSubmission of
+their ServiceManager, which only its rewards initiator can call. Use --service-manager to send the call to
+the ServiceManager at the AVS address, with the rewards initiator as caller. The caller then approves the
+ServiceManager, which transfers the tokens to the RewardsCoordinator.
+
+YAML files are a list of submissions with the keys token, amount, start_timestamp, duration and strategies,
+a list of strategy and multiplier. CSV files have the columns
+'submission,token,amount,start_timestamp,duration,strategy,multiplier' with one row per strategy; rows with
+the same submission id make up one submission.
+
+Amounts and multipliers are in the smallest unit. start_timestamp is a unix timestamp or a YYYY-MM-DD date
+and duration is a number of seconds or a duration such as 7d or 168h. Both must be multiples of the
+calculation interval of the RewardsCoordinator, and the start must be within its retroactive and future
+limits as of the latest block.
+
+The caller must have approved the RewardsCoordinator, or the ServiceManager, to transfer the tokens. Use
+--approve to add the missing approvals before the submission.
+
+With --output-type calldata, the calldata of each transaction is printed on its own line, in the order the
+transactions must be sent: the approvals, to the token contracts, then the submission.
+`,
+		Flags: getSubmitFlags(avsRewardsSubmissionType),
+		Action: func(cCtx *cli.Context) error {
+			return submitRewardsAction(cCtx, p, avsRewardsSubmissionType)
+		},
+	}
+}
+
+func submitOperatorDirectedCmd(p utils.Prompter) *cli.Command {
+	return &cli.Command{
+		Name:      operatorDirectedSubmissionType,
+		Usage:     "Submit retroactive rewards with an amount for each operator",
+		UsageText: "operator-directed --avs-address <avs-address> --file <submissions.yaml> [flags]",
+		After:     telemetry.AfterRunAction(),
+		Description: `
+Builds a createOperatorDirectedAVSRewardsSubmission call from a YAML or CSV file. The caller is the AVS or
+an appointee of the AVS allowed to call createOperatorDirectedAVSRewardsSubmission.
+
+YAML files are a list of submissions with the keys token, start_timestamp, duration, description, strategies,
+a list of strategy and multiplier, and operator_rewards, a list of operator and amount. CSV files have the
+columns 'submission,token,start_timestamp,duration,description,strategy,multiplier,operator,operator_amount'
+where each row adds a strategy, an operator reward or both; rows with the same submission id make up one
+submission.
+
+Amounts and multipliers are in the smallest unit. start_timestamp is a unix timestamp or a YYYY-MM-DD date
+and duration is a number of seconds or a duration such as 7d or 168h. Both must be multiples of the
+calculation interval of the RewardsCoordinator, and operator-directed submissions must have ended before
+the latest block.
+
+The caller must have approved the RewardsCoordinator to transfer the tokens. Use --approve to add the
+missing approvals before the submission.
+
+With --output-type calldata, the calldata of each transaction is printed on its own line, in the order the
+transactions must be sent: the approvals, to the token contracts, then the submission.
+`,
+		Flags: getSubmitFlags(operatorDirectedSubmissionType),
+		Action: func(cCtx *cli.Context) error {
+			return submitRewardsAction(cCtx, p, operatorDirectedSubmissionType)
+		},
+	}
+}
+
+func getSubmitFlags(submissionType string) []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.OutputFileFlag,
+		&flags.OutputTypeFlag,
+		&flags.BroadcastFlag,
+		&flags.VerboseFlag,
+		&flags.SilentFlag,
+		&flags.AVSAddressFlag,
+		&flags.CallerAddressFlag,
+		&flags.SafeBatchFileFlag,
+		&ApproveFlag,
+		&RewardsCoordinatorAddressFlag,
+		&SubmissionFileFlag,
+	}
+	if submissionType == avsRewardsSubmissionType {
+		baseFlags = append(baseFlags, &ServiceManagerFlag)
+	}
+	allFlags := append(baseFlags, flags.GetSignerFlags()...)
+	sort.Sort(cli.FlagsByName(allFlags))
+	return allFlags
+}
+
+func submitRewardsAction(cCtx *cli.Context, p utils.Prompter, submissionType string) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateSubmitRewardsConfig(cCtx, logger, submissionType)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate submit rewards config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	_, _, contractBindings, err := elcontracts.BuildClients(
+		elcontracts.Config{RewardsCoordinatorAddress: config.RewardsCoordinatorAddress},
+		ethClient,
+		nil,
+		logger,
+		nil,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create contract bindings", err)
+	}
+	var serviceManager *servicemanager.ContractServiceManagerBase
+	if config.ServiceManager {
+		serviceManager, err = servicemanager.NewContractServiceManagerBase(config.AVSAddress, ethClient)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to create service manager binding", err)
+		}
+		err = resolveRewardsInitiatorCaller(&bind.CallOpts{Context: ctx}, serviceManager, config, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	params, err := getRewardsSubmissionParams(&bind.CallOpts{Context: ctx}, contractBindings.RewardsCoordinator)
+	if err != nil {
+		return err
+	}
+	header, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get latest block header", err)
+	}
+	for i, submission := range config.Submissions {
+		if err := validateRewardsSubmission(submission, submissionType, params, header.Time); err != nil {
+			return fmt.Errorf("submission %d: %w", i+1, err)
+		}
+	}
+
+	allowances, err := getTokenAllowances(
+		&bind.CallOpts{Context: ctx},
+		ethClient,
+		config.CallerAddress,
+		config.SpenderAddress,
+		getSubmissionTotals(config.Submissions),
+	)
+	if err != nil {
+		return err
+	}
+	approvals := getRequiredApprovals(allowances)
+
+	if config.OutputType != utils.CallDataOutputType || config.Broadcast {
+		printRewardsSubmissions(config, allowances)
+	}
+	for _, allowance := range allowances {
+		if allowance.balance.Cmp(allowance.required) < 0 {
+			logger.Warnf(
+				"Balance of %s %s is below the %s %s to submit",
+				erc20.FormatAmount(allowance.balance, allowance.decimals),
+				allowance.symbol,
+				erc20.FormatAmount(allowance.required, allowance.decimals),
+				allowance.symbol,
+			)
+		}
+	}
+	if len(approvals) > 0 && !config.Approve {
+		spenderName := getSubmissionSpenderName(config)
+		if config.Broadcast {
+			return fmt.Errorf("the allowance of the %s is too low, use --approve to approve it first", spenderName)
+		}
+		logger.Warnf("The allowance of the %s is too low, use --approve to add the approvals", spenderName)
+	}
+
+	txs := make([]submitRewardsTx, 0, len(approvals)+1)
+	if config.Approve {
+		for _, approval := range approvals {
+			token, err := erc20.NewERC20(approval.token, ethClient)
+			if err != nil {
+				return eigenSdkUtils.WrapError("failed to create ERC20 binding", err)
+			}
+			required := approval.required
+			txs = append(txs, submitRewardsTx{
+				txType:   approveTxType,
+				gasLimit: approveGasLimit,
+				build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+					return token.Approve(opts, config.SpenderAddress, required)
+				},
+			})
+		}
+	}
+	txs = append(txs, buildSubmitRewardsTx(contractBindings.RewardsCoordinator, serviceManager, config))
+
+	if config.Broadcast {
+		if config.SignerConfig == nil {
+			return errors.New("signer is required for broadcasting")
+		}
+		confirm, err := p.Confirm(
+			fmt.Sprintf("This will send %d transaction(s) to submit the rewards. Do you want to continue?", len(txs)),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			logger.Info("Operation cancelled")
+			return nil
+		}
+
+		txMgr, err := common.GetTxManager(
+			config.CallerAddress,
+			config.SignerConfig,
+			ethClient,
+			p,
+			config.ChainID,
+			logger,
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError("failed to get tx manager", err)
+		}
+		for _, tx := range txs {
+			// Transactions are built one by one since the submission can only be estimated once the
+			// approvals are mined
+			unsignedTx, err := tx.build(common.GetNoSendTxOpts(config.CallerAddress))
+			if err != nil {
+				return eigenSdkUtils.WrapError(fmt.Sprintf("failed to create %s transaction", tx.txType), err)
+			}
+			receipt, err := txMgr.Send(ctx, unsignedTx, true)
+			if err != nil {
+				return eigenSdkUtils.WrapError(fmt.Sprintf("failed to send %s transaction", tx.txType), err)
+			}
+			if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+				return fmt.Errorf("%s transaction %s failed", tx.txType, receipt.TxHash.Hex())
+			}
+			common.PrintTransactionInfo(receipt.TxHash.String(), config.ChainID)
+		}
+		return nil
+	}
+
+	// Gas can't be estimated for smart contract callers, nor for a submission whose approvals aren't sent
+	// yet, so those transactions get a fixed gas limit
+	fixedGasLimit := common.IsSmartContractAddress(config.CallerAddress, ethClient)
+	unsignedTxs := make([]*gethtypes.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		noSendTxOpts := common.GetNoSendTxOpts(config.CallerAddress)
+		if fixedGasLimit || len(approvals) > 0 {
+			noSendTxOpts.GasLimit = tx.gasLimit
+		}
+		unsignedTx, err := tx.build(noSendTxOpts)
+		if err != nil {
+			return eigenSdkUtils.WrapError(fmt.Sprintf("failed to create unsigned %s transaction", tx.txType), err)
+		}
+		unsignedTxs = append(unsignedTxs, unsignedTx)
+	}
+
+	if !common.IsEmptyString(config.SafeBatchFile) {
+		batch := common.NewSafeBatch(
+			config.ChainID,
+			config.CallerAddress,
+			"Rewards submission",
+			fmt.Sprintf("Submit %s rewards from %s for AVS %s", submissionType, config.File, config.AVSAddress.Hex()),
+		)
+		for _, unsignedTx := range unsignedTxs {
+			batch.AddTransaction(unsignedTx)
+		}
+		if err := batch.WriteToFile(config.SafeBatchFile); err != nil {
+			return err
+		}
+		logger.Infof("Safe transaction batch written to file: %s", config.SafeBatchFile)
+		return nil
+	}
+
+	if config.OutputType == utils.CallDataOutputType {
+		lines := make([]string, 0, len(unsignedTxs))
+		for _, unsignedTx := range unsignedTxs {
+			lines = append(lines, gethcommon.Bytes2Hex(unsignedTx.Data()))
+		}
+		calldata := strings.Join(lines, "\n")
+		if !common.IsEmptyString(config.Output) {
+			err = common.WriteToFile([]byte(calldata), config.Output)
+			if err != nil {
+				return err
+			}
+			logger.Infof("Call data written to file: %s", config.Output)
+		} else {
+			fmt.Println(calldata)
+		}
+	} else if !common.IsEmptyString(config.Output) {
+		fmt.Println("output file not supported for pretty output type")
+		fmt.Println()
+	}
+
+	if !config.IsSilent {
+		for i, unsignedTx := range unsignedTxs {
+			fmt.Println()
+			fmt.Printf("Transaction %d (%s) to %s\n", i+1, txs[i].txType, unsignedTx.To().Hex())
+			common.GetTxFeeDetails(unsignedTx).Print()
+		}
+		fmt.Println("To broadcast the transactions, use the --broadcast flag")
+	}
+	return nil
+}
+
+// buildSubmitRewardsTx builds the submission transaction, sent to the ServiceManager if the config says so
+// and to the RewardsCoordinator otherwise
+func buildSubmitRewardsTx(
+	rewardsCoordinator *rewardscoordinator.ContractRewardsCoordinator,
+	serviceManager *servicemanager.ContractServiceManagerBase,
+	config *SubmitRewardsConfig,
+) submitRewardsTx {
+	gasLimit := getRewardsSubmissionGasLimit(config.Submissions, config.ServiceManager)
+	if config.ServiceManager {
+		submissions := toServiceManagerRewardsSubmissions(toAVSRewardsSubmissions(config.Submissions))
+		return submitRewardsTx{
+			txType:   createAVSRewardsTxType,
+			gasLimit: gasLimit,
+			build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+				return serviceManager.CreateAVSRewardsSubmission(opts, submissions)
+			},
+		}
+	}
+	if config.SubmissionType == avsRewardsSubmissionType {
+		submissions := toAVSRewardsSubmissions(config.Submissions)
+		return submitRewardsTx{
+			txType:   createAVSRewardsTxType,
+			gasLimit: gasLimit,
+			build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+				return rewardsCoordinator.CreateAVSRewardsSubmission(opts, submissions)
+			},
+		}
+	}
+	submissions := toOperatorDirectedRewardsSubmissions(config.Submissions)
+	return submitRewardsTx{
+		txType:   createOperatorDirectedTxType,
+		gasLimit: gasLimit,
+		build: func(opts *bind.TransactOpts) (*gethtypes.Transaction, error) {
+			return rewardsCoordinator.CreateOperatorDirectedAVSRewardsSubmission(opts, config.AVSAddress, submissions)
+		},
+	}
+}
+
+// getRewardsSubmissionGasLimit returns the gas limit of a submission transaction whose gas can't be
+// estimated. Submissions through a ServiceManager also pay for the transfer of the tokens to the
+// ServiceManager and for its approval of the RewardsCoordinator.
+func getRewardsSubmissionGasLimit(submissions []*rewardsSubmission, serviceManager bool) uint64 {
+	var gasLimit uint64
+	for _, submission := range submissions {
+		gasLimit += rewardsSubmissionGasLimit
+		gasLimit += uint64(len(submission.strategies)) * strategyMultiplierGasLimit
+		gasLimit += uint64(len(submission.operatorRewards)) * operatorRewardGasLimit
+		if serviceManager {
+			gasLimit += serviceManagerSubmissionGasLimit
+		}
+	}
+	return gasLimit
+}
+
+// resolveRewardsInitiatorCaller defaults the caller to the rewards initiator of the ServiceManager, and
+// checks that a caller provided by the user is the rewards initiator
+func resolveRewardsInitiatorCaller(
+	opts *bind.CallOpts,
+	reader rewardsInitiatorReader,
+	config *SubmitRewardsConfig,
+	logger logging.Logger,
+) error {
+	rewardsInitiator, err := reader.RewardsInitiator(opts)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to get rewards initiator of the service manager", err)
+	}
+	if config.CallerAddress == utils.ZeroAddress {
+		logger.Infof(
+			"Caller address not provided. Using rewards initiator of the service manager as default address (%s)",
+			rewardsInitiator.Hex(),
+		)
+		config.CallerAddress = rewardsInitiator
+		return nil
+	}
+	if config.CallerAddress != rewardsInitiator {
+		return fmt.Errorf(
+			"submissions through the service manager must be sent by its rewards initiator %s, not by %s",
+			rewardsInitiator.Hex(),
+			config.CallerAddress.Hex(),
+		)
+	}
+	return nil
+}
+
+// getSubmissionSpenderName returns the name of the contract the caller approves to transfer the tokens
+func getSubmissionSpenderName(config *SubmitRewardsConfig) string {
+	if config.ServiceManager {
+		return "ServiceManager"
+	}
+	return "RewardsCoordinator"
+}
+
+func getRewardsSubmissionParams(
+	opts *bind.CallOpts,
+	reader rewardsSubmissionParamsReader,
+) (rewardsSubmissionParams, error) {
+	var params rewardsSubmissionParams
+	var err error
+	if params.calculationInterval, err = reader.CALCULATIONINTERVALSECONDS(opts); err != nil {
+		return params, eigenSdkUtils.WrapError("failed to get calculation interval", err)
+	}
+	if params.maxRewardsDuration, err = reader.MAXREWARDSDURATION(opts); err != nil {
+		return params, eigenSdkUtils.WrapError("failed to get max rewards duration", err)
+	}
+	if params.maxRetroactiveLength, err = reader.MAXRETROACTIVELENGTH(opts); err != nil {
+		return params, eigenSdkUtils.WrapError("failed to get max retroactive length", err)
+	}
+	if params.maxFutureLength, err = reader.MAXFUTURELENGTH(opts); err != nil {
+		return params, eigenSdkUtils.WrapError("failed to get max future length", err)
+	}
+	if params.genesisRewardsTimestamp, err = reader.GENESISREWARDSTIMESTAMP(opts); err != nil {
+		return params, eigenSdkUtils.WrapError("failed to get genesis rewards timestamp", err)
+	}
+	return params, nil
+}
+
+// validateRewardsSubmission checks the submission against the constraints of the RewardsCoordinator as of
+// the block timestamp now
+func validateRewardsSubmission(
+	submission *rewardsSubmission,
+	submissionType string,
+	params rewardsSubmissionParams,
+	now uint64,
+) error {
+	if submission.amount.Cmp(maxRewardsAmount) > 0 {
+		return fmt.Errorf("amount %s is above the maximum of %s", submission.amount, maxRewardsAmount)
+	}
+	if submission.duration == 0 {
+		return errors.New("duration must be greater than 0")
+	}
+	if submission.duration > params.maxRewardsDuration {
+		return fmt.Errorf(
+			"duration %s is above the maximum of %s",
+			formatSubmissionDuration(submission.duration),
+			formatSubmissionDuration(params.maxRewardsDuration),
+		)
+	}
+	if submission.duration%params.calculationInterval != 0 {
+		return fmt.Errorf(
+			"duration %s must be a multiple of the calculation interval of %s",
+			formatSubmissionDuration(submission.duration),
+			formatSubmissionDuration(params.calculationInterval),
+		)
+	}
+	if submission.startTimestamp%params.calculationInterval != 0 {
+		aligned := submission.startTimestamp - submission.startTimestamp%params.calculationInterval
+		return fmt.Errorf(
+			"start %s must be a multiple of the calculation interval of %s, such as %s",
+			formatSubmissionTimestamp(submission.startTimestamp),
+			formatSubmissionDuration(params.calculationInterval),
+			formatSubmissionTimestamp(aligned),
+		)
+	}
+
+	start := uint64(submission.startTimestamp)
+	if start < uint64(params.genesisRewardsTimestamp) {
+		return fmt.Errorf(
+			"start %s is before the genesis rewards timestamp %s",
+			formatSubmissionTimestamp(submission.startTimestamp),
+			formatSubmissionTimestamp(params.genesisRewardsTimestamp),
+		)
+	}
+	if start+uint64(params.maxRetroactiveLength) < now {
+		return fmt.Errorf(
+			"start %s is more than %s in the past",
+			formatSubmissionTimestamp(submission.startTimestamp),
+			formatSubmissionDuration(params.maxRetroactiveLength),
+		)
+	}
+	if submissionType == avsRewardsSubmissionType && start > now+uint64(params.maxFutureLength) {
+		return fmt.Errorf(
+			"start %s is more than %s in the future",
+			formatSubmissionTimestamp(submission.startTimestamp),
+			formatSubmissionDuration(params.maxFutureLength),
+		)
+	}
+	if submissionType == operatorDirectedSubmissionType && start+uint64(submission.duration) >= now {
+		return fmt.Errorf(
+			"operator-directed submissions must be retroactive, but the submission ends at %s",
+			formatSubmissionTimestamp(uint32(start+uint64(submission.duration))),
+		)
+	}
+	return nil
+}
+
+// getSubmissionTotals returns the total amount of each token transferred by the submissions
+func getSubmissionTotals(submissions []*rewardsSubmission) map[gethcommon.Address]*big.Int {
+	totals := make(map[gethcommon.Address]*big.Int)
+	for _, submission := range submissions {
+		if _, ok := totals[submission.token]; !ok {
+			totals[submission.token] = new(big.Int)
+		}
+		totals[submission.token].Add(totals[submission.token], submission.amount)
+	}
+	return totals
+}
+
+// getTokenAllowances reads the balance of the owner and its allowance to the spender for every token
+func getTokenAllowances(
+	opts *bind.CallOpts,
+	backend bind.ContractBackend,
+	owner gethcommon.Address,
+	spender gethcommon.Address,
+	totals map[gethcommon.Address]*big.Int,
+) ([]tokenAllowance, error) {
+	allowances := make([]tokenAllowance, 0, len(totals))
+	for token, required := range totals {
+		tokenClient, err := erc20.NewERC20(token, backend)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to create ERC20 binding", err)
+		}
+		allowance, err := tokenClient.Allowance(opts, owner, spender)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError(fmt.Sprintf("failed to get allowance of token %s", token.Hex()), err)
+		}
+		balance, err := tokenClient.BalanceOf(opts, owner)
+		if err != nil {
+			return nil, eigenSdkUtils.WrapError(fmt.Sprintf("failed to get balance of token %s", token.Hex()), err)
+		}
+		symbol, decimals := erc20.GetTokenSymbolAndDecimals(token, backend)
+		allowances = append(allowances, tokenAllowance{
+			token:     token,
+			symbol:    symbol,
+			decimals:  decimals,
+			required:  required,
+			allowance: allowance,
+			balance:   balance,
+		})
+	}
+	sort.Slice(allowances, func(i, j int) bool {
+		return bytes.Compare(allowances[i].token.Bytes(), allowances[j].token.Bytes()) < 0
+	})
+	return allowances, nil
+}
+
+// getRequiredApprovals returns the tokens whose allowance is below the amount to submit
+func getRequiredApprovals(allowances []tokenAllowance) []tokenAllowance {
+	approvals := make([]tokenAllowance, 0)
+	for _, allowance := range allowances {
+		if allowance.allowance.Cmp(allowance.required) < 0 {
+			approvals = append(approvals, allowance)
+		}
+	}
+	return approvals
+}
+
+func toAVSRewardsSubmissions(
+	submissions []*rewardsSubmission,
+) []rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission {
+	out := make([]rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission, 0, len(submissions))
+	for _, submission := range submissions {
+		out = append(out, rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission{
+			StrategiesAndMultipliers: submission.strategies,
+			Token:                    submission.token,
+			Amount:                   submission.amount,
+			StartTimestamp:           submission.startTimestamp,
+			Duration:                 submission.duration,
+		})
+	}
+	return out
+}
+
+// toServiceManagerRewardsSubmissions converts the submissions to the identical types of the ServiceManager
+// binding
+func toServiceManagerRewardsSubmissions(
+	submissions []rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission,
+) []servicemanager.IRewardsCoordinatorTypesRewardsSubmission {
+	out := make([]servicemanager.IRewardsCoordinatorTypesRewardsSubmission, 0, len(submissions))
+	for _, submission := range submissions {
+		strategies := make(
+			[]servicemanager.IRewardsCoordinatorTypesStrategyAndMultiplier,
+			0,
+			len(submission.StrategiesAndMultipliers),
+		)
+		for _, strategy := range submission.StrategiesAndMultipliers {
+			strategies = append(strategies, servicemanager.IRewardsCoordinatorTypesStrategyAndMultiplier(strategy))
+		}
+		out = append(out, servicemanager.IRewardsCoordinatorTypesRewardsSubmission{
+			StrategiesAndMultipliers: strategies,
+			Token:                    submission.Token,
+			Amount:                   submission.Amount,
+			StartTimestamp:           submission.StartTimestamp,
+			Duration:                 submission.Duration,
+		})
+	}
+	return out
+}
+
+func toOperatorDirectedRewardsSubmissions(
+	submissions []*rewardsSubmission,
+) []rewardscoordinator.IRewardsCoordinatorTypesOperatorDirectedRewardsSubmission {
+	out := make([]rewardscoordinator.IRewardsCoordinatorTypesOperatorDirectedRewardsSubmission, 0, len(submissions))
+	for _, submission := range submissions {
+		out = append(out, rewardscoordinator.IRewardsCoordinatorTypesOperatorDirectedRewardsSubmission{
+			StrategiesAndMultipliers: submission.strategies,
+			Token:                    submission.token,
+			OperatorRewards:          submission.operatorRewards,
+			StartTimestamp:           submission.startTimestamp,
+			Duration:                 submission.duration,
+			Description:              submission.description,
+		})
+	}
+	return out
+}
+
+func printRewardsSubmissions(config *SubmitRewardsConfig, allowances []tokenAllowance) {
+	tokens := make(map[gethcommon.Address]tokenAllowance, len(allowances))
+	for _, allowance := range allowances {
+		tokens[allowance.token] = allowance
+	}
+
+	fmt.Println()
+	fmt.Printf("%s rewards submissions for AVS %s\n", config.SubmissionType, config.AVSAddress.Hex())
+	for i, submission := range config.Submissions {
+		token := tokens[submission.token]
+		fmt.Printf(
+			"  %d. %s %s from %s for %s, %d strategies",
+			i+1,
+			erc20.FormatAmount(submission.amount, token.decimals),
+			token.symbol,
+			formatSubmissionTimestamp(submission.startTimestamp),
+			formatSubmissionDuration(submission.duration),
+			len(submission.strategies),
+		)
+		if config.SubmissionType == operatorDirectedSubmissionType {
+			fmt.Printf(", %d operators", len(submission.operatorRewards))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	fmt.Printf("Allowances of %s for the %s\n", config.CallerAddress.Hex(), getSubmissionSpenderName(config))
+	for _, allowance := range allowances {
+		status := utils.EmojiCheckMark
+		if allowance.allowance.Cmp(allowance.required) < 0 {
+			status = utils.EmojiWarning
+		}
+		fmt.Printf(
+			"  %s %s: required %s, allowance %s, balance %s\n",
+			status,
+			allowance.symbol,
+			erc20.FormatAmount(allowance.required, allowance.decimals),
+			erc20.FormatAmount(allowance.allowance, allowance.decimals),
+			erc20.FormatAmount(allowance.balance, allowance.decimals),
+		)
+	}
+	fmt.Println()
+}
+
+func formatSubmissionTimestamp(timestamp uint32) string {
+	return time.Unix(int64(timestamp), 0).UTC().Format(time.DateTime) + " UTC"
+}
+
+func formatSubmissionDuration(seconds uint32) string {
+	if seconds%secondsPerDay == 0 {
+		return fmt.Sprintf("%dd", seconds/secondsPerDay)
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// readRewardsSubmissionFile reads the submissions of a YAML file, or of a CSV file if the file has a .csv
+// extension
+func readRewardsSubmissionFile(filePath string) ([]rewardsSubmissionEntry, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".csv") {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		var rows []rewardsSubmissionCsvRow
+		if err := gocsv.UnmarshalFile(file, &rows); err != nil {
+			return nil, eigenSdkUtils.WrapError("failed to parse CSV submission file", err)
+		}
+		return csvRowsToRewardsSubmissionEntries(rows)
+	}
+
+	yamlFile, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to read YAML submission file", err)
+	}
+	var entries []rewardsSubmissionEntry
+	if err := yaml.UnmarshalStrict(yamlFile, &entries); err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to parse YAML submission file", err)
+	}
+	return entries, nil
+}
+
+// csvRowsToRewardsSubmissionEntries merges the rows of each submission id, in the order of the file
+func csvRowsToRewardsSubmissionEntries(rows []rewardsSubmissionCsvRow) ([]rewardsSubmissionEntry, error) {
+	entries := make([]rewardsSubmissionEntry, 0)
+	indices := make(map[string]int)
+	for i, row := range rows {
+		line := i + 2 // account for the header row
+		id := strings.TrimSpace(row.Submission)
+		index, ok := indices[id]
+		if !ok {
+			index = len(entries)
+			indices[id] = index
+			entries = append(entries, rewardsSubmissionEntry{})
+		}
+		entry := &entries[index]
+
+		fields := []struct {
+			name  string
+			value string
+			field *string
+		}{
+			{"token", row.Token, &entry.Token},
+			{"amount", row.Amount, &entry.Amount},
+			{"start_timestamp", row.StartTimestamp, &entry.StartTimestamp},
+			{"duration", row.Duration, &entry.Duration},
+			{"description", row.Description, &entry.Description},
+		}
+		for _, f := range fields {
+			value := strings.TrimSpace(f.value)
+			if value == "" {
+				continue
+			}
+			if *f.field != "" && *f.field != value {
+				return nil, fmt.Errorf("line %d: %s '%s' differs from '%s' in submission '%s'", line, f.name, value, *f.field, id)
+			}
+			*f.field = value
+		}
+
+		strategy := strings.TrimSpace(row.Strategy)
+		multiplier := strings.TrimSpace(row.Multiplier)
+		if strategy != "" || multiplier != "" {
+			entry.Strategies = append(entry.Strategies, strategyMultiplierEntry{Strategy: strategy, Multiplier: multiplier})
+		}
+		operator := strings.TrimSpace(row.Operator)
+		operatorAmount := strings.TrimSpace(row.OperatorAmount)
+		if operator != "" || operatorAmount != "" {
+			entry.OperatorRewards = append(
+				entry.OperatorRewards,
+				operatorRewardEntry{Operator: operator, Amount: operatorAmount},
+			)
+		}
+	}
+	return entries, nil
+}
+
+// parseRewardsSubmissionEntry validates the fields of an entry. Strategies and operators are sorted in
+// ascending order, as the RewardsCoordinator requires.
+func parseRewardsSubmissionEntry(entry rewardsSubmissionEntry, submissionType string) (*rewardsSubmission, error) {
+	token, err := parseNonZeroAddress("token", entry.Token)
+	if err != nil {
+		return nil, err
+	}
+	submission := &rewardsSubmission{token: token, description: entry.Description}
+
+	if len(entry.Strategies) == 0 {
+		return nil, errors.New("at least one strategy is required")
+	}
+	seenStrategies := make(map[gethcommon.Address]bool)
+	for _, strategyEntry := range entry.Strategies {
+		strategy, err := parseNonZeroAddress("strategy", strategyEntry.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		if seenStrategies[strategy] {
+			return nil, fmt.Errorf("duplicate strategy %s", strategy.Hex())
+		}
+		seenStrategies[strategy] = true
+		multiplier, err := parsePositiveAmount("multiplier", strategyEntry.Multiplier)
+		if err != nil {
+			return nil, err
+		}
+		if multiplier.Cmp(maxMultiplier) > 0 {
+			return nil, fmt.Errorf("multiplier %s of strategy %s doesn't fit in an uint96", multiplier, strategy.Hex())
+		}
+		submission.strategies = append(
+			submission.strategies,
+			rewardscoordinator.IRewardsCoordinatorTypesStrategyAndMultiplier{Strategy: strategy, Multiplier: multiplier},
+		)
+	}
+	sort.Slice(submission.strategies, func(i, j int) bool {
+		return bytes.Compare(submission.strategies[i].Strategy.Bytes(), submission.strategies[j].Strategy.Bytes()) < 0
+	})
+
+	if submissionType == avsRewardsSubmissionType {
+		if len(entry.OperatorRewards) > 0 {
+			return nil, errors.New("operator_rewards are only supported by operator-directed submissions")
+		}
+		if entry.Description != "" {
+			return nil, errors.New("description is only supported by operator-directed submissions")
+		}
+		if submission.amount, err = parsePositiveAmount("amount", entry.Amount); err != nil {
+			return nil, err
+		}
+	} else {
+		if entry.Amount != "" {
+			return nil, errors.New("amount is the sum of the operator_rewards of operator-directed submissions")
+		}
+		if len(entry.OperatorRewards) == 0 {
+			return nil, errors.New("at least one operator reward is required")
+		}
+		submission.amount = new(big.Int)
+		seenOperators := make(map[gethcommon.Address]bool)
+		for _, operatorReward := range entry.OperatorRewards {
+			operator, err := parseNonZeroAddress("operator", operatorReward.Operator)
+			if err != nil {
+				return nil, err
+			}
+			if seenOperators[operator] {
+				return nil, fmt.Errorf("duplicate operator %s", operator.Hex())
+			}
+			seenOperators[operator] = true
+			amount, err := parsePositiveAmount("operator amount", operatorReward.Amount)
+			if err != nil {
+				return nil, err
+			}
+			submission.amount.Add(submission.amount, amount)
+			submission.operatorRewards = append(
+				submission.operatorRewards,
+				rewardscoordinator.IRewardsCoordinatorTypesOperatorReward{Operator: operator, Amount: amount},
+			)
+		}
+		sort.Slice(submission.operatorRewards, func(i, j int) bool {
+			return bytes.Compare(
+				submission.operatorRewards[i].Operator.Bytes(),
+				submission.operatorRewards[j].Operator.Bytes(),
+			) < 0
+		})
+	}
+
+	if submission.startTimestamp, err = parseSubmissionTimestamp(entry.StartTimestamp); err != nil {
+		return nil, err
+	}
+	if submission.duration, err = parseSubmissionDuration(entry.Duration); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+func parseNonZeroAddress(name string, value string) (gethcommon.Address, error) {
+	value = strings.TrimSpace(value)
+	if !gethcommon.IsHexAddress(value) {
+		return utils.ZeroAddress, fmt.Errorf("invalid %s address '%s'", name, value)
+	}
+	address := gethcommon.HexToAddress(value)
+	if address == utils.ZeroAddress {
+		return utils.ZeroAddress, fmt.Errorf("%s address can't be the zero address", name)
+	}
+	return address, nil
+}
+
+func parsePositiveAmount(name string, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(strings.TrimSpace(value), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s '%s'", name, value)
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%s must be greater than 0", name)
+	}
+	return amount, nil
+}
+
+// parseSubmissionTimestamp parses a unix timestamp, a YYYY-MM-DD date or a RFC 3339 time
+func parseSubmissionTimestamp(value string) (uint32, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("start_timestamp is required")
+	}
+	if timestamp, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return uint32(timestamp), nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if t.Unix() < 0 || t.Unix() > math.MaxUint32 {
+				return 0, fmt.Errorf("start_timestamp '%s' is out of range", value)
+			}
+			return uint32(t.Unix()), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid start_timestamp '%s', use a unix timestamp or a YYYY-MM-DD date", value)
+}
+
+// parseSubmissionDuration parses a number of seconds, a number of days such as 7d or a Go duration such as
+// 168h
+func parseSubmissionDuration(value string) (uint32, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("duration is required")
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return uint32(seconds), nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err == nil && n*secondsPerDay <= math.MaxUint32 {
+			return uint32(n * secondsPerDay), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 && d%time.Second == 0 &&
+		d/time.Second <= math.MaxUint32 {
+		return uint32(d / time.Second), nil
+	}
+	return 0, fmt.Errorf("invalid duration '%s', use a number of seconds or a duration such as 7d or 168h", value)
+}
+
+func readAndValidateSubmitRewardsConfig(
+	cCtx *cli.Context,
+	logger logging.Logger,
+	submissionType string,
+) (*SubmitRewardsConfig, error) {
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	output := cCtx.String(flags.OutputFileFlag.Name)
+	outputType := cCtx.String(flags.OutputTypeFlag.Name)
+	broadcast := cCtx.Bool(flags.BroadcastFlag.Name)
+	isSilent := cCtx.Bool(flags.SilentFlag.Name)
+	safeBatchFile := cCtx.String(flags.SafeBatchFileFlag.Name)
+	approve := cCtx.Bool(ApproveFlag.Name)
+	serviceManager := submissionType == avsRewardsSubmissionType && cCtx.Bool(ServiceManagerFlag.Name)
+	file := cCtx.String(SubmissionFileFlag.Name)
+	chainID := utils.NetworkNameToChainId(network)
+
+	if outputType != utils.PrettyOutputType && outputType != utils.CallDataOutputType {
+		return nil, fmt.Errorf("unsupported output type for this command %s", outputType)
+	}
+
+	avsAddressString := cCtx.String(flags.AVSAddressFlag.Name)
+	if !gethcommon.IsHexAddress(avsAddressString) {
+		return nil, fmt.Errorf("invalid AVS address '%s'", avsAddressString)
+	}
+	avsAddress := gethcommon.HexToAddress(avsAddressString)
+	// Submissions through the service manager are sent by its rewards initiator, which is read from the chain
+	// when no caller is provided
+	callerAddress := utils.ZeroAddress
+	if !serviceManager || !common.IsEmptyString(cCtx.String(flags.CallerAddressFlag.Name)) {
+		callerAddress = common.PopulateCallerAddress(cCtx, logger, avsAddress, avsAddressString)
+	}
+	if submissionType == avsRewardsSubmissionType && !serviceManager && callerAddress != avsAddress {
+		return nil, fmt.Errorf(
+			"AVS rewards submissions must be sent by the AVS %s, not by %s. Use --service-manager to submit "+
+				"through the service manager of the AVS with its rewards initiator",
+			avsAddress.Hex(),
+			callerAddress.Hex(),
+		)
+	}
+
+	entries, err := readRewardsSubmissionFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("submission file has no submissions")
+	}
+	submissions := make([]*rewardsSubmission, 0, len(entries))
+	for i, entry := range entries {
+		submission, err := parseRewardsSubmissionEntry(entry, submissionType)
+		if err != nil {
+			return nil, fmt.Errorf("submission %d: %w", i+1, err)
+		}
+		submissions = append(submissions, submission)
+	}
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	// Tokens are transferred by the service manager, when the submission goes through it
+	spenderAddress := gethcommon.HexToAddress(rewardsCoordinatorAddress)
+	if serviceManager {
+		spenderAddress = avsAddress
+	}
+
+	// Get signerConfig
+	signerConfig, err := common.GetSignerConfig(cCtx, logger)
+	if err != nil {
+		// We don't want to throw error since people can still use it to generate the calldata
+		// without broadcasting it
+		logger.Debugf("Failed to get signer config: %s", err)
+	}
+
+	return &SubmitRewardsConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		ChainID:                   chainID,
+		SubmissionType:            submissionType,
+		File:                      file,
+		AVSAddress:                avsAddress,
+		CallerAddress:             callerAddress,
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+		ServiceManager:            serviceManager,
+		SpenderAddress:            spenderAddress,
+		Submissions:               submissions,
+		Approve:                   approve,
+		Broadcast:                 broadcast,
+		SignerConfig:              signerConfig,
+		Output:                    output,
+		OutputType:                outputType,
+		SafeBatchFile:             safeBatchFile,
+		IsSilent:                  isSilent,
+	}, nil
+}
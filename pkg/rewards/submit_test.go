@@ -0,0 +1,485 @@
+package rewards
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	servicemanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/ServiceManagerBase"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRewardsSubmissionParamsReader struct {
+	params rewardsSubmissionParams
+	err    error
+}
+
+func (f *fakeRewardsSubmissionParamsReader) CALCULATIONINTERVALSECONDS(opts *bind.CallOpts) (uint32, error) {
+	return f.params.calculationInterval, f.err
+}
+
+func (f *fakeRewardsSubmissionParamsReader) MAXREWARDSDURATION(opts *bind.CallOpts) (uint32, error) {
+	return f.params.maxRewardsDuration, nil
+}
+
+func (f *fakeRewardsSubmissionParamsReader) MAXRETROACTIVELENGTH(opts *bind.CallOpts) (uint32, error) {
+	return f.params.maxRetroactiveLength, nil
+}
+
+func (f *fakeRewardsSubmissionParamsReader) MAXFUTURELENGTH(opts *bind.CallOpts) (uint32, error) {
+	return f.params.maxFutureLength, nil
+}
+
+func (f *fakeRewardsSubmissionParamsReader) GENESISREWARDSTIMESTAMP(opts *bind.CallOpts) (uint32, error) {
+	return f.params.genesisRewardsTimestamp, nil
+}
+
+var testRewardsSubmissionParams = rewardsSubmissionParams{
+	calculationInterval:     secondsPerDay,
+	maxRewardsDuration:      70 * secondsPerDay,
+	maxRetroactiveLength:    90 * secondsPerDay,
+	maxFutureLength:         30 * secondsPerDay,
+	genesisRewardsTimestamp: 1710979200,
+}
+
+func TestGetRewardsSubmissionParams(t *testing.T) {
+	params, err := getRewardsSubmissionParams(
+		&bind.CallOpts{},
+		&fakeRewardsSubmissionParamsReader{params: testRewardsSubmissionParams},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, testRewardsSubmissionParams, params)
+
+	_, err = getRewardsSubmissionParams(&bind.CallOpts{}, &fakeRewardsSubmissionParamsReader{err: errors.New("boom")})
+	assert.ErrorContains(t, err, "failed to get calculation interval")
+}
+
+func TestParseSubmissionTimestamp(t *testing.T) {
+	timestamp, err := parseSubmissionTimestamp("1735689600")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1735689600), timestamp)
+
+	timestamp, err = parseSubmissionTimestamp("2025-01-01")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1735689600), timestamp)
+
+	timestamp, err = parseSubmissionTimestamp("2025-01-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1735689600), timestamp)
+
+	_, err = parseSubmissionTimestamp("")
+	assert.Error(t, err)
+	_, err = parseSubmissionTimestamp("01/01/2025")
+	assert.Error(t, err)
+}
+
+func TestParseSubmissionDuration(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  uint32
+		expectErr bool
+	}{
+		{value: "604800", expected: 604800},
+		{value: "7d", expected: 604800},
+		{value: "168h", expected: 604800},
+		{value: "", expectErr: true},
+		{value: "1.5s", expectErr: true},
+		{value: "-1h", expectErr: true},
+		{value: "a week", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			duration, err := parseSubmissionDuration(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, duration)
+		})
+	}
+}
+
+func TestParseRewardsSubmissionEntry(t *testing.T) {
+	token := "0xa000000000000000000000000000000000000001"
+	strategyA := "0x1000000000000000000000000000000000000001"
+	strategyB := "0x2000000000000000000000000000000000000002"
+	operatorA := "0x3000000000000000000000000000000000000003"
+	operatorB := "0x4000000000000000000000000000000000000004"
+	strategies := []strategyMultiplierEntry{
+		{Strategy: strategyB, Multiplier: "2000000000000000000"},
+		{Strategy: strategyA, Multiplier: "1000000000000000000"},
+	}
+
+	submission, err := parseRewardsSubmissionEntry(rewardsSubmissionEntry{
+		Token:          token,
+		Amount:         "1000",
+		StartTimestamp: "2025-01-01",
+		Duration:       "7d",
+		Strategies:     strategies,
+	}, avsRewardsSubmissionType)
+	assert.NoError(t, err)
+	assert.Equal(t, gethcommon.HexToAddress(token), submission.token)
+	assert.Equal(t, big.NewInt(1000), submission.amount)
+	assert.Equal(t, uint32(1735689600), submission.startTimestamp)
+	assert.Equal(t, uint32(604800), submission.duration)
+	assert.Equal(t, gethcommon.HexToAddress(strategyA), submission.strategies[0].Strategy)
+	assert.Equal(t, gethcommon.HexToAddress(strategyB), submission.strategies[1].Strategy)
+
+	submission, err = parseRewardsSubmissionEntry(rewardsSubmissionEntry{
+		Token:          token,
+		StartTimestamp: "1735689600",
+		Duration:       "86400",
+		Description:    "January rewards",
+		Strategies:     strategies,
+		OperatorRewards: []operatorRewardEntry{
+			{Operator: operatorB, Amount: "300"},
+			{Operator: operatorA, Amount: "200"},
+		},
+	}, operatorDirectedSubmissionType)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(500), submission.amount)
+	assert.Equal(t, "January rewards", submission.description)
+	assert.Equal(t, []rewardscoordinator.IRewardsCoordinatorTypesOperatorReward{
+		{Operator: gethcommon.HexToAddress(operatorA), Amount: big.NewInt(200)},
+		{Operator: gethcommon.HexToAddress(operatorB), Amount: big.NewInt(300)},
+	}, submission.operatorRewards)
+
+	invalid := []struct {
+		name           string
+		entry          rewardsSubmissionEntry
+		submissionType string
+		expectedErr    string
+	}{
+		{
+			name:           "zero token",
+			entry:          rewardsSubmissionEntry{Token: "0x0000000000000000000000000000000000000000"},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "zero address",
+		},
+		{
+			name:           "no strategies",
+			entry:          rewardsSubmissionEntry{Token: token},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "at least one strategy",
+		},
+		{
+			name: "duplicate strategy",
+			entry: rewardsSubmissionEntry{Token: token, Strategies: []strategyMultiplierEntry{
+				{Strategy: strategyA, Multiplier: "1"},
+				{Strategy: strategyA, Multiplier: "2"},
+			}},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "duplicate strategy",
+		},
+		{
+			name: "multiplier above uint96",
+			entry: rewardsSubmissionEntry{Token: token, Strategies: []strategyMultiplierEntry{
+				{Strategy: strategyA, Multiplier: "79228162514264337593543950336"},
+			}},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "uint96",
+		},
+		{
+			name:           "zero amount",
+			entry:          rewardsSubmissionEntry{Token: token, Amount: "0", Strategies: strategies},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "amount must be greater than 0",
+		},
+		{
+			name: "operator rewards in avs rewards",
+			entry: rewardsSubmissionEntry{
+				Token:           token,
+				Amount:          "1",
+				Strategies:      strategies,
+				OperatorRewards: []operatorRewardEntry{{Operator: operatorA, Amount: "1"}},
+			},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "only supported by operator-directed",
+		},
+		{
+			name:           "amount in operator-directed",
+			entry:          rewardsSubmissionEntry{Token: token, Amount: "1", Strategies: strategies},
+			submissionType: operatorDirectedSubmissionType,
+			expectedErr:    "sum of the operator_rewards",
+		},
+		{
+			name: "duplicate operator",
+			entry: rewardsSubmissionEntry{
+				Token:      token,
+				Strategies: strategies,
+				OperatorRewards: []operatorRewardEntry{
+					{Operator: operatorA, Amount: "1"},
+					{Operator: operatorA, Amount: "2"},
+				},
+			},
+			submissionType: operatorDirectedSubmissionType,
+			expectedErr:    "duplicate operator",
+		},
+		{
+			name:           "missing start",
+			entry:          rewardsSubmissionEntry{Token: token, Amount: "1", Strategies: strategies},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "start_timestamp is required",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRewardsSubmissionEntry(tt.entry, tt.submissionType)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidateRewardsSubmission(t *testing.T) {
+	now := uint64(1735689600 + 12*60*60)
+	day := uint32(secondsPerDay)
+	submission := func(start uint32, duration uint32) *rewardsSubmission {
+		return &rewardsSubmission{amount: big.NewInt(1), startTimestamp: start, duration: duration}
+	}
+
+	tests := []struct {
+		name           string
+		submission     *rewardsSubmission
+		submissionType string
+		expectedErr    string
+	}{
+		{
+			name:           "valid avs rewards",
+			submission:     submission(1735689600, 7*day),
+			submissionType: avsRewardsSubmissionType,
+		},
+		{
+			name:           "valid operator-directed",
+			submission:     submission(1735689600-7*day, 7*day),
+			submissionType: operatorDirectedSubmissionType,
+		},
+		{
+			name:           "amount above maximum",
+			submission:     &rewardsSubmission{amount: new(big.Int).Add(maxRewardsAmount, big.NewInt(1))},
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "above the maximum",
+		},
+		{
+			name:           "zero duration",
+			submission:     submission(1735689600, 0),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "duration must be greater than 0",
+		},
+		{
+			name:           "duration above maximum",
+			submission:     submission(1735689600, 71*day),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "duration 71d is above the maximum of 70d",
+		},
+		{
+			name:           "duration not aligned",
+			submission:     submission(1735689600, day+3600),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "multiple of the calculation interval",
+		},
+		{
+			name:           "start not aligned",
+			submission:     submission(1735689600+3600, day),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "such as 2025-01-01 00:00:00 UTC",
+		},
+		{
+			name:           "start before genesis",
+			submission:     submission(1710979200-day, day),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "before the genesis rewards timestamp",
+		},
+		{
+			name:           "start too far in the past",
+			submission:     submission(1735689600-91*day, day),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "in the past",
+		},
+		{
+			name:           "start too far in the future",
+			submission:     submission(1735689600+31*day, day),
+			submissionType: avsRewardsSubmissionType,
+			expectedErr:    "in the future",
+		},
+		{
+			name:           "operator-directed not retroactive",
+			submission:     submission(1735689600, day),
+			submissionType: operatorDirectedSubmissionType,
+			expectedErr:    "must be retroactive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRewardsSubmission(tt.submission, tt.submissionType, testRewardsSubmissionParams, now)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestReadRewardsSubmissionFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "submissions.yaml")
+	err := os.WriteFile(yamlFile, []byte(`
+- token: "0xa000000000000000000000000000000000000001"
+  start_timestamp: 1735689600
+  duration: 7d
+  description: January rewards
+  strategies:
+    - strategy: "0x1000000000000000000000000000000000000001"
+      multiplier: 1000000000000000000
+  operator_rewards:
+    - operator: "0x3000000000000000000000000000000000000003"
+      amount: "100"
+`), 0o600)
+	assert.NoError(t, err)
+
+	entries, err := readRewardsSubmissionFile(yamlFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []rewardsSubmissionEntry{{
+		Token:          "0xa000000000000000000000000000000000000001",
+		StartTimestamp: "1735689600",
+		Duration:       "7d",
+		Description:    "January rewards",
+		Strategies: []strategyMultiplierEntry{
+			{Strategy: "0x1000000000000000000000000000000000000001", Multiplier: "1000000000000000000"},
+		},
+		OperatorRewards: []operatorRewardEntry{
+			{Operator: "0x3000000000000000000000000000000000000003", Amount: "100"},
+		},
+	}}, entries)
+
+	csvFile := filepath.Join(dir, "submissions.csv")
+	err = os.WriteFile(csvFile, []byte(
+		"submission,token,start_timestamp,duration,description,strategy,multiplier,operator,operator_amount\n"+
+			"1,0xa000000000000000000000000000000000000001,1735689600,7d,January,"+
+			"0x1000000000000000000000000000000000000001,1,0x3000000000000000000000000000000000000003,100\n"+
+			"1,,,,,0x2000000000000000000000000000000000000002,2,,\n"+
+			"2,0xa000000000000000000000000000000000000002,1735689600,1d,,"+
+			"0x1000000000000000000000000000000000000001,1,0x3000000000000000000000000000000000000003,5\n",
+	), 0o600)
+	assert.NoError(t, err)
+
+	entries, err = readRewardsSubmissionFile(csvFile)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "0xa000000000000000000000000000000000000001", entries[0].Token)
+	assert.Equal(t, "January", entries[0].Description)
+	assert.Len(t, entries[0].Strategies, 2)
+	assert.Len(t, entries[0].OperatorRewards, 1)
+	assert.Equal(t, "1d", entries[1].Duration)
+	assert.Len(t, entries[1].Strategies, 1)
+
+	err = os.WriteFile(csvFile, []byte(
+		"submission,token,amount,start_timestamp,duration,strategy,multiplier\n"+
+			"1,0xa000000000000000000000000000000000000001,100,1735689600,7d,0x1,1\n"+
+			"1,0xa000000000000000000000000000000000000001,200,,,0x2,1\n",
+	), 0o600)
+	assert.NoError(t, err)
+	_, err = readRewardsSubmissionFile(csvFile)
+	assert.ErrorContains(t, err, "line 3: amount '200' differs from '100'")
+}
+
+func TestGetSubmissionTotals(t *testing.T) {
+	tokenA := gethcommon.HexToAddress("0xa")
+	tokenB := gethcommon.HexToAddress("0xb")
+	totals := getSubmissionTotals([]*rewardsSubmission{
+		{token: tokenA, amount: big.NewInt(100)},
+		{token: tokenB, amount: big.NewInt(5)},
+		{token: tokenA, amount: big.NewInt(50)},
+	})
+	assert.Equal(t, map[gethcommon.Address]*big.Int{tokenA: big.NewInt(150), tokenB: big.NewInt(5)}, totals)
+
+	approvals := getRequiredApprovals([]tokenAllowance{
+		{token: tokenA, required: big.NewInt(150), allowance: big.NewInt(150)},
+		{token: tokenB, required: big.NewInt(5), allowance: big.NewInt(4)},
+	})
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, tokenB, approvals[0].token)
+}
+
+func TestGetRewardsSubmissionGasLimit(t *testing.T) {
+	strategies := make([]rewardscoordinator.IRewardsCoordinatorTypesStrategyAndMultiplier, 3)
+	operatorRewards := make([]rewardscoordinator.IRewardsCoordinatorTypesOperatorReward, 10)
+	submissions := []*rewardsSubmission{
+		{strategies: strategies[:1]},
+		{strategies: strategies, operatorRewards: operatorRewards},
+	}
+
+	assert.Equal(
+		t,
+		uint64(2*rewardsSubmissionGasLimit+4*strategyMultiplierGasLimit+10*operatorRewardGasLimit),
+		getRewardsSubmissionGasLimit(submissions, false),
+	)
+	assert.Equal(
+		t,
+		getRewardsSubmissionGasLimit(submissions, false)+2*serviceManagerSubmissionGasLimit,
+		getRewardsSubmissionGasLimit(submissions, true),
+	)
+}
+
+type fakeRewardsInitiatorReader struct {
+	rewardsInitiator gethcommon.Address
+}
+
+func (f *fakeRewardsInitiatorReader) RewardsInitiator(opts *bind.CallOpts) (gethcommon.Address, error) {
+	return f.rewardsInitiator, nil
+}
+
+func TestResolveRewardsInitiatorCaller(t *testing.T) {
+	rewardsInitiator := gethcommon.HexToAddress("0x1")
+	reader := &fakeRewardsInitiatorReader{rewardsInitiator: rewardsInitiator}
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+
+	config := &SubmitRewardsConfig{}
+	assert.NoError(t, resolveRewardsInitiatorCaller(&bind.CallOpts{}, reader, config, logger))
+	assert.Equal(t, rewardsInitiator, config.CallerAddress)
+
+	config = &SubmitRewardsConfig{CallerAddress: rewardsInitiator}
+	assert.NoError(t, resolveRewardsInitiatorCaller(&bind.CallOpts{}, reader, config, logger))
+
+	config = &SubmitRewardsConfig{CallerAddress: gethcommon.HexToAddress("0x2")}
+	assert.ErrorContains(
+		t,
+		resolveRewardsInitiatorCaller(&bind.CallOpts{}, reader, config, logger),
+		"must be sent by its rewards initiator",
+	)
+}
+
+func TestToServiceManagerRewardsSubmissions(t *testing.T) {
+	submission := rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission{
+		StrategiesAndMultipliers: []rewardscoordinator.IRewardsCoordinatorTypesStrategyAndMultiplier{
+			{Strategy: gethcommon.HexToAddress("0x1"), Multiplier: big.NewInt(2)},
+		},
+		Token:          gethcommon.HexToAddress("0x3"),
+		Amount:         big.NewInt(100),
+		StartTimestamp: 1710979200,
+		Duration:       secondsPerDay,
+	}
+
+	assert.Equal(t, []servicemanager.IRewardsCoordinatorTypesRewardsSubmission{{
+		StrategiesAndMultipliers: []servicemanager.IRewardsCoordinatorTypesStrategyAndMultiplier{
+			{Strategy: gethcommon.HexToAddress("0x1"), Multiplier: big.NewInt(2)},
+		},
+		Token:          gethcommon.HexToAddress("0x3"),
+		Amount:         big.NewInt(100),
+		StartTimestamp: 1710979200,
+		Duration:       secondsPerDay,
+	}}, toServiceManagerRewardsSubmissions(
+		[]rewardscoordinator.IRewardsCoordinatorTypesRewardsSubmission{submission},
+	))
+}
@@ -0,0 +1,176 @@
+package rewards
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+)
+
+// tokenPolicyFile is the token policy file. Tokens in deny are never claimed and, if allow is not empty, only
+// the tokens in allow are claimed.
+type tokenPolicyFile struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// tokenPolicy decides which tokens of the distribution are claimed. Any AVS can pay rewards in any token, so
+// the policy keeps spam and unknown tokens out of claims. A nil policy allows every token.
+type tokenPolicy struct {
+	allow map[gethcommon.Address]bool
+	deny  map[gethcommon.Address]bool
+}
+
+// recipientProof is the part of a claim proof whose tokens go to the recipient
+type recipientProof struct {
+	recipient gethcommon.Address
+	proof     *rewardsV1.Proof
+}
+
+// readTokenPolicyFile reads the token policy of a YAML file. It returns nil if no file is given.
+func readTokenPolicyFile(filePath string) (*tokenPolicy, error) {
+	if common.IsEmptyString(filePath) {
+		return nil, nil
+	}
+	yamlFile, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to read token policy file", err)
+	}
+	var file tokenPolicyFile
+	if err := yaml.UnmarshalStrict(yamlFile, &file); err != nil {
+		return nil, eigenSdkUtils.WrapError("failed to parse token policy file", err)
+	}
+
+	policy := &tokenPolicy{
+		allow: make(map[gethcommon.Address]bool, len(file.Allow)),
+		deny:  make(map[gethcommon.Address]bool, len(file.Deny)),
+	}
+	for _, token := range file.Allow {
+		if !gethcommon.IsHexAddress(token) {
+			return nil, fmt.Errorf("invalid allowed token address %q", token)
+		}
+		policy.allow[gethcommon.HexToAddress(token)] = true
+	}
+	for _, token := range file.Deny {
+		if !gethcommon.IsHexAddress(token) {
+			return nil, fmt.Errorf("invalid denied token address %q", token)
+		}
+		address := gethcommon.HexToAddress(token)
+		if policy.allow[address] {
+			return nil, fmt.Errorf("token %s is both allowed and denied", address.Hex())
+		}
+		policy.deny[address] = true
+	}
+	return policy, nil
+}
+
+// allows returns whether the token can be claimed
+func (p *tokenPolicy) allows(token gethcommon.Address) bool {
+	if p == nil {
+		return true
+	}
+	if p.deny[token] {
+		return false
+	}
+	return len(p.allow) == 0 || p.allow[token]
+}
+
+// filterProof removes the token leaves the policy doesn't allow from the proof
+func (p *tokenPolicy) filterProof(proof *rewardsV1.Proof, logger logging.Logger) *rewardsV1.Proof {
+	if p == nil {
+		return proof
+	}
+	return filterTokenLeaves(proof, func(leaf *rewardsV1.TokenLeaf) bool {
+		token := gethcommon.HexToAddress(leaf.Token)
+		if p.allows(token) {
+			return true
+		}
+		logger.Infof("Skipping token %s of earner %s, which the token policy doesn't allow", token, proof.EarnerLeaf.Earner)
+		return false
+	})
+}
+
+// parseTokenRecipients parses a comma separated list of <token address>:<recipient address>
+func parseTokenRecipients(value string) (map[gethcommon.Address]gethcommon.Address, error) {
+	recipients := make(map[gethcommon.Address]gethcommon.Address)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		token, recipient, found := strings.Cut(item, ":")
+		token, recipient = strings.TrimSpace(token), strings.TrimSpace(recipient)
+		if !found || !gethcommon.IsHexAddress(token) || !gethcommon.IsHexAddress(recipient) {
+			return nil, fmt.Errorf("invalid token recipient %q, expected <token address>:<recipient address>", item)
+		}
+		tokenAddress := gethcommon.HexToAddress(token)
+		if _, ok := recipients[tokenAddress]; ok {
+			return nil, fmt.Errorf("duplicate recipient of token %s", tokenAddress.Hex())
+		}
+		recipientAddress := gethcommon.HexToAddress(recipient)
+		if recipientAddress == utils.ZeroAddress {
+			return nil, fmt.Errorf("recipient of token %s can't be the zero address", tokenAddress.Hex())
+		}
+		recipients[tokenAddress] = recipientAddress
+	}
+	return recipients, nil
+}
+
+// validateTokenRecipients checks that every token with a recipient can be claimed under the policy
+func validateTokenRecipients(
+	tokenRecipients map[gethcommon.Address]gethcommon.Address,
+	policy *tokenPolicy,
+) error {
+	for token := range tokenRecipients {
+		if !policy.allows(token) {
+			return fmt.Errorf("token %s has a recipient but the token policy doesn't allow it", token.Hex())
+		}
+	}
+	return nil
+}
+
+// splitProofByRecipient splits the token leaves of the proof into a proof per recipient, since a claim
+// transaction has a single recipient. Tokens without a recipient of their own go to the default recipient.
+// Proofs are ordered by the first token leaf of each recipient.
+func splitProofByRecipient(
+	proof *rewardsV1.Proof,
+	tokenRecipients map[gethcommon.Address]gethcommon.Address,
+	defaultRecipient gethcommon.Address,
+) []recipientProof {
+	recipientOf := func(leaf *rewardsV1.TokenLeaf) gethcommon.Address {
+		if recipient, ok := tokenRecipients[gethcommon.HexToAddress(leaf.Token)]; ok {
+			return recipient
+		}
+		return defaultRecipient
+	}
+
+	recipients := make([]gethcommon.Address, 0)
+	seen := make(map[gethcommon.Address]bool)
+	for _, leaf := range proof.TokenLeaves {
+		recipient := recipientOf(leaf)
+		if !seen[recipient] {
+			seen[recipient] = true
+			recipients = append(recipients, recipient)
+		}
+	}
+
+	proofs := make([]recipientProof, 0, len(recipients))
+	for _, recipient := range recipients {
+		proofs = append(proofs, recipientProof{
+			recipient: recipient,
+			proof: filterTokenLeaves(proof, func(leaf *rewardsV1.TokenLeaf) bool {
+				return recipientOf(leaf) == recipient
+			}),
+		})
+	}
+	return proofs
+}
@@ -0,0 +1,192 @@
+package rewards
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	rewardsV1 "github.com/Layr-Labs/protocol-apis/gen/protos/eigenlayer/sidecar/v1/rewards"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	policyTokenA = gethcommon.HexToAddress("0x000000000000000000000000000000000000000a")
+	policyTokenB = gethcommon.HexToAddress("0x000000000000000000000000000000000000000b")
+	policyTokenC = gethcommon.HexToAddress("0x000000000000000000000000000000000000000c")
+	treasury     = gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	operations   = gethcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func writeTokenPolicyFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "token-policy.yaml")
+	assert.NoError(t, os.WriteFile(filePath, []byte(content), 0o600))
+	return filePath
+}
+
+func newPolicyTestProof(tokens ...gethcommon.Address) *rewardsV1.Proof {
+	proof := &rewardsV1.Proof{
+		EarnerLeaf: &rewardsV1.EarnerLeaf{Earner: treasury.Hex()},
+	}
+	for i, token := range tokens {
+		proof.TokenIndices = append(proof.TokenIndices, uint32(i))
+		proof.TokenTreeProofs = append(proof.TokenTreeProofs, []byte{byte(i)})
+		proof.TokenLeaves = append(proof.TokenLeaves, &rewardsV1.TokenLeaf{Token: token.Hex(), CumulativeEarnings: "1"})
+	}
+	return proof
+}
+
+func getProofTokens(proof *rewardsV1.Proof) []gethcommon.Address {
+	tokens := make([]gethcommon.Address, 0, len(proof.TokenLeaves))
+	for _, leaf := range proof.TokenLeaves {
+		tokens = append(tokens, gethcommon.HexToAddress(leaf.Token))
+	}
+	return tokens
+}
+
+func TestReadTokenPolicyFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantPolicy   *tokenPolicy
+		wantErr      bool
+		noPolicyFile bool
+	}{
+		{
+			name:         "no policy file",
+			noPolicyFile: true,
+		},
+		{
+			name:    "allow and deny lists",
+			content: "allow:\n  - " + policyTokenA.Hex() + "\ndeny:\n  - " + policyTokenB.Hex() + "\n",
+			wantPolicy: &tokenPolicy{
+				allow: map[gethcommon.Address]bool{policyTokenA: true},
+				deny:  map[gethcommon.Address]bool{policyTokenB: true},
+			},
+		},
+		{
+			name:    "invalid token address",
+			content: "deny:\n  - not-an-address\n",
+			wantErr: true,
+		},
+		{
+			name:    "token allowed and denied",
+			content: "allow:\n  - " + policyTokenA.Hex() + "\ndeny:\n  - " + policyTokenA.Hex() + "\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			content: "block:\n  - " + policyTokenA.Hex() + "\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := ""
+			if !tt.noPolicyFile {
+				filePath = writeTokenPolicyFile(t, tt.content)
+			}
+			policy, err := readTokenPolicyFile(filePath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPolicy, policy)
+		})
+	}
+}
+
+func TestTokenPolicyAllows(t *testing.T) {
+	var noPolicy *tokenPolicy
+	assert.True(t, noPolicy.allows(policyTokenA))
+
+	denyOnly := &tokenPolicy{deny: map[gethcommon.Address]bool{policyTokenB: true}}
+	assert.True(t, denyOnly.allows(policyTokenA))
+	assert.False(t, denyOnly.allows(policyTokenB))
+
+	allowList := &tokenPolicy{
+		allow: map[gethcommon.Address]bool{policyTokenA: true, policyTokenB: true},
+		deny:  map[gethcommon.Address]bool{policyTokenB: true},
+	}
+	assert.True(t, allowList.allows(policyTokenA))
+	assert.False(t, allowList.allows(policyTokenB))
+	assert.False(t, allowList.allows(policyTokenC))
+}
+
+func TestTokenPolicyFilterProof(t *testing.T) {
+	logger := logging.NewJsonSLogger(os.Stdout, &logging.SLoggerOptions{})
+	proof := newPolicyTestProof(policyTokenA, policyTokenB, policyTokenC)
+
+	var noPolicy *tokenPolicy
+	assert.Equal(t, proof, noPolicy.filterProof(proof, logger))
+
+	policy := &tokenPolicy{deny: map[gethcommon.Address]bool{policyTokenB: true}}
+	filtered := policy.filterProof(proof, logger)
+	assert.Equal(t, []gethcommon.Address{policyTokenA, policyTokenC}, getProofTokens(filtered))
+	assert.Equal(t, []uint32{0, 2}, filtered.TokenIndices)
+	assert.Len(t, proof.TokenLeaves, 3)
+}
+
+func TestParseTokenRecipients(t *testing.T) {
+	recipients, err := parseTokenRecipients("")
+	assert.NoError(t, err)
+	assert.Empty(t, recipients)
+
+	recipients, err = parseTokenRecipients(
+		policyTokenA.Hex() + ":" + treasury.Hex() + ", " + policyTokenB.Hex() + ":" + operations.Hex(),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[gethcommon.Address]gethcommon.Address{
+		policyTokenA: treasury,
+		policyTokenB: operations,
+	}, recipients)
+
+	_, err = parseTokenRecipients(policyTokenA.Hex())
+	assert.Error(t, err)
+
+	_, err = parseTokenRecipients(
+		policyTokenA.Hex() + ":" + treasury.Hex() + "," + policyTokenA.Hex() + ":" + operations.Hex(),
+	)
+	assert.Error(t, err)
+
+	_, err = parseTokenRecipients(policyTokenA.Hex() + ":0x0000000000000000000000000000000000000000")
+	assert.Error(t, err)
+}
+
+func TestValidateTokenRecipients(t *testing.T) {
+	recipients := map[gethcommon.Address]gethcommon.Address{policyTokenA: treasury}
+	assert.NoError(t, validateTokenRecipients(recipients, nil))
+	assert.NoError(t, validateTokenRecipients(recipients, &tokenPolicy{
+		allow: map[gethcommon.Address]bool{policyTokenA: true},
+	}))
+	assert.Error(t, validateTokenRecipients(recipients, &tokenPolicy{
+		deny: map[gethcommon.Address]bool{policyTokenA: true},
+	}))
+}
+
+func TestSplitProofByRecipient(t *testing.T) {
+	earner := gethcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	proof := newPolicyTestProof(policyTokenA, policyTokenB, policyTokenC)
+
+	proofs := splitProofByRecipient(proof, nil, earner)
+	assert.Len(t, proofs, 1)
+	assert.Equal(t, earner, proofs[0].recipient)
+	assert.Equal(t, []gethcommon.Address{policyTokenA, policyTokenB, policyTokenC}, getProofTokens(proofs[0].proof))
+
+	proofs = splitProofByRecipient(proof, map[gethcommon.Address]gethcommon.Address{
+		policyTokenB: treasury,
+		policyTokenC: operations,
+	}, earner)
+	assert.Len(t, proofs, 3)
+	assert.Equal(t, earner, proofs[0].recipient)
+	assert.Equal(t, []gethcommon.Address{policyTokenA}, getProofTokens(proofs[0].proof))
+	assert.Equal(t, treasury, proofs[1].recipient)
+	assert.Equal(t, []gethcommon.Address{policyTokenB}, getProofTokens(proofs[1].proof))
+	assert.Equal(t, []uint32{1}, proofs[1].proof.TokenIndices)
+	assert.Equal(t, operations, proofs[2].recipient)
+	assert.Equal(t, []gethcommon.Address{policyTokenC}, getProofTokens(proofs[2].proof))
+}
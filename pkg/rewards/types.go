@@ -2,6 +2,7 @@ package rewards
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/Layr-Labs/eigenlayer-cli/pkg/types"
 	gethcommon "github.com/ethereum/go-ethereum/common"
@@ -15,6 +16,75 @@ type rewardsJson struct {
 
 type allRewardsJson []rewardsJson
 
+// rewardsBreakdownJson is the amount earned from an AVS, and its operator set if known, for a rewards
+// submission type on a snapshot date
+type rewardsBreakdownJson struct {
+	Snapshot        string  `json:"snapshot"`
+	AvsAddress      string  `json:"avsAddress"`
+	OperatorSetId   *uint32 `json:"operatorSetId,omitempty"`
+	RewardType      string  `json:"rewardType"`
+	TokenAddress    string  `json:"tokenAddress"`
+	TokenSymbol     string  `json:"tokenSymbol"`
+	Amount          string  `json:"amount"`
+	FormattedAmount string  `json:"formattedAmount"`
+}
+
+type rewardsBreakdownOutput struct {
+	EarnerAddress     string                 `json:"earnerAddress"`
+	StartDate         string                 `json:"startDate"`
+	EndDate           string                 `json:"endDate,omitempty"`
+	Rewards           []rewardsBreakdownJson `json:"rewards"`
+	AvsTotals         []rewardsBreakdownJson `json:"avsTotals"`
+	OperatorSetTotals []rewardsBreakdownJson `json:"operatorSetTotals"`
+}
+
+// rewardsExportRow is a row of the rewards export. Rows with record type 'reward' are the rewards earned
+// from an AVS on a snapshot date and rows with record type 'claim' are RewardsClaimed events.
+type rewardsExportRow struct {
+	RecordType       string `csv:"record_type" json:"recordType"`
+	Date             string `csv:"date" json:"date"`
+	EarnerAddress    string `csv:"earner_address" json:"earnerAddress"`
+	AvsAddress       string `csv:"avs_address" json:"avsAddress,omitempty"`
+	RewardType       string `csv:"reward_type" json:"rewardType,omitempty"`
+	TokenAddress     string `csv:"token_address" json:"tokenAddress"`
+	TokenSymbol      string `csv:"token_symbol" json:"tokenSymbol"`
+	Amount           string `csv:"amount" json:"amount"`
+	FormattedAmount  string `csv:"formatted_amount" json:"formattedAmount"`
+	FiatValue        string `csv:"fiat_value" json:"fiatValue,omitempty"`
+	RecipientAddress string `csv:"recipient_address" json:"recipientAddress,omitempty"`
+	TxHash           string `csv:"tx_hash" json:"txHash,omitempty"`
+	BlockNumber      string `csv:"block_number" json:"blockNumber,omitempty"`
+}
+
+// tokenPrice is a row of the price file of rewards export. A price without a date is used for all dates
+// without a price of their own.
+type tokenPrice struct {
+	Date         string `csv:"date"`
+	TokenAddress string `csv:"token_address"`
+	Price        string `csv:"price"`
+}
+
+// batchClaimProgress records the outcome of each earner of a batch claim for a distribution root, so that
+// claimed earners are not claimed again when the batch is resumed
+type batchClaimProgress struct {
+	RootIndex uint32                       `json:"rootIndex"`
+	Earners   map[string]*batchClaimStatus `json:"earners"`
+}
+
+type batchClaimStatus struct {
+	Status           string `json:"status"`
+	RecipientAddress string `json:"recipientAddress"`
+	TxHash           string `json:"txHash,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+type batchClaimReportRow struct {
+	EarnerAddress    string
+	RecipientAddress string
+	Status           string
+	Detail           string
+}
+
 type ClaimConfig struct {
 	Network                   string
 	RPCUrl                    string
@@ -25,6 +95,8 @@ type ClaimConfig struct {
 	OutputType                string
 	Broadcast                 bool
 	TokenAddresses            []gethcommon.Address
+	TokenRecipients           map[gethcommon.Address]gethcommon.Address
+	TokenPolicy               *tokenPolicy
 	RewardsCoordinatorAddress gethcommon.Address
 	ClaimTimestamp            string
 	ChainID                   *big.Int
@@ -32,9 +104,109 @@ type ClaimConfig struct {
 	SignerConfig              *types.SignerConfig
 	IsSilent                  bool
 	BatchClaimFile            string
+	BatchGasLimit             uint64
+	BatchProgressFile         string
+	DistributionFile          string
+	SidecarHttpRpcURL         string
+}
+
+// autoClaimStatus is the outcome of the last auto-claim check
+type autoClaimStatus struct {
+	UpdatedAt          string                 `json:"updatedAt"`
+	EarnerAddress      string                 `json:"earnerAddress"`
+	RootIndex          uint32                 `json:"rootIndex"`
+	Result             string                 `json:"result"`
+	GasPrice           string                 `json:"gasPrice,omitempty"`
+	Tokens             []autoClaimTokenStatus `json:"tokens,omitempty"`
+	TxHash             string                 `json:"txHash,omitempty"`
+	Error              string                 `json:"error,omitempty"`
+	LastClaimTxHash    string                 `json:"lastClaimTxHash,omitempty"`
+	LastClaimRootIndex uint32                 `json:"lastClaimRootIndex,omitempty"`
+	LastClaimAt        string                 `json:"lastClaimAt,omitempty"`
+}
+
+type autoClaimTokenStatus struct {
+	TokenAddress     string `json:"tokenAddress"`
+	ClaimableAmount  string `json:"claimableAmount"`
+	Threshold        string `json:"threshold,omitempty"`
+	Claimed          bool   `json:"claimed"`
+	RecipientAddress string `json:"recipientAddress,omitempty"`
+}
+
+type AutoClaimConfig struct {
+	Network                   string
+	RPCUrl                    string
+	EarnerAddress             gethcommon.Address
+	RecipientAddress          gethcommon.Address
+	ClaimerAddress            gethcommon.Address
+	TokenAddresses            []gethcommon.Address
+	TokenRecipients           map[gethcommon.Address]gethcommon.Address
+	TokenPolicy               *tokenPolicy
+	ClaimThresholds           map[gethcommon.Address]*big.Int
+	ClaimUnlistedTokens       bool
+	MaxGasPrice               *big.Int
+	PollInterval              time.Duration
+	Once                      bool
+	StatusFile                string
+	RewardsCoordinatorAddress gethcommon.Address
+	ChainID                   *big.Int
+	Environment               string
+	SignerConfig              *types.SignerConfig
 	SidecarHttpRpcURL         string
 }
 
+// claimerInfoJson is the claimer of an earner and the accounts which can change it
+type claimerInfoJson struct {
+	EarnerAddress        string   `json:"earnerAddress"`
+	ClaimerAddress       string   `json:"claimerAddress"`
+	ClaimerSet           bool     `json:"claimerSet"`
+	Admins               []string `json:"admins"`
+	SetClaimerAppointees []string `json:"setClaimerAppointees"`
+}
+
+type GetClaimerConfig struct {
+	Network                     string
+	RPCUrl                      string
+	EarnerAddress               gethcommon.Address
+	RewardsCoordinatorAddress   gethcommon.Address
+	PermissionControllerAddress gethcommon.Address
+	ChainID                     *big.Int
+	Environment                 string
+	Output                      string
+	OutputType                  string
+}
+
+// distributionRootJson is a distribution root posted to the rewards coordinator
+type distributionRootJson struct {
+	RootIndex             uint32 `json:"rootIndex"`
+	Root                  string `json:"root"`
+	RewardsCalculationEnd string `json:"rewardsCalculationEnd"`
+	ActivatedAt           string `json:"activatedAt"`
+	Disabled              bool   `json:"disabled"`
+	Status                string `json:"status"`
+	ActivatesIn           string `json:"activatesIn,omitempty"`
+}
+
+// distributionRootEvent is emitted when a distribution root is submitted, activated or disabled
+type distributionRootEvent struct {
+	Event     string `json:"event"`
+	Timestamp string `json:"timestamp"`
+	distributionRootJson
+}
+
+type RootsConfig struct {
+	Network                   string
+	RPCUrl                    string
+	RewardsCoordinatorAddress gethcommon.Address
+	ChainID                   *big.Int
+	Environment               string
+	Output                    string
+	OutputType                string
+	Limit                     uint64
+	PollInterval              time.Duration
+	WebhookURL                string
+}
+
 type SetClaimerConfig struct {
 	ClaimerAddress            gethcommon.Address
 	Network                   string
@@ -53,6 +225,8 @@ type ShowConfig struct {
 	EarnerAddress             gethcommon.Address
 	RPCUrl                    string
 	NumberOfDays              int64
+	StartDate                 string
+	EndDate                   string
 	Network                   string
 	Environment               string
 	ClaimType                 ClaimType
@@ -63,4 +237,50 @@ type ShowConfig struct {
 	ClaimTimestamp            string
 	RewardsCoordinatorAddress gethcommon.Address
 	SidecarHttpRpcURL         string
+	Breakdown                 bool
+	AvsAddresses              []gethcommon.Address
+}
+
+type SubmitRewardsConfig struct {
+	Network                   string
+	RPCUrl                    string
+	ChainID                   *big.Int
+	SubmissionType            string
+	File                      string
+	AVSAddress                gethcommon.Address
+	CallerAddress             gethcommon.Address
+	RewardsCoordinatorAddress gethcommon.Address
+	ServiceManager            bool
+	SpenderAddress            gethcommon.Address
+	Submissions               []*rewardsSubmission
+	Approve                   bool
+	Broadcast                 bool
+	SignerConfig              *types.SignerConfig
+	Output                    string
+	OutputType                string
+	SafeBatchFile             string
+	IsSilent                  bool
+}
+
+type ExportConfig struct {
+	Network                   string
+	RPCUrl                    string
+	ChainID                   *big.Int
+	Environment               string
+	EarnerAddresses           []gethcommon.Address
+	FromDate                  string
+	ToDate                    string
+	Format                    string
+	Output                    string
+	PriceFile                 string
+	RewardsCoordinatorAddress gethcommon.Address
+	SidecarHttpRpcURL         string
+}
+
+type VerifyProofConfig struct {
+	Network                   string
+	RPCUrl                    string
+	ChainID                   *big.Int
+	ProofFile                 string
+	RewardsCoordinatorAddress gethcommon.Address
 }
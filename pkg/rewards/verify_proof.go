@@ -0,0 +1,356 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/common/flags"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/telemetry"
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/utils"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	eigenSdkUtils "github.com/Layr-Labs/eigensdk-go/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/urfave/cli/v2"
+)
+
+type distributionRootReader interface {
+	GetDistributionRootAtIndex(
+		opts *bind.CallOpts,
+		index *big.Int,
+	) (rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot, error)
+}
+
+func VerifyProofCmd(p utils.Prompter) *cli.Command {
+	verifyProofCmd := &cli.Command{
+		Name:      "verify-proof",
+		Usage:     "Verify a claim proof against the distribution root posted on-chain",
+		UsageText: "verify-proof [flags] <proof.json>",
+		Description: `
+Command to verify claim proofs written by 'rewards claim --output-type json'
+
+The earner tree proof and every token tree proof are verified locally against the distribution root at
+the root index of the claim, which is read from the RewardsCoordinator. The root in the proof file must
+match the on-chain root and the root must not be disabled. The file can contain a single claim, an array
+of claims or multiple claims one after another.
+		`,
+		After: telemetry.AfterRunAction(),
+		Flags: getVerifyProofFlags(),
+		Action: func(cCtx *cli.Context) error {
+			return verifyProof(cCtx)
+		},
+	}
+
+	return verifyProofCmd
+}
+
+func getVerifyProofFlags() []cli.Flag {
+	baseFlags := []cli.Flag{
+		&flags.NetworkFlag,
+		&flags.ETHRpcUrlFlag,
+		&flags.VerboseFlag,
+		&RewardsCoordinatorAddressFlag,
+	}
+
+	sort.Sort(cli.FlagsByName(baseFlags))
+	return baseFlags
+}
+
+func verifyProof(cCtx *cli.Context) error {
+	ctx := cCtx.Context
+	logger := common.GetLogger(cCtx)
+
+	config, err := readAndValidateVerifyProofConfig(cCtx, logger)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read and validate verify proof config", err)
+	}
+	cCtx.App.Metadata["network"] = config.ChainID.String()
+
+	data, err := os.ReadFile(config.ProofFile)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to read proof file", err)
+	}
+	claims, err := parseSolidityClaims(data)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to parse proof file", err)
+	}
+
+	ethClient, err := ethclient.Dial(config.RPCUrl)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create new eth client", err)
+	}
+	rewardsCoordinator, err := rewardscoordinator.NewContractRewardsCoordinator(
+		config.RewardsCoordinatorAddress,
+		ethClient,
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError("failed to create rewards coordinator binding", err)
+	}
+
+	failed := 0
+	for i, solidityClaim := range claims {
+		claim, root, err := convertSolidityClaimToContractClaim(solidityClaim)
+		if err == nil {
+			err = verifyClaimAgainstDistributionRoot(ctx, rewardsCoordinator, claim, &root)
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("Claim %d for earner %s is invalid: %s\n", i, solidityClaim.EarnerLeaf.Earner.Hex(), err)
+			continue
+		}
+		fmt.Printf(
+			"Claim %d for earner %s is valid for root %s at index %d\n",
+			i,
+			claim.EarnerLeaf.Earner.Hex(),
+			hexutil.Encode(root[:]),
+			claim.RootIndex,
+		)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d claims are invalid", failed, len(claims))
+	}
+	return nil
+}
+
+// parseSolidityClaims reads claims in the format of formatProofForSolidity. The data can be a single
+// claim, an array of claims or a stream of claims.
+func parseSolidityClaims(data []byte) ([]*claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings, error) {
+	claims := make([]*claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings, 0)
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &claims); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			var claim claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings
+			err := decoder.Decode(&claim)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			claims = append(claims, &claim)
+		}
+	}
+	if len(claims) == 0 {
+		return nil, errors.New("no claims found")
+	}
+	return claims, nil
+}
+
+// convertSolidityClaimToContractClaim converts a claim in the format of formatProofForSolidity back into
+// the contract format. It also returns the root the claim was generated for.
+func convertSolidityClaimToContractClaim(
+	claim *claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings,
+) (rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim, [32]byte, error) {
+	var root [32]byte
+	rootBytes, err := hexutil.Decode(claim.Root)
+	if err != nil || len(rootBytes) != 32 {
+		return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{}, root, fmt.Errorf(
+			"invalid root: %s",
+			claim.Root,
+		)
+	}
+	copy(root[:], rootBytes)
+
+	var earnerTokenRoot [32]byte
+	earnerTokenRootBytes, err := hexutil.Decode(claim.EarnerLeaf.EarnerTokenRoot)
+	if err != nil || len(earnerTokenRootBytes) != 32 {
+		return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{}, root, fmt.Errorf(
+			"invalid earner token root: %s",
+			claim.EarnerLeaf.EarnerTokenRoot,
+		)
+	}
+	copy(earnerTokenRoot[:], earnerTokenRootBytes)
+
+	earnerTreeProof, err := hexutil.Decode(claim.EarnerTreeProof)
+	if err != nil {
+		return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{}, root, eigenSdkUtils.WrapError(
+			"invalid earner tree proof",
+			err,
+		)
+	}
+
+	tokenTreeProofs := make([][]byte, 0, len(claim.TokenTreeProofs))
+	for _, proof := range claim.TokenTreeProofs {
+		proofBytes, err := hexutil.Decode(proof)
+		if err != nil {
+			return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{}, root, eigenSdkUtils.WrapError(
+				"invalid token tree proof",
+				err,
+			)
+		}
+		tokenTreeProofs = append(tokenTreeProofs, proofBytes)
+	}
+
+	tokenLeaves := make([]rewardscoordinator.IRewardsCoordinatorTypesTokenTreeMerkleLeaf, 0, len(claim.TokenLeaves))
+	for _, leaf := range claim.TokenLeaves {
+		earnings, ok := new(big.Int).SetString(leaf.CumulativeEarnings, 10)
+		if !ok {
+			return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{}, root, fmt.Errorf(
+				"invalid cumulative earnings of token %s: %s",
+				leaf.Token.Hex(),
+				leaf.CumulativeEarnings,
+			)
+		}
+		tokenLeaves = append(tokenLeaves, rewardscoordinator.IRewardsCoordinatorTypesTokenTreeMerkleLeaf{
+			Token:              leaf.Token,
+			CumulativeEarnings: earnings,
+		})
+	}
+
+	return rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim{
+		RootIndex:       claim.RootIndex,
+		EarnerIndex:     claim.EarnerIndex,
+		EarnerTreeProof: earnerTreeProof,
+		EarnerLeaf: rewardscoordinator.IRewardsCoordinatorTypesEarnerTreeMerkleLeaf{
+			Earner:          claim.EarnerLeaf.Earner,
+			EarnerTokenRoot: earnerTokenRoot,
+		},
+		TokenIndices:    claim.TokenIndices,
+		TokenTreeProofs: tokenTreeProofs,
+		TokenLeaves:     tokenLeaves,
+	}, root, nil
+}
+
+// verifyClaimAgainstDistributionRoot verifies the proofs of the claim against the distribution root at the
+// root index of the claim. If expectedRoot is set, it must match the on-chain root.
+func verifyClaimAgainstDistributionRoot(
+	ctx context.Context,
+	reader distributionRootReader,
+	claim rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim,
+	expectedRoot *[32]byte,
+) error {
+	distributionRoot, err := reader.GetDistributionRootAtIndex(
+		&bind.CallOpts{Context: ctx},
+		new(big.Int).SetUint64(uint64(claim.RootIndex)),
+	)
+	if err != nil {
+		return eigenSdkUtils.WrapError(fmt.Sprintf("failed to get distribution root at index %d", claim.RootIndex), err)
+	}
+	if distributionRoot.Disabled {
+		return fmt.Errorf("distribution root at index %d is disabled", claim.RootIndex)
+	}
+	if expectedRoot != nil && *expectedRoot != distributionRoot.Root {
+		return fmt.Errorf(
+			"proof was generated for root %s but the on-chain root at index %d is %s",
+			hexutil.Encode(expectedRoot[:]),
+			claim.RootIndex,
+			hexutil.Encode(distributionRoot.Root[:]),
+		)
+	}
+	return verifyClaimProof(claim, distributionRoot.Root)
+}
+
+// verifyClaimProof verifies the earner tree proof and the token tree proofs of the claim the same way
+// RewardsCoordinator does when processing the claim
+func verifyClaimProof(claim rewardscoordinator.IRewardsCoordinatorTypesRewardsMerkleClaim, root [32]byte) error {
+	if len(claim.TokenIndices) != len(claim.TokenTreeProofs) || len(claim.TokenTreeProofs) != len(claim.TokenLeaves) {
+		return errors.New("token indices, token tree proofs and token leaves must have the same length")
+	}
+
+	for i, leaf := range claim.TokenLeaves {
+		if leaf.CumulativeEarnings == nil || leaf.CumulativeEarnings.Sign() < 0 {
+			return fmt.Errorf("invalid cumulative earnings of token %s", leaf.Token.Hex())
+		}
+		tokenLeafHash := crypto.Keccak256Hash(distribution.EncodeTokenLeaf(leaf.Token, leaf.CumulativeEarnings))
+		err := verifyMerkleInclusion(
+			claim.TokenTreeProofs[i],
+			claim.EarnerLeaf.EarnerTokenRoot,
+			tokenLeafHash,
+			claim.TokenIndices[i],
+		)
+		if err != nil {
+			return eigenSdkUtils.WrapError(fmt.Sprintf("invalid token tree proof for token %s", leaf.Token.Hex()), err)
+		}
+	}
+
+	earnerLeafHash := crypto.Keccak256Hash(
+		distribution.EncodeAccountLeaf(claim.EarnerLeaf.Earner, claim.EarnerLeaf.EarnerTokenRoot[:]),
+	)
+	err := verifyMerkleInclusion(claim.EarnerTreeProof, root, earnerLeafHash, claim.EarnerIndex)
+	if err != nil {
+		return eigenSdkUtils.WrapError(
+			fmt.Sprintf("invalid earner tree proof for earner %s", claim.EarnerLeaf.Earner.Hex()),
+			err,
+		)
+	}
+	return nil
+}
+
+// verifyMerkleInclusion checks that the leaf at index is included in the keccak256 merkle tree with the
+// given root, where the proof is the concatenation of the sibling hashes from the leaf to the root
+func verifyMerkleInclusion(proof []byte, root [32]byte, leaf gethcommon.Hash, index uint32) error {
+	if len(proof)%32 != 0 {
+		return fmt.Errorf("proof length must be a multiple of 32, got %d", len(proof))
+	}
+	depth := len(proof) / 32
+	if depth < 32 && uint64(index) >= uint64(1)<<depth {
+		return fmt.Errorf("leaf index %d is out of range for a proof of depth %d", index, depth)
+	}
+
+	computed := leaf
+	for i := 0; i < depth; i++ {
+		sibling := proof[i*32 : (i+1)*32]
+		if index%2 == 0 {
+			computed = crypto.Keccak256Hash(computed.Bytes(), sibling)
+		} else {
+			computed = crypto.Keccak256Hash(sibling, computed.Bytes())
+		}
+		index /= 2
+	}
+	if computed != root {
+		return fmt.Errorf("computed root %s does not match %s", computed.Hex(), hexutil.Encode(root[:]))
+	}
+	return nil
+}
+
+func readAndValidateVerifyProofConfig(cCtx *cli.Context, logger logging.Logger) (*VerifyProofConfig, error) {
+	args := cCtx.Args()
+	if args.Len() != 1 {
+		return nil, fmt.Errorf("accepts 1 arg, received %d", args.Len())
+	}
+
+	network := cCtx.String(flags.NetworkFlag.Name)
+	rpcUrl := cCtx.String(flags.ETHRpcUrlFlag.Name)
+	chainID := utils.NetworkNameToChainId(network)
+	logger.Debugf("Using chain ID: %s", chainID.String())
+
+	rewardsCoordinatorAddress := cCtx.String(RewardsCoordinatorAddressFlag.Name)
+	var err error
+	if common.IsEmptyString(rewardsCoordinatorAddress) {
+		rewardsCoordinatorAddress, err = common.GetRewardCoordinatorAddress(chainID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugf("Using Rewards Coordinator address: %s", rewardsCoordinatorAddress)
+
+	return &VerifyProofConfig{
+		Network:                   network,
+		RPCUrl:                    rpcUrl,
+		ChainID:                   chainID,
+		ProofFile:                 args.First(),
+		RewardsCoordinatorAddress: gethcommon.HexToAddress(rewardsCoordinatorAddress),
+	}, nil
+}
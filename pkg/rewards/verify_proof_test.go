@@ -0,0 +1,152 @@
+package rewards
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenlayer-cli/pkg/internal/testutils"
+
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/claimgen"
+	"github.com/Layr-Labs/eigenlayer-rewards-proofs/pkg/distribution"
+	rewardscoordinator "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RewardsCoordinator"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDistributionRootReader struct {
+	roots []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot
+}
+
+func (f *fakeDistributionRootReader) GetDistributionRootAtIndex(
+	opts *bind.CallOpts,
+	index *big.Int,
+) (rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot, error) {
+	return f.roots[index.Int64()], nil
+}
+
+// newTestSolidityClaim merklizes a distribution of three earners and returns the claim of the second
+// earner for two of its tokens in the format of formatProofForSolidity
+func newTestSolidityClaim(t *testing.T) (*claimgen.IRewardsCoordinatorRewardsMerkleClaimStrings, [32]byte) {
+	// The distribution requires earners and tokens in ascending order
+	earners := []gethcommon.Address{
+		gethcommon.HexToAddress("0x1000000000000000000000000000000000000001"),
+		gethcommon.HexToAddress("0x2000000000000000000000000000000000000002"),
+		gethcommon.HexToAddress("0x3000000000000000000000000000000000000003"),
+	}
+	tokens := []gethcommon.Address{
+		gethcommon.HexToAddress("0xa000000000000000000000000000000000000001"),
+		gethcommon.HexToAddress("0xb000000000000000000000000000000000000002"),
+		gethcommon.HexToAddress("0xc000000000000000000000000000000000000003"),
+	}
+	distro := distribution.NewDistribution()
+	for i, earner := range earners {
+		for j, token := range tokens {
+			assert.NoError(t, distro.Set(earner, token, big.NewInt(int64(1000*(i+1)+j))))
+		}
+	}
+
+	accountTree, tokenTrees, err := distro.Merklize()
+	assert.NoError(t, err)
+	proof, err := claimgen.GetProofForEarner(distro, 1, accountTree, tokenTrees, earners[1], tokens[1:])
+	assert.NoError(t, err)
+
+	var root [32]byte
+	copy(root[:], accountTree.Root())
+	return claimgen.FormatProofForSolidity(accountTree.Root(), proof), root
+}
+
+func TestVerifyClaimProof(t *testing.T) {
+	solidityClaim, root := newTestSolidityClaim(t)
+	reader := &fakeDistributionRootReader{
+		roots: []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+			{Root: [32]byte{1}},
+			{Root: root},
+		},
+	}
+
+	t.Run("valid claim", func(t *testing.T) {
+		claim, claimRoot, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		assert.Equal(t, root, claimRoot)
+		assert.NoError(t, verifyClaimAgainstDistributionRoot(context.Background(), reader, claim, &claimRoot))
+	})
+
+	t.Run("tampered cumulative earnings", func(t *testing.T) {
+		claim, _, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		claim.TokenLeaves[0].CumulativeEarnings = big.NewInt(1_000_000)
+		assert.ErrorContains(t, verifyClaimProof(claim, root), "invalid token tree proof")
+	})
+
+	t.Run("tampered earner", func(t *testing.T) {
+		claim, _, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		claim.EarnerLeaf.Earner = gethcommon.HexToAddress(testutils.GenerateRandomEthereumAddressString())
+		assert.ErrorContains(t, verifyClaimProof(claim, root), "invalid earner tree proof")
+	})
+
+	t.Run("wrong earner index", func(t *testing.T) {
+		claim, _, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		claim.EarnerIndex = 0
+		assert.Error(t, verifyClaimProof(claim, root))
+	})
+
+	t.Run("root in proof does not match the on-chain root", func(t *testing.T) {
+		claim, claimRoot, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		claim.RootIndex = 0
+		err = verifyClaimAgainstDistributionRoot(context.Background(), reader, claim, &claimRoot)
+		assert.ErrorContains(t, err, "on-chain root at index 0")
+	})
+
+	t.Run("disabled root", func(t *testing.T) {
+		claim, claimRoot, err := convertSolidityClaimToContractClaim(solidityClaim)
+		assert.NoError(t, err)
+		disabledReader := &fakeDistributionRootReader{
+			roots: []rewardscoordinator.IRewardsCoordinatorTypesDistributionRoot{
+				{},
+				{Root: root, Disabled: true},
+			},
+		}
+		err = verifyClaimAgainstDistributionRoot(context.Background(), disabledReader, claim, &claimRoot)
+		assert.ErrorContains(t, err, "disabled")
+	})
+}
+
+func TestParseSolidityClaims(t *testing.T) {
+	solidityClaim, _ := newTestSolidityClaim(t)
+	claimJson, err := json.MarshalIndent(solidityClaim, "", "  ")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		expectedCount int
+		expectError   bool
+	}{
+		{name: "single claim", data: claimJson, expectedCount: 1},
+		{name: "array of claims", data: []byte("[" + string(claimJson) + "," + string(claimJson) + "]"), expectedCount: 2},
+		{name: "stream of claims", data: []byte(string(claimJson) + "\n" + string(claimJson)), expectedCount: 2},
+		{name: "empty file", data: []byte(" \n"), expectError: true},
+		{name: "invalid json", data: []byte("{"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := parseSolidityClaims(tt.data)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, claims, tt.expectedCount)
+			assert.Equal(t, solidityClaim.EarnerLeaf.Earner, claims[0].EarnerLeaf.Earner)
+		})
+	}
+}